@@ -0,0 +1,103 @@
+package alertmanager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays are weekdays by their short lowercase names
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ActiveHours is a weekly time window provider receives events in e.g.
+// working hours
+type ActiveHours struct {
+	// days are weekdays window is active on, all days if it's empty
+	days map[time.Weekday]bool
+
+	// start and end are minutes since midnight, window passes midnight if
+	// end is before start
+	start int
+	end   int
+
+	location *time.Location
+}
+
+// parseActiveHours returns active hours of provider option e.g.
+// {days: [mon, fri], start: "09:00", end: "18:00", timezone: Europe/Berlin}
+func parseActiveHours(opt interface{}) (*ActiveHours, error) {
+	m, ok := opt.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("active hours must be an object")
+	}
+
+	hours := &ActiveHours{
+		days:     make(map[time.Weekday]bool),
+		location: time.UTC,
+	}
+
+	var err error
+	startOpt, _ := m["start"].(string)
+	if hours.start, err = parseClock(startOpt); err != nil {
+		return nil, err
+	}
+	endOpt, _ := m["end"].(string)
+	if hours.end, err = parseClock(endOpt); err != nil {
+		return nil, err
+	}
+
+	if tz, ok := m["timezone"].(string); ok && len(tz) > 0 {
+		if hours.location, err = time.LoadLocation(tz); err != nil {
+			return nil, err
+		}
+	}
+
+	days, _ := m["days"].([]interface{})
+	for _, d := range days {
+		name, _ := d.(string)
+		day, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %v", d)
+		}
+		hours.days[day] = true
+	}
+
+	return hours, nil
+}
+
+// parseClock returns minutes since midnight of time e.g. 09:30
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains returns true if t is within active hours
+func (h *ActiveHours) Contains(t time.Time) bool {
+	t = t.In(h.location)
+	minutes := t.Hour()*60 + t.Minute()
+
+	// window passing midnight belongs to the day it started on
+	day := t.Weekday()
+	if h.end <= h.start && minutes < h.end {
+		day = (day + 6) % 7
+	}
+	if len(h.days) > 0 && !h.days[day] {
+		return false
+	}
+
+	if h.start < h.end {
+		return minutes >= h.start && minutes < h.end
+	}
+	return minutes >= h.start || minutes < h.end
+}
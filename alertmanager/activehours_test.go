@@ -0,0 +1,79 @@
+package alertmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseActiveHours(t *testing.T) {
+	assert := assert.New(t)
+
+	hours, err := parseActiveHours(map[string]interface{}{
+		"days":     []interface{}{"mon", "Fri"},
+		"start":    "09:00",
+		"end":      "18:30",
+		"timezone": "Europe/Berlin",
+	})
+	assert.Nil(err)
+	assert.Equal(9*60, hours.start)
+	assert.Equal(18*60+30, hours.end)
+	assert.Len(hours.days, 2)
+	assert.Equal("Europe/Berlin", hours.location.String())
+
+	invalid := []interface{}{
+		"09:00-18:00",
+		map[string]interface{}{"start": "9am", "end": "18:00"},
+		map[string]interface{}{"start": "09:00"},
+		map[string]interface{}{
+			"start": "09:00",
+			"end":   "18:00",
+			"days":  []interface{}{"monday"},
+		},
+		map[string]interface{}{
+			"start":    "09:00",
+			"end":      "18:00",
+			"timezone": "Mars/Olympus",
+		},
+	}
+	for _, opt := range invalid {
+		_, err := parseActiveHours(opt)
+		assert.NotNil(err)
+	}
+}
+
+func TestActiveHoursContains(t *testing.T) {
+	assert := assert.New(t)
+
+	hours, _ := parseActiveHours(map[string]interface{}{
+		"days":  []interface{}{"mon", "tue", "wed", "thu", "fri"},
+		"start": "09:00",
+		"end":   "18:00",
+	})
+
+	// 2024-06-03 is a monday
+	assert.True(hours.Contains(
+		time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)))
+	assert.False(hours.Contains(
+		time.Date(2024, 6, 3, 18, 0, 0, 0, time.UTC)))
+	assert.False(hours.Contains(
+		time.Date(2024, 6, 3, 8, 59, 0, 0, time.UTC)))
+	assert.False(hours.Contains(
+		time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)))
+
+	// night shift from friday to saturday
+	night, _ := parseActiveHours(map[string]interface{}{
+		"days":  []interface{}{"fri"},
+		"start": "22:00",
+		"end":   "06:00",
+	})
+	assert.True(night.Contains(
+		time.Date(2024, 6, 7, 23, 0, 0, 0, time.UTC)))
+	assert.True(night.Contains(
+		time.Date(2024, 6, 8, 5, 0, 0, 0, time.UTC)))
+	assert.False(night.Contains(
+		time.Date(2024, 6, 7, 5, 0, 0, 0, time.UTC)))
+	assert.False(night.Contains(
+		time.Date(2024, 6, 8, 23, 0, 0, 0, time.UTC)))
+}
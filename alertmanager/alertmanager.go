@@ -1,8 +1,14 @@
 package alertmanager
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/abahmed/kwatch/alertmanager/dingtalk"
 	"github.com/abahmed/kwatch/alertmanager/discord"
@@ -19,13 +25,114 @@ import (
 	"github.com/abahmed/kwatch/alertmanager/telegram"
 	"github.com/abahmed/kwatch/alertmanager/webhook"
 	"github.com/abahmed/kwatch/alertmanager/zenduty"
+	"github.com/abahmed/kwatch/audit"
 	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/metrics"
+	"github.com/abahmed/kwatch/severity"
 	"github.com/sirupsen/logrus"
 )
 
 type AlertManager struct {
 	providers []Provider
+
+	// sections are configured message sections of providers in order
+	sections map[Provider][]string
+
+	// targets are non-secret identifiers of providers destinations used in
+	// audit records e.g. channel or webhook host
+	targets map[Provider]string
+
+	// optOuts are kinds of messages providers opted out of
+	optOuts map[Provider]map[string]bool
+
+	// minSeverities are ranks of lowest severity of events sent to
+	// providers
+	minSeverities map[Provider]int
+
+	// activeHours are time windows providers receive events in
+	activeHours map[Provider]*ActiveHours
+
+	// reasons are allow or forbid lists of reasons of events sent to
+	// providers
+	reasons map[Provider]*ReasonFilter
+
+	// payloadLimits are max payload sizes of providers and strategies
+	// truncating messages exceeding them
+	payloadLimits map[Provider]*PayloadLimit
+
+	// fallbacks are names of providers notifications are sent through in
+	// order if sending through provider fails
+	fallbacks map[Provider][]string
+
+	// retries are numbers of times failed sends of providers are retried
+	// before notifications are sent through fallback providers
+	retries map[Provider]int
+
+	// retryBackoff is wait before first retry, it's doubled for next ones
+	retryBackoff time.Duration
+
+	// severityMapper adjusts severity of events by namespace, if it's set
+	severityMapper *severity.Mapper
+
+	// failures holds number of consecutive failed sends of providers
+	failures   map[string]int
+	failuresMu sync.RWMutex
+
+	// failureThreshold is number of consecutive failed sends of a provider
+	// after which healthy providers are notified
+	failureThreshold int
+
+	auditor *audit.Auditor
+
+	// dryRun logs notifications instead of sending them
+	dryRun      bool
+	clusterName string
+
+	// isLeader returns false if another instance is responsible for sending
+	// alerts
+	isLeader func() bool
+
+	// router picks providers and sections of events, if it's set
+	router Router
+
+	// queues hold pending sends of providers when workers are started, so
+	// a slow provider doesn't block event processing or other providers
+	queues   map[Provider]chan *Notification
+	queuesMu sync.RWMutex
+	workers  sync.WaitGroup
+
+	// abort is closed to stop workers when queues aren't drained in time
+	abort chan struct{}
+
+	// stopped is true once kwatch is shutting down, new notifications are
+	// dropped
+	stopped bool
+}
+
+const (
+	// defaultRetries is number of times failed sends are retried if
+	// provider doesn't configure it, failed sends aren't retried by default
+	defaultRetries = 0
+
+	// defaultRetryBackoff is wait before first retry of failed send
+	defaultRetryBackoff = time.Second
+)
+
+const (
+	// MessageStartup is kind of message sent when kwatch starts
+	MessageStartup = "startup"
+
+	// MessageUpdate is kind of message sent when a newer version is
+	// available
+	MessageUpdate = "update"
+)
+
+// messageOptOuts are provider options opting out of message kinds
+var messageOptOuts = map[string]string{
+	MessageStartup: "disableStartupMessage",
+	MessageUpdate:  "disableUpdateMessage",
 }
 
 // Provider interface
@@ -35,11 +142,59 @@ type Provider interface {
 	SendMessage(string) error
 }
 
+// Route is how an event is sent to providers
+type Route struct {
+	// Drop if set to true, event isn't sent
+	Drop bool
+
+	// Providers are names of providers event is sent to, if it's empty
+	// event is sent to all providers
+	Providers []string
+
+	// Sections overrides configured message sections of providers
+	Sections []string
+}
+
+// Router returns route of events, nil route sends event to all providers
+type Router interface {
+	Route(*event.Event) *Route
+}
+
+// hasProvider returns true if event is sent to provider
+func (r *Route) hasProvider(prv Provider) bool {
+	if len(r.Providers) == 0 {
+		return true
+	}
+
+	for _, name := range r.Providers {
+		if strings.EqualFold(name, prv.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
 // Init initializes AlertManager with provided config
 func (a *AlertManager) Init(
 	alertCfg map[string]map[string]interface{},
 	appCfg *config.App) {
 	a.providers = make([]Provider, 0)
+	a.sections = make(map[Provider][]string)
+	a.targets = make(map[Provider]string)
+	a.optOuts = make(map[Provider]map[string]bool)
+	a.minSeverities = make(map[Provider]int)
+	a.activeHours = make(map[Provider]*ActiveHours)
+	a.reasons = make(map[Provider]*ReasonFilter)
+	a.payloadLimits = make(map[Provider]*PayloadLimit)
+	a.fallbacks = make(map[Provider][]string)
+	a.retries = make(map[Provider]int)
+	a.retryBackoff = defaultRetryBackoff
+	a.failures = make(map[string]int)
+	if appCfg != nil {
+		a.failureThreshold = appCfg.ProviderFailureThreshold
+		a.dryRun = appCfg.DryRun
+		a.clusterName = appCfg.ClusterName
+	}
 	for k, v := range alertCfg {
 		lowerCaseKey := strings.ToLower(k)
 		var pvdr Provider = nil
@@ -77,35 +232,678 @@ func (a *AlertManager) Init(
 
 		if !reflect.ValueOf(pvdr).IsNil() {
 			a.providers = append(a.providers, pvdr)
+
+			if sections := getSections(v); len(sections) > 0 {
+				a.sections[pvdr] = sections
+			}
+
+			a.targets[pvdr] = getTarget(v)
+
+			if optOuts := getOptOuts(v); len(optOuts) > 0 {
+				a.optOuts[pvdr] = optOuts
+			}
+
+			if minSeverity, ok := v["minSeverity"].(string); ok {
+				a.minSeverities[pvdr] = severity.Rank(minSeverity)
+			}
+
+			if opt, ok := v["activeHours"]; ok {
+				hours, err := parseActiveHours(opt)
+				if err != nil {
+					logrus.WithField("provider", pvdr.Name()).
+						WithError(err).
+						Error("invalid active hours, events are always sent")
+				} else {
+					a.activeHours[pvdr] = hours
+				}
+			}
+
+			if filter := parseReasonFilter(v); filter != nil {
+				if len(filter.allowed) > 0 && len(filter.forbidden) > 0 {
+					logrus.WithField("provider", pvdr.Name()).Error(
+						"Either allowed or forbidden reasons must be set. " +
+							"Can't set both")
+				}
+				a.reasons[pvdr] = filter
+			}
+
+			limit, err := parsePayloadLimit(v)
+			if err != nil {
+				logrus.WithField("provider", pvdr.Name()).
+					WithError(err).
+					Error("invalid payload limit, messages aren't truncated")
+			} else if limit != nil {
+				a.payloadLimits[pvdr] = limit
+			}
+
+			if fallbacks := getFallbacks(v); len(fallbacks) > 0 {
+				a.fallbacks[pvdr] = fallbacks
+			}
+
+			retries, err := getRetries(v)
+			if err != nil {
+				logrus.WithField("provider", pvdr.Name()).
+					WithError(err).
+					Error("invalid retries, default is used")
+			}
+			a.retries[pvdr] = retries
+		}
+	}
+
+	for prv, fallbacks := range a.fallbacks {
+		for _, name := range fallbacks {
+			if a.findProvider(name) == nil {
+				logrus.WithFields(logrus.Fields{
+					"provider": prv.Name(),
+					"fallback": name,
+				}).Warn("fallback provider isn't configured")
+			}
 		}
 	}
 }
 
+// AddProvider adds provider to configured ones
+func (a *AlertManager) AddProvider(prv Provider) {
+	a.providers = append(a.providers, prv)
+}
+
+// SetAuditor sets auditor that records every outgoing notification
+func (a *AlertManager) SetAuditor(auditor *audit.Auditor) {
+	a.auditor = auditor
+}
+
+// SetLeaderCheck sets function that reports whether this instance is the
+// leader, alerts are only sent by leader
+func (a *AlertManager) SetLeaderCheck(isLeader func() bool) {
+	a.isLeader = isLeader
+}
+
+// SetSeverityMapper sets mapper that adjusts severity of events by their
+// namespace before they're routed
+func (a *AlertManager) SetSeverityMapper(mapper *severity.Mapper) {
+	a.severityMapper = mapper
+}
+
+// SetRouter sets router that picks providers and sections of events
+func (a *AlertManager) SetRouter(router Router) {
+	a.router = router
+}
+
 // Notify sends string msg to all providers
 func (a *AlertManager) Notify(msg string) {
+	a.NotifyKind("", msg)
+}
+
+// NotifyKind sends string msg of kind e.g. MessageStartup to providers
+// that didn't opt out of it
+func (a *AlertManager) NotifyKind(kind string, msg string) {
+	a.notify(kind, &Route{}, msg)
+}
+
+// NotifyProviders sends string msg to providers with given names, or all
+// providers if names is empty
+func (a *AlertManager) NotifyProviders(names []string, msg string) {
+	a.notify("", &Route{Providers: names}, msg)
+}
+
+// notify sends string msg of kind to providers of route that didn't opt
+// out of it
+func (a *AlertManager) notify(kind string, route *Route, msg string) {
+	if !a.shouldSend() {
+		logrus.Debugf("skipping message as instance isn't leader: %s", msg)
+		return
+	}
+
 	logrus.Infof("sending message: %s", msg)
 
 	for _, prv := range a.providers {
-		if err := prv.SendMessage(msg); err != nil {
-			logrus.Errorf(
-				"failed to send msg with %s: %s",
-				prv.Name(),
-				err.Error())
+		if !route.hasProvider(prv) {
+			continue
+		}
+
+		if a.optOuts[prv][kind] {
+			logrus.WithFields(logrus.Fields{
+				"provider": prv.Name(),
+				"kind":     kind,
+			}).Debug("skipping message as provider opted out of it")
+			continue
 		}
+
+		a.dispatch(prv, a.newMessageNotification(prv, msg))
+	}
+}
+
+// newMessageNotification returns notification that sends msg to provider,
+// or its fallback providers if it fails
+func (a *AlertManager) newMessageNotification(
+	prv Provider,
+	msg string) *Notification {
+	return &Notification{
+		Provider: prv.Name(),
+		Msg:      msg,
+		send: func() {
+			if a.dryRun {
+				a.logDryRun(prv, a.payloadLimits[prv].truncateMessage(msg))
+				return
+			}
+
+			err := a.sendMessage(prv, msg)
+			if err == nil {
+				return
+			}
+
+			logrus.WithField("provider", prv.Name()).
+				WithError(err).
+				Error("failed to send msg")
+			a.fallback(prv, func(fallbackPrv Provider) error {
+				return a.sendMessage(fallbackPrv, msg)
+			})
+		},
 	}
 }
 
+// sendMessage sends msg to provider truncated to its payload limit
+func (a *AlertManager) sendMessage(prv Provider, msg string) error {
+	msg = a.payloadLimits[prv].truncateMessage(msg)
+	return a.send(
+		prv,
+		[]byte(msg),
+		func() error { return prv.SendMessage(msg) })
+}
+
 // NotifyEvent sends event to all providers
 func (a *AlertManager) NotifyEvent(event event.Event) {
-	logrus.Infof("sending event: %+v", event)
+	if !a.shouldSend() {
+		logrus.WithFields(logrus.Fields{
+			"namespace": event.Namespace,
+			"pod":       event.PodName,
+		}).Debug("skipping event as instance isn't leader")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": event.Namespace,
+		"pod":       event.PodName,
+		"container": event.ContainerName,
+		"reason":    event.Reason,
+	}).Info("sending event")
+
+	event.Severity = a.severityMapper.Apply(event.Namespace, event.Severity)
+
+	route := a.route(&event)
+	if route.Drop {
+		logrus.WithFields(logrus.Fields{
+			"namespace": event.Namespace,
+			"pod":       event.PodName,
+		}).Info("dropping event by routing rule")
+		return
+	}
 
 	for _, prv := range a.providers {
-		if err := prv.SendEvent(&event); err != nil {
-			logrus.Errorf(
-				"failed to send event with %s: %s",
-				prv.Name(),
-				err.Error(),
-			)
+		if !route.hasProvider(prv) || !a.accepts(prv, &event) {
+			continue
 		}
+
+		a.dispatch(prv, a.newEventNotification(prv, &event, route.Sections))
+	}
+}
+
+// accepts returns true if event is within active hours, reasons and min
+// severity of provider
+func (a *AlertManager) accepts(prv Provider, event *event.Event) bool {
+	hours := a.activeHours[prv]
+	if hours != nil && !hours.Contains(time.Now()) {
+		logrus.WithField("provider", prv.Name()).
+			Debug("skipping event outside active hours of provider")
+		return false
+	}
+
+	if !a.reasons[prv].Allows(event.Reason) {
+		logrus.WithFields(logrus.Fields{
+			"provider": prv.Name(),
+			"reason":   event.Reason,
+		}).Debug("skipping event by reasons of provider")
+		return false
+	}
+
+	if severity.Rank(event.Severity) < a.minSeverities[prv] {
+		logrus.WithFields(logrus.Fields{
+			"provider": prv.Name(),
+			"severity": event.Severity,
+		}).Debug("skipping event below min severity of provider")
+		return false
+	}
+
+	return true
+}
+
+// NotifyEventProviders sends event to providers with given names, or all
+// providers if names is empty, regardless of routes and provider windows
+func (a *AlertManager) NotifyEventProviders(
+	names []string,
+	event event.Event) {
+	if !a.shouldSend() {
+		return
 	}
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": event.Namespace,
+		"pod":       event.PodName,
+		"providers": names,
+	}).Info("sending event to providers")
+
+	route := &Route{Providers: names}
+	for _, prv := range a.providers {
+		if route.hasProvider(prv) {
+			a.dispatch(prv, a.newEventNotification(prv, &event, nil))
+		}
+	}
+}
+
+// route returns route of event, by default it's sent to all providers
+func (a *AlertManager) route(event *event.Event) *Route {
+	if a.router == nil {
+		return &Route{}
+	}
+
+	if route := a.router.Route(event); route != nil {
+		return route
+	}
+	return &Route{}
+}
+
+// newEventNotification returns notification that sends event to provider
+// with given sections, or its configured ones if sections is empty, or to
+// its fallback providers if it fails
+func (a *AlertManager) newEventNotification(
+	prv Provider,
+	event *event.Event,
+	sections []string) *Notification {
+	return &Notification{
+		Provider: prv.Name(),
+		Event:    event,
+		send: func() {
+			if a.dryRun {
+				ev := a.providerEvent(prv, event, sections)
+				a.logDryRun(
+					prv,
+					ev.FormatText(ev.GetClusterName(a.clusterName), ""))
+				return
+			}
+
+			err := a.sendEvent(prv, event, sections)
+			if err == nil {
+				return
+			}
+
+			logrus.WithFields(logrus.Fields{
+				"provider":  prv.Name(),
+				"namespace": event.Namespace,
+				"pod":       event.PodName,
+			}).WithError(err).Error("failed to send event")
+			a.fallback(prv, func(fallbackPrv Provider) error {
+				// fallback providers keep their own filters, event is
+				// skipped by ones that wouldn't receive it
+				if !a.accepts(fallbackPrv, event) {
+					return errSkipped
+				}
+				return a.sendEvent(fallbackPrv, event, sections)
+			})
+		},
+	}
+}
+
+// sendEvent sends event to provider with given sections, or its configured
+// ones if sections is empty
+func (a *AlertManager) sendEvent(
+	prv Provider,
+	event *event.Event,
+	sections []string) error {
+	ev := a.providerEvent(prv, event, sections)
+	payload, _ := json.Marshal(ev)
+	return a.send(
+		prv,
+		payload,
+		func() error { return prv.SendEvent(ev) })
+}
+
+// providerEvent returns event with given sections, or configured sections
+// of provider if sections is empty, truncated to its payload limit
+func (a *AlertManager) providerEvent(
+	prv Provider,
+	event *event.Event,
+	sections []string) *event.Event {
+	ev := event
+	if len(sections) > 0 {
+		ev = event.WithSections(sections)
+	} else if sections, ok := a.sections[prv]; ok {
+		ev = event.WithSections(sections)
+	}
+	return a.payloadLimits[prv].truncateEvent(ev)
+}
+
+// errSkipped is returned by fallback send functions if fallback provider
+// doesn't receive notification e.g. by its reasons
+var errSkipped = errors.New("skipped by provider filters")
+
+// fallback sends notification using sendFunc through fallback providers of
+// failed provider in order until it's sent through one of them
+func (a *AlertManager) fallback(
+	failedPrv Provider,
+	sendFunc func(Provider) error) {
+	fallbacks := a.fallbacks[failedPrv]
+	for _, name := range fallbacks {
+		prv := a.findProvider(name)
+		if prv == nil || prv == failedPrv {
+			continue
+		}
+
+		err := sendFunc(prv)
+		if err == nil {
+			logrus.WithFields(logrus.Fields{
+				"provider": failedPrv.Name(),
+				"fallback": prv.Name(),
+			}).Info("sent notification through fallback provider")
+			return
+		}
+		if errors.Is(err, errSkipped) {
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"provider": failedPrv.Name(),
+			"fallback": prv.Name(),
+		}).WithError(err).Error("failed to send through fallback provider")
+	}
+
+	if len(fallbacks) > 0 {
+		logrus.WithField("provider", failedPrv.Name()).
+			Error("notification wasn't sent through any fallback provider")
+	}
+}
+
+// findProvider returns configured provider with given name ignoring case
+// as names of routes, or nil
+func (a *AlertManager) findProvider(name string) Provider {
+	route := &Route{Providers: []string{name}}
+	for _, prv := range a.providers {
+		if route.hasProvider(prv) {
+			return prv
+		}
+	}
+	return nil
+}
+
+// logDryRun logs rendered message instead of sending it to provider
+func (a *AlertManager) logDryRun(prv Provider, msg string) {
+	logrus.WithFields(logrus.Fields{
+		"provider": prv.Name(),
+		"dryRun":   true,
+	}).Infof("not sending message in dry run mode:\n%s", msg)
+}
+
+// shouldSend returns true if alerts should be sent by this instance
+func (a *AlertManager) shouldSend() bool {
+	return a.isLeader == nil || a.isLeader()
+}
+
+// ProviderStatus is delivery health of a provider
+type ProviderStatus struct {
+	Name    string
+	Failing bool
+}
+
+// ProviderStatuses returns delivery health of providers, a provider is
+// failing if its last send has failed
+func (a *AlertManager) ProviderStatuses() []ProviderStatus {
+	a.failuresMu.RLock()
+	defer a.failuresMu.RUnlock()
+
+	statuses := make([]ProviderStatus, 0, len(a.providers))
+	for _, prv := range a.providers {
+		statuses = append(statuses, ProviderStatus{
+			Name:    prv.Name(),
+			Failing: a.failures[prv.Name()] > 0,
+		})
+	}
+	return statuses
+}
+
+// Ready returns true if at least one provider is configured and its last
+// send, if any, has succeeded
+func (a *AlertManager) Ready() bool {
+	a.failuresMu.RLock()
+	defer a.failuresMu.RUnlock()
+
+	for _, prv := range a.providers {
+		if a.failures[prv.Name()] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// send calls sendFunc of provider and records its status, metrics and audit
+// record
+func (a *AlertManager) send(
+	prv Provider,
+	payload []byte,
+	sendFunc func() error) error {
+	start := time.Now()
+	err := a.retry(prv, sendFunc)
+	latency := time.Since(start)
+	metrics.AlertSendDuration.
+		WithLabelValues(prv.Name()).
+		Observe(latency.Seconds())
+
+	if a.auditor.Enabled() {
+		record := &audit.Record{
+			Time:        start,
+			Provider:    prv.Name(),
+			Target:      a.targets[prv],
+			Status:      audit.StatusSent,
+			LatencyMs:   latency.Milliseconds(),
+			PayloadHash: audit.HashPayload(payload),
+		}
+		if err != nil {
+			record.Status = audit.StatusFailed
+			record.Error = err.Error()
+		}
+		a.auditor.Record(record)
+	}
+
+	failures := a.recordResult(prv, err)
+	if err != nil {
+		metrics.AlertSendFailures.WithLabelValues(prv.Name()).Inc()
+
+		if a.failureThreshold > 0 && failures == a.failureThreshold {
+			a.notifyProviderFailure(prv, failures, err)
+		}
+		return err
+	}
+
+	metrics.AlertsSent.WithLabelValues(prv.Name()).Inc()
+	return nil
+}
+
+// retry calls sendFunc until it succeeds or retries of provider are used,
+// waiting for doubled backoff before each retry
+func (a *AlertManager) retry(prv Provider, sendFunc func() error) error {
+	err := sendFunc()
+	if err == nil || a.retries[prv] == 0 {
+		return err
+	}
+
+	// retries are stopped if workers are aborted on shutdown
+	a.queuesMu.RLock()
+	abort := a.abort
+	a.queuesMu.RUnlock()
+
+	backoff := a.retryBackoff
+	for attempt := 1; err != nil && attempt <= a.retries[prv]; attempt++ {
+		logrus.WithFields(logrus.Fields{
+			"provider": prv.Name(),
+			"attempt":  attempt,
+		}).WithError(err).Warn("retrying failed send")
+
+		select {
+		case <-time.After(backoff):
+		case <-abort:
+			return err
+		}
+		backoff *= 2
+
+		err = sendFunc()
+	}
+	return err
+}
+
+// recordResult updates and returns number of consecutive failed sends of
+// provider
+func (a *AlertManager) recordResult(prv Provider, err error) int {
+	a.failuresMu.Lock()
+	defer a.failuresMu.Unlock()
+
+	if a.failures == nil {
+		a.failures = make(map[string]int)
+	}
+
+	if err != nil {
+		a.failures[prv.Name()]++
+	} else {
+		a.failures[prv.Name()] = 0
+	}
+
+	failures := a.failures[prv.Name()]
+	metrics.ProviderConsecutiveFailures.
+		WithLabelValues(prv.Name()).
+		Set(float64(failures))
+
+	return failures
+}
+
+// notifyProviderFailure sends message about failing provider to healthy
+// providers
+func (a *AlertManager) notifyProviderFailure(
+	failedPrv Provider,
+	failures int,
+	err error) {
+	msg := fmt.Sprintf(
+		constant.ProviderFailureMsg,
+		failures,
+		failedPrv.Name(),
+		err.Error())
+
+	logrus.WithField("provider", failedPrv.Name()).Warn(msg)
+
+	for _, prv := range a.providers {
+		if prv == failedPrv {
+			continue
+		}
+
+		a.failuresMu.RLock()
+		healthy := a.failures[prv.Name()] == 0
+		a.failuresMu.RUnlock()
+		if !healthy {
+			continue
+		}
+
+		a.dispatch(prv, a.newMessageNotification(prv, msg))
+	}
+}
+
+// getSections returns list of message sections configured for provider
+func getSections(providerCfg map[string]interface{}) []string {
+	items, ok := providerCfg["sections"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	sections := make([]string, 0, len(items))
+	for _, item := range items {
+		if section, ok := item.(string); ok {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// getFallbacks returns names of fallback providers of provider in order
+func getFallbacks(providerCfg map[string]interface{}) []string {
+	items, ok := providerCfg["fallback"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	fallbacks := make([]string, 0, len(items))
+	for _, item := range items {
+		if name, ok := item.(string); ok && len(name) > 0 {
+			fallbacks = append(fallbacks, name)
+		}
+	}
+	return fallbacks
+}
+
+// getRetries returns number of times failed sends of provider are retried,
+// or default one if it isn't configured or it's invalid
+func getRetries(providerCfg map[string]interface{}) (int, error) {
+	var retries int
+	switch value := providerCfg["retries"].(type) {
+	case nil:
+		return defaultRetries, nil
+	case int:
+		retries = value
+	case float64:
+		retries = int(value)
+	default:
+		return defaultRetries, errors.New("retries must be a number")
+	}
+	if retries < 0 {
+		return defaultRetries, errors.New("retries must not be negative")
+	}
+	return retries, nil
+}
+
+// getOptOuts returns kinds of messages provider opted out of
+func getOptOuts(providerCfg map[string]interface{}) map[string]bool {
+	optOuts := make(map[string]bool)
+	for kind, option := range messageOptOuts {
+		if disabled, ok := providerCfg[option].(bool); ok && disabled {
+			optOuts[kind] = true
+		}
+	}
+	return optOuts
+}
+
+// getTarget returns non-secret identifier of provider destination, it's
+// either configured channel, chat or recipient, or host of configured URL
+func getTarget(providerCfg map[string]interface{}) string {
+	for _, key := range []string{
+		"channel",
+		"channelId",
+		"chatId",
+		"internalRoomId",
+		"to",
+	} {
+		if target, ok := providerCfg[key].(string); ok && len(target) > 0 {
+			return target
+		}
+	}
+
+	for _, key := range []string{"webhook", "url", "homeServer"} {
+		rawURL, ok := providerCfg[key].(string)
+		if !ok || len(rawURL) == 0 {
+			continue
+		}
+
+		if u, err := url.Parse(rawURL); err == nil && len(u.Host) > 0 {
+			return u.Host
+		}
+	}
+
+	if host, ok := providerCfg["host"].(string); ok {
+		return host
+	}
+
+	return ""
 }
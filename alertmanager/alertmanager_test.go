@@ -1,11 +1,19 @@
 package alertmanager
 
 import (
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/abahmed/kwatch/audit"
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/metrics"
+	"github.com/abahmed/kwatch/severity"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -125,3 +133,449 @@ func TestSendProvidersMsg(t *testing.T) {
 	)
 	alertmanager.Notify("hello world!")
 }
+
+type fakeSectionsProvider struct {
+	sections []string
+}
+
+func (p *fakeSectionsProvider) SendMessage(msg string) error {
+	return nil
+}
+func (p *fakeSectionsProvider) SendEvent(evt *event.Event) error {
+	p.sections = evt.SectionNames()
+	return nil
+}
+func (p *fakeSectionsProvider) Name() string {
+	return "Sections"
+}
+
+func TestSendProvidersEventSections(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		[]string{"logs", "events"},
+		getSections(map[string]interface{}{
+			"sections": []interface{}{"logs", "events"},
+		}))
+	assert.Nil(getSections(map[string]interface{}{}))
+
+	withSections := &fakeSectionsProvider{}
+	withoutSections := &fakeSectionsProvider{}
+
+	alertmanager := AlertManager{
+		providers: []Provider{withSections, withoutSections},
+		sections: map[Provider][]string{
+			withSections: {"logs", "unknown", "Events"},
+		},
+	}
+	alertmanager.NotifyEvent(event.Event{})
+
+	assert.Equal([]string{"logs", "events"}, withSections.sections)
+	assert.Equal(event.DefaultSections, withoutSections.sections)
+}
+
+func TestSendMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	okPrv := &fakeProvider{}
+	errPrv := &fakeProviderWithError{}
+
+	sent := testutil.ToFloat64(metrics.AlertsSent.WithLabelValues("Slack"))
+	failed := testutil.ToFloat64(
+		metrics.AlertSendFailures.WithLabelValues("Slack Error"))
+
+	alertmanager := AlertManager{}
+	assert.Nil(alertmanager.send(
+		okPrv,
+		[]byte("test"),
+		func() error { return okPrv.SendMessage("test") }))
+	assert.NotNil(alertmanager.send(
+		errPrv,
+		[]byte("test"),
+		func() error { return errPrv.SendMessage("test") }))
+
+	assert.Equal(
+		sent+1,
+		testutil.ToFloat64(metrics.AlertsSent.WithLabelValues("Slack")))
+	assert.Equal(
+		failed+1,
+		testutil.ToFloat64(
+			metrics.AlertSendFailures.WithLabelValues("Slack Error")))
+}
+
+func TestReady(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+	assert.False(alertmanager.Ready())
+
+	alertmanager.providers = []Provider{&fakeProviderWithError{}}
+	assert.True(alertmanager.Ready())
+
+	alertmanager.Notify("test")
+	assert.False(alertmanager.Ready())
+
+	alertmanager.providers = append(alertmanager.providers, &fakeProvider{})
+	alertmanager.Notify("test")
+	assert.True(alertmanager.Ready())
+}
+
+func TestGetTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("#alerts", getTarget(map[string]interface{}{
+		"webhook": "https://hooks.slack.com/services/secret",
+		"channel": "#alerts",
+	}))
+	assert.Equal("hooks.slack.com", getTarget(map[string]interface{}{
+		"webhook": "https://hooks.slack.com/services/secret",
+	}))
+	assert.Equal("smtp.example.com", getTarget(map[string]interface{}{
+		"host": "smtp.example.com",
+	}))
+	assert.Empty(getTarget(map[string]interface{}{}))
+}
+
+func TestSendAudit(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+	alertmanager.providers = []Provider{&fakeProviderWithError{}}
+	alertmanager.SetAuditor(
+		audit.NewAuditor(&config.Audit{Enabled: true, Path: path}))
+
+	alertmanager.Notify("test")
+
+	data, err := os.ReadFile(path)
+	assert.Nil(err)
+
+	var record audit.Record
+	assert.Nil(json.Unmarshal(data, &record))
+	assert.Equal("Slack Error", record.Provider)
+	assert.Equal(audit.StatusFailed, record.Status)
+	assert.Equal(audit.HashPayload([]byte("test")), record.PayloadHash)
+}
+
+type recordingProvider struct {
+	messages []string
+	events   []*event.Event
+}
+
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+func (p *recordingProvider) SendEvent(evt *event.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+func (p *recordingProvider) Name() string {
+	return "Recording"
+}
+
+func TestNotifyProviderFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, &config.App{ProviderFailureThreshold: 2})
+
+	okPrv := &recordingProvider{}
+	alertmanager.providers = []Provider{&fakeProviderWithError{}, okPrv}
+
+	alertmanager.Notify("first")
+	assert.Equal([]string{"first"}, okPrv.messages)
+
+	alertmanager.Notify("second")
+	assert.Len(okPrv.messages, 3)
+	assert.Contains(okPrv.messages[1], "2 consecutive notifications")
+	assert.Contains(okPrv.messages[1], "Slack Error")
+
+	// failure message is sent once when threshold is reached
+	alertmanager.Notify("third")
+	assert.Len(okPrv.messages, 4)
+}
+
+func TestNotifyNotLeader(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+
+	prv := &recordingProvider{}
+	alertmanager.providers = []Provider{prv}
+
+	isLeader := false
+	alertmanager.SetLeaderCheck(func() bool { return isLeader })
+
+	alertmanager.Notify("test")
+	assert.Len(prv.messages, 0)
+
+	isLeader = true
+	alertmanager.Notify("test")
+	assert.Len(prv.messages, 1)
+}
+
+func TestNotifyKindOptOut(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		map[string]bool{MessageUpdate: true},
+		getOptOuts(map[string]interface{}{
+			"disableStartupMessage": false,
+			"disableUpdateMessage":  true,
+		}))
+	assert.Empty(getOptOuts(map[string]interface{}{}))
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+
+	pager := &recordingProvider{}
+	chat := &recordingProvider{}
+	alertmanager.providers = []Provider{pager, chat}
+	alertmanager.optOuts[pager] = map[string]bool{
+		MessageStartup: true,
+		MessageUpdate:  true,
+	}
+
+	alertmanager.NotifyKind(MessageStartup, "started")
+	alertmanager.NotifyKind(MessageUpdate, "update")
+	assert.Len(pager.messages, 0)
+	assert.Len(chat.messages, 2)
+
+	alertmanager.Notify("shutdown")
+	assert.Len(pager.messages, 1)
+}
+
+func TestNotifyMinSeverity(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+
+	pager := &recordingProvider{}
+	chat := &recordingProvider{}
+	alertmanager.providers = []Provider{pager, chat}
+	alertmanager.minSeverities[pager] = severity.Rank("warning")
+	alertmanager.SetSeverityMapper(severity.NewMapper(&config.Severity{
+		Namespaces: []config.NamespaceSeverity{
+			{Namespace: "prod-*", Min: "warning"},
+		},
+	}))
+
+	alertmanager.NotifyEvent(event.Event{Namespace: "dev"})
+	alertmanager.NotifyEvent(event.Event{Namespace: "prod-api"})
+	assert.Len(pager.events, 1)
+	assert.Len(chat.events, 2)
+}
+
+func TestNotifyActiveHours(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+
+	chat := &recordingProvider{}
+	email := &recordingProvider{}
+	alertmanager.providers = []Provider{chat, email}
+
+	// window is never active as none of its days are enabled
+	alertmanager.activeHours[chat] = &ActiveHours{
+		days:     map[time.Weekday]bool{time.Monday: false},
+		location: time.UTC,
+	}
+
+	alertmanager.NotifyEvent(event.Event{Namespace: "default"})
+	assert.Len(chat.events, 0)
+	assert.Len(email.events, 1)
+}
+
+func TestNotifyDryRun(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, &config.App{DryRun: true})
+
+	prv := &recordingProvider{}
+	alertmanager.providers = []Provider{prv}
+
+	alertmanager.Notify("test")
+	alertmanager.NotifyEvent(event.Event{PodName: "api"})
+	assert.Len(prv.messages, 0)
+}
+
+type routerFunc func(*event.Event) *Route
+
+func (f routerFunc) Route(ev *event.Event) *Route {
+	return f(ev)
+}
+
+func TestNotifyRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	prv := &fakeSectionsProvider{}
+	alertmanager := AlertManager{providers: []Provider{prv}}
+	alertmanager.SetRouter(routerFunc(func(ev *event.Event) *Route {
+		switch ev.Namespace {
+		case "dropped":
+			return &Route{Drop: true}
+		case "other":
+			return &Route{Providers: []string{"slack"}}
+		case "routed":
+			return &Route{
+				Providers: []string{"sections"},
+				Sections:  []string{"logs"},
+			}
+		}
+		return nil
+	}))
+
+	alertmanager.NotifyEvent(event.Event{Namespace: "dropped"})
+	alertmanager.NotifyEvent(event.Event{Namespace: "other"})
+	assert.Nil(prv.sections)
+
+	alertmanager.NotifyEvent(event.Event{Namespace: "routed"})
+	assert.Equal([]string{"logs"}, prv.sections)
+
+	alertmanager.NotifyEvent(event.Event{Namespace: "default"})
+	assert.Equal(event.DefaultSections, prv.sections)
+}
+
+type namedProvider struct {
+	recordingProvider
+	name string
+	err  error
+}
+
+func (p *namedProvider) SendMessage(msg string) error {
+	p.recordingProvider.SendMessage(msg)
+	return p.err
+}
+func (p *namedProvider) SendEvent(evt *event.Event) error {
+	p.recordingProvider.SendEvent(evt)
+	return p.err
+}
+func (p *namedProvider) Name() string {
+	return p.name
+}
+
+func TestNotifyFallback(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+
+	slack := &namedProvider{name: "Slack", err: errors.New("timeout")}
+	email := &namedProvider{name: "Email", err: errors.New("timeout")}
+	webhook := &namedProvider{name: "Webhook"}
+	alertmanager.providers = []Provider{slack, email, webhook}
+	alertmanager.fallbacks[slack] = []string{"missing", "email", "webhook"}
+
+	alertmanager.NotifyProviders([]string{"slack"}, "test")
+	assert.Len(slack.messages, 1)
+	assert.Len(email.messages, 1)
+	assert.Equal([]string{"test"}, webhook.messages)
+
+	alertmanager.NotifyEventProviders(
+		[]string{"slack"},
+		event.Event{Namespace: "default"})
+	assert.Len(email.events, 1)
+	assert.Len(webhook.events, 1)
+
+	// fallbacks aren't used if provider succeeds
+	slack.err = nil
+	alertmanager.NotifyProviders([]string{"slack"}, "test")
+	assert.Len(email.messages, 1)
+	assert.Len(webhook.messages, 1)
+
+	// fallback providers skip events filtered by their reasons
+	slack.err = errors.New("timeout")
+	alertmanager.reasons[webhook] = parseReasonFilter(map[string]interface{}{
+		"reasons": []interface{}{"!OOMKilled"},
+	})
+	alertmanager.NotifyEventProviders(
+		[]string{"slack"},
+		event.Event{Namespace: "default", Reason: "OOMKilled"})
+	assert.Len(email.events, 2)
+	assert.Len(webhook.events, 1)
+}
+
+func TestNotifyRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, &config.App{ProviderFailureThreshold: 1})
+	alertmanager.retryBackoff = time.Millisecond
+
+	slack := &namedProvider{name: "Slack", err: errors.New("timeout")}
+	email := &namedProvider{name: "Email"}
+	alertmanager.providers = []Provider{slack, email}
+	alertmanager.retries[slack] = 2
+	alertmanager.fallbacks[slack] = []string{"email"}
+
+	// failed send is retried before fallback, and it's counted once
+	alertmanager.NotifyProviders([]string{"slack"}, "test")
+	assert.Len(slack.messages, 3)
+	assert.Len(email.messages, 2)
+	assert.Contains(email.messages[0], "1 consecutive notifications")
+	assert.Equal("test", email.messages[1])
+}
+
+func TestGetRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	retries, err := getRetries(map[string]interface{}{})
+	assert.Nil(err)
+	assert.Equal(defaultRetries, retries)
+
+	retries, err = getRetries(map[string]interface{}{"retries": 2})
+	assert.Nil(err)
+	assert.Equal(2, retries)
+
+	retries, err = getRetries(map[string]interface{}{"retries": -1})
+	assert.NotNil(err)
+	assert.Equal(defaultRetries, retries)
+}
+
+func TestGetFallbacks(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(getFallbacks(map[string]interface{}{}))
+	assert.Equal(
+		[]string{"email", "webhook"},
+		getFallbacks(map[string]interface{}{
+			"fallback": []interface{}{"email", "", "webhook"},
+		}))
+}
+
+func TestNotifyReasons(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+
+	pager := &recordingProvider{}
+	chat := &recordingProvider{}
+	email := &recordingProvider{}
+	alertmanager.providers = []Provider{pager, chat, email}
+	alertmanager.reasons[pager] = parseReasonFilter(map[string]interface{}{
+		"reasons": []interface{}{"CrashLoopBackOff", "OOMKilled"},
+	})
+	alertmanager.reasons[chat] = parseReasonFilter(map[string]interface{}{
+		"reasons": []interface{}{"!OOMKilled"},
+	})
+
+	alertmanager.NotifyEvent(event.Event{Reason: "OOMKilled"})
+	alertmanager.NotifyEvent(event.Event{Reason: "Error"})
+	assert.Len(pager.events, 1)
+	assert.Len(chat.events, 1)
+	assert.Len(email.events, 2)
+
+	assert.Nil(parseReasonFilter(map[string]interface{}{}))
+	assert.Nil(parseReasonFilter(map[string]interface{}{
+		"reasons": []interface{}{""},
+	}))
+}
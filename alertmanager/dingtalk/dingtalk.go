@@ -15,6 +15,7 @@ import (
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/constant"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -33,6 +34,12 @@ type DingTalk struct {
 	url         string
 	title       string
 
+	// format of event messages e.g. markdown
+	renderMode string
+
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -50,12 +57,25 @@ func NewDingTalk(config map[string]interface{}, appCfg *config.App) *DingTalk {
 	title, _ := config["title"].(string)
 	secret, _ := config["secret"].(string)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing dingtalk with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &DingTalk{
+		client:      client,
 		accessToken: accessToken,
 		url:         dingTalkAPIURL,
 		title:       title,
 		secret:      secret,
-		appCfg:      appCfg,
+		renderMode: event.GetRenderMode(
+			config,
+			event.RenderMarkdown,
+			event.RenderPlain),
+		appCfg: appCfg,
 	}
 }
 
@@ -73,7 +93,9 @@ func (d *DingTalk) SendEvent(e *event.Event) error {
 		title = constant.DefaultTitle
 	}
 
-	msg := e.FormatMarkdown(d.appCfg.ClusterName, "", "")
+	msg := e.Render(d.renderMode, event.RenderOptions{
+		ClusterName: e.GetClusterName(d.appCfg.ClusterName),
+	})
 
 	body := fmt.Sprintf(`{
 		"msgtype": "markdown",
@@ -111,7 +133,7 @@ func (d *DingTalk) sendAPI(msg string) error {
 
 	request.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
+	client := d.client
 	response, err := client.Do(request)
 	if err != nil {
 		return err
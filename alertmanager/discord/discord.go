@@ -6,6 +6,7 @@ import (
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/constant"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 
 	discordgo "github.com/bwmarrin/discordgo"
 	"github.com/sirupsen/logrus"
@@ -22,6 +23,10 @@ type Discord struct {
 		data *discordgo.WebhookParams,
 		options ...discordgo.RequestOption) (st *discordgo.Message, err error)
 
+	// severityWebhooks are webhooks events are sent to by their severity
+	// instead of default one
+	severityWebhooks util.SeverityTargets
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -34,31 +39,73 @@ func NewDiscord(config map[string]interface{}, appCfg *config.App) *Discord {
 		return nil
 	}
 
-	webhookList := strings.Split(webhook, "/")
-	if len(webhookList) <= 1 {
+	webhookID, webhookToken, ok := splitWebhook(webhook)
+	if !ok {
 		logrus.Warnf("initializing discord with missing id or token")
 		return nil
 	}
 	logrus.Infof("initializing discord with webhook url: %s", webhook)
 
-	webhookToken := webhookList[len(webhookList)-1]
-	webhookID := webhookList[len(webhookList)-2]
+	severityWebhooks, err := util.NewSeverityTargets(
+		config,
+		"severityWebhooks")
+	if err != nil {
+		logrus.Warnf("initializing discord with invalid webhooks: %s", err)
+		return nil
+	}
+	for severity, webhook := range severityWebhooks {
+		if _, _, ok := splitWebhook(webhook); !ok {
+			logrus.Warnf(
+				"initializing discord with missing id or token of %s webhook",
+				severity)
+			return nil
+		}
+	}
+
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing discord with invalid http options: %s",
+			err)
+		return nil
+	}
 
 	discordClient, _ := discordgo.New("")
+	discordClient.Client.Transport = client.Transport
+	discordClient.Client.Timeout = client.Timeout
 
 	title, _ := config["title"].(string)
 	text, _ := config["text"].(string)
 
 	return &Discord{
-		id:     webhookID,
-		token:  webhookToken,
-		title:  title,
-		text:   text,
-		send:   discordClient.WebhookExecute,
-		appCfg: appCfg,
+		id:               webhookID,
+		token:            webhookToken,
+		severityWebhooks: severityWebhooks,
+		title:            title,
+		text:             text,
+		send:             discordClient.WebhookExecute,
+		appCfg:           appCfg,
 	}
 }
 
+// splitWebhook returns id and token of webhook url
+func splitWebhook(webhook string) (string, string, bool) {
+	webhookList := strings.Split(webhook, "/")
+	if len(webhookList) <= 1 {
+		return "", "", false
+	}
+	return webhookList[len(webhookList)-2],
+		webhookList[len(webhookList)-1],
+		true
+}
+
+// sectionIcons are emojis shown before titles of text sections
+var sectionIcons = map[string]string{
+	event.SectionEvents:   ":mag:",
+	event.SectionLogs:     ":memo:",
+	event.SectionCommands: ":wrench:",
+}
+
 // Name returns name of the provider
 func (s *Discord) Name() string {
 	return "Discord"
@@ -72,7 +119,7 @@ func (s *Discord) SendEvent(ev *event.Event) error {
 	fields := []*discordgo.MessageEmbedField{
 		{
 			Name:   "Cluster",
-			Value:  s.appCfg.ClusterName,
+			Value:  ev.GetClusterName(s.appCfg.ClusterName),
 			Inline: true,
 		},
 		{
@@ -97,27 +144,32 @@ func (s *Discord) SendEvent(ev *event.Event) error {
 		},
 	}
 
-	// add events part if it exists
-	events := strings.TrimSpace(ev.Events)
-	if len(events) > 0 {
-		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:  ":mag: Events",
-			Value: "```\n" + events + "```",
-		})
-	}
+	// add sections in configured order
+	for _, section := range ev.Sections() {
+		if section.IsFields() {
+			for _, field := range section.Fields {
+				fields = append(fields, &discordgo.MessageEmbedField{
+					Name:   field.Name,
+					Value:  field.Value,
+					Inline: true,
+				})
+			}
+			continue
+		}
 
-	// add logs part if it exists
-	logs := strings.TrimSpace(ev.Logs)
-	if len(logs) > 0 {
-		logData := logs
+		// add text part if it exists
+		sectionText := strings.TrimSpace(section.Text)
+		if len(sectionText) == 0 {
+			continue
+		}
 
-		if len(logData) > 1024 {
-			logData = logs[:1024]
+		if len(sectionText) > 1024 {
+			sectionText = sectionText[:1024]
 		}
 
 		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:  ":memo: Logs",
-			Value: "```\n" + logData + "```",
+			Name:  sectionIcons[section.Name] + " " + section.Title,
+			Value: "```\n" + sectionText + "```",
 		})
 	}
 
@@ -133,10 +185,15 @@ func (s *Discord) SendEvent(ev *event.Event) error {
 		text = constant.DefaultText
 	}
 
-	// send message
+	// send message to webhook of severity, or default one
+	id, token := s.id, s.token
+	if webhook := s.severityWebhooks.Get(ev.Severity, ""); len(webhook) > 0 {
+		id, token, _ = splitWebhook(webhook)
+	}
+
 	_, err := s.send(
-		s.id,
-		s.token,
+		id,
+		token,
 		false,
 		&discordgo.WebhookParams{
 			Embeds: []*discordgo.MessageEmbed{
@@ -99,3 +99,36 @@ func TestSendEvent(t *testing.T) {
 	}
 	assert.Nil(c.SendEvent(&ev))
 }
+
+func TestSendEventSeverityWebhook(t *testing.T) {
+	assert := assert.New(t)
+
+	configMap := map[string]interface{}{
+		"webhook": "default/token",
+		"severityWebhooks": map[string]interface{}{
+			"critical": "incidents/secret",
+		},
+	}
+	c := NewDiscord(configMap, &config.App{ClusterName: "dev"})
+	assert.NotNil(c)
+
+	ids := make([]string, 0)
+	c.send = func(
+		webhookID,
+		token string,
+		wait bool,
+		data *discordgo.WebhookParams,
+		options ...discordgo.RequestOption) (*discordgo.Message, error) {
+		ids = append(ids, webhookID+"/"+token)
+		return nil, nil
+	}
+
+	assert.Nil(c.SendEvent(&event.Event{Severity: "critical"}))
+	assert.Nil(c.SendEvent(&event.Event{Severity: "info"}))
+	assert.Equal([]string{"incidents/secret", "default/token"}, ids)
+
+	configMap["severityWebhooks"] = map[string]interface{}{
+		"critical": "incidents",
+	}
+	assert.Nil(NewDiscord(configMap, &config.App{ClusterName: "dev"}))
+}
@@ -0,0 +1,138 @@
+package alertmanager
+
+import (
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// Notification is a pending send of a provider, message or event is kept so
+// notifications that weren't sent can be persisted on shutdown
+type Notification struct {
+	Provider string       `json:"provider"`
+	Msg      string       `json:"msg,omitempty"`
+	Event    *event.Event `json:"event,omitempty"`
+
+	send func()
+}
+
+// StartWorkers starts configured number of workers per provider that send
+// queued notifications, without workers notifications are sent inline
+func (a *AlertManager) StartWorkers(cfg *config.Dispatch) {
+	if cfg.Workers <= 0 {
+		return
+	}
+
+	a.queuesMu.Lock()
+	defer a.queuesMu.Unlock()
+
+	a.abort = make(chan struct{})
+	a.queues = make(map[Provider]chan *Notification, len(a.providers))
+	for _, prv := range a.providers {
+		queue := make(chan *Notification, cfg.QueueSize)
+		a.queues[prv] = queue
+
+		for i := 0; i < cfg.Workers; i++ {
+			a.workers.Add(1)
+			go a.runWorker(prv, queue)
+		}
+	}
+}
+
+// runWorker sends queued notifications of provider until queue is closed or
+// workers are aborted
+func (a *AlertManager) runWorker(prv Provider, queue chan *Notification) {
+	defer a.workers.Done()
+
+	for {
+		select {
+		case <-a.abort:
+			return
+		default:
+		}
+
+		n, ok := <-queue
+		if !ok {
+			return
+		}
+
+		metrics.DispatchQueueLength.
+			WithLabelValues(prv.Name()).
+			Set(float64(len(queue)))
+		n.send()
+	}
+}
+
+// dispatch queues notification of provider, it's sent inline if workers
+// aren't started and dropped if provider queue is full
+func (a *AlertManager) dispatch(prv Provider, n *Notification) {
+	a.queuesMu.RLock()
+	if a.stopped {
+		a.queuesMu.RUnlock()
+		logrus.WithField("provider", prv.Name()).
+			Warn("kwatch is shutting down, dropping notification")
+		return
+	}
+
+	queue, ok := a.queues[prv]
+	if !ok {
+		// lock is released before sending inline, as send may dispatch
+		// provider failure notifications and take it again
+		a.queuesMu.RUnlock()
+		n.send()
+		return
+	}
+	defer a.queuesMu.RUnlock()
+
+	select {
+	case queue <- n:
+		metrics.DispatchQueueLength.
+			WithLabelValues(prv.Name()).
+			Set(float64(len(queue)))
+	default:
+		metrics.DispatchDropped.WithLabelValues(prv.Name()).Inc()
+		logrus.WithField("provider", prv.Name()).
+			Error("notification queue is full, dropping notification")
+	}
+}
+
+// Stop stops accepting notifications and waits for queued ones to be sent
+// until timeout, it returns notifications that weren't sent in time
+func (a *AlertManager) Stop(timeout time.Duration) []*Notification {
+	a.queuesMu.Lock()
+	a.stopped = true
+	for _, queue := range a.queues {
+		close(queue)
+	}
+	a.queuesMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	// stop workers once they finish in-flight sends and collect what is
+	// still queued
+	close(a.abort)
+
+	pending := make([]*Notification, 0)
+	for _, queue := range a.queues {
+		for n := range queue {
+			pending = append(pending, n)
+		}
+	}
+
+	logrus.WithField("pending", len(pending)).
+		Warn("timed out waiting for notifications to be sent")
+	return pending
+}
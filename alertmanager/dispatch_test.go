@@ -0,0 +1,132 @@
+package alertmanager
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingProvider struct {
+	release chan struct{}
+}
+
+func (p *blockingProvider) SendMessage(msg string) error {
+	<-p.release
+	return nil
+}
+func (p *blockingProvider) SendEvent(evt *event.Event) error {
+	<-p.release
+	return nil
+}
+func (p *blockingProvider) Name() string {
+	return "Blocking"
+}
+
+type countingProvider struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (p *countingProvider) SendMessage(msg string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.count++
+	return nil
+}
+func (p *countingProvider) SendEvent(evt *event.Event) error {
+	return p.SendMessage("")
+}
+func (p *countingProvider) Name() string {
+	return "Counting"
+}
+func (p *countingProvider) Count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+func TestDispatchSlowProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	slowPrv := &blockingProvider{release: make(chan struct{})}
+	fastPrv := &countingProvider{}
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+	alertmanager.providers = []Provider{slowPrv, fastPrv}
+	alertmanager.StartWorkers(&config.Dispatch{Workers: 1, QueueSize: 3})
+
+	done := make(chan struct{})
+	go func() {
+		alertmanager.Notify("first")
+		alertmanager.NotifyEvent(event.Event{PodName: "api"})
+		alertmanager.Notify("third")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notify was blocked by slow provider")
+	}
+
+	assert.Eventually(
+		func() bool { return fastPrv.Count() == 3 },
+		5*time.Second,
+		10*time.Millisecond)
+
+	close(slowPrv.release)
+}
+
+func TestStop(t *testing.T) {
+	assert := assert.New(t)
+
+	fastPrv := &countingProvider{}
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+	alertmanager.providers = []Provider{fastPrv}
+	alertmanager.StartWorkers(&config.Dispatch{Workers: 1, QueueSize: 3})
+
+	alertmanager.Notify("first")
+	alertmanager.Notify("second")
+
+	pending := alertmanager.Stop(5 * time.Second)
+	assert.Empty(pending)
+	assert.Equal(2, fastPrv.Count())
+
+	// notifications are dropped after stop
+	alertmanager.Notify("third")
+	assert.Equal(2, fastPrv.Count())
+}
+
+func TestStopTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	slowPrv := &blockingProvider{release: make(chan struct{})}
+	defer close(slowPrv.release)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+	alertmanager.providers = []Provider{slowPrv}
+	alertmanager.StartWorkers(&config.Dispatch{Workers: 1, QueueSize: 3})
+
+	alertmanager.Notify("first")
+	alertmanager.NotifyEvent(event.Event{PodName: "api"})
+	alertmanager.Notify("third")
+
+	// first notification is in-flight, the rest are still queued
+	assert.Eventually(
+		func() bool { return len(alertmanager.queues[slowPrv]) == 2 },
+		5*time.Second,
+		10*time.Millisecond)
+
+	pending := alertmanager.Stop(10 * time.Millisecond)
+	assert.Len(pending, 2)
+	assert.Equal("api", pending[0].Event.PodName)
+	assert.Equal("third", pending[1].Msg)
+}
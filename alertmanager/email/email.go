@@ -64,7 +64,24 @@ func NewEmail(config map[string]interface{}, appCfg *config.App) *Email {
 		return nil
 	}
 
+	tlsConfig, err := util.NewTLSConfig(config)
+	if err != nil {
+		logrus.Warnf("initializing email with invalid tls config: %s", err)
+		return nil
+	}
+
+	timeout, err := util.GetProviderTimeout(config)
+	if err != nil {
+		logrus.Warnf("initializing email with invalid timeout: %s", err)
+		return nil
+	}
+
 	d := gomail.NewDialer(host, portNumber, from, password)
+	d.Timeout = timeout
+	if tlsConfig != nil {
+		tlsConfig.ServerName = host
+		d.TLSConfig = tlsConfig
+	}
 
 	return &Email{
 		from:   from,
@@ -89,6 +106,13 @@ func (e *Email) SendEvent(event *event.Event) error {
 	m.SetHeader("Subject", subject)
 	m.SetBody("text/plain", body)
 
+	// attach full logs if they were captured
+	if len(strings.TrimSpace(event.FullLogs)) > 0 {
+		m.AttachReader(
+			event.LogsFileName(),
+			strings.NewReader(event.FullLogs))
+	}
+
 	return e.send(m)
 }
 
@@ -99,34 +123,34 @@ func (e *Email) SendMessage(s string) error {
 
 func (e *Email) buildMessageSubjectAndBody(
 	ev *event.Event) (string, string) {
-	eventsText := "No events captured"
-	logsText := "No logs captured"
-
-	// add events part if it exists
-	events := strings.TrimSpace(ev.Events)
-	if len(events) > 0 {
-		eventsText = util.JsonEscape(ev.Events)
-	}
-
-	// add logs part if it exists
-	logs := strings.TrimSpace(ev.Logs)
-	if len(logs) > 0 {
-		logsText = util.JsonEscape(ev.Logs)
+	subject := fmt.Sprintf("⛑ Kwatch detected a crash in pod %s ", ev.ContainerName)
+	extraText := ""
+	sectionsText := make([]string, 0)
+	for _, section := range ev.Sections() {
+		if section.IsFields() {
+			for _, field := range section.Fields {
+				extraText += fmt.Sprintf("%s: *%s*  ", field.Name, field.Value)
+			}
+			continue
+		}
+
+		sectionsText = append(sectionsText, fmt.Sprintf(
+			"%s: *%s* ",
+			section.Title,
+			util.JsonEscape(section.DisplayText())))
 	}
 
-	subject := fmt.Sprintf("⛑ Kwatch detected a crash in pod %s ", ev.ContainerName)
 	body := fmt.Sprintf(
 		"An alert for cluster: *%s* Name: *%s*  Container: *%s* "+
-			"Namespace: *%s*  "+
+			"Namespace: *%s*  %s"+
 			"has been triggered:\\n—\\n "+
-			"Logs: *%s* \\n "+
-			"Events: *%s* ",
-		e.appCfg.ClusterName,
+			"%s",
+		ev.GetClusterName(e.appCfg.ClusterName),
 		ev.PodName,
 		ev.ContainerName,
 		ev.Namespace,
-		logsText,
-		eventsText,
+		extraText,
+		strings.Join(sectionsText, "\\n "),
 	)
 	return subject, body
 }
@@ -127,6 +127,7 @@ func TestSendEvent(t *testing.T) {
 		Namespace:     "default",
 		Reason:        "OOMKILLED",
 		Logs:          "test\ntestlogs",
+		FullLogs:      "full\ntest\ntestlogs",
 		Events: "event1-event2-event3-event1-event2-event3-event1-event2-" +
 			"event3\nevent5\nevent6-event8-event11-event12",
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,6 +17,12 @@ type FeiShu struct {
 	webhook string
 	title   string
 
+	// format of event messages e.g. markdown
+	renderMode string
+
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -37,10 +44,23 @@ func NewFeiShu(config map[string]interface{}, appCfg *config.App) *FeiShu {
 
 	title, _ := config["title"].(string)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing feishu with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &FeiShu{
+		client:  client,
 		webhook: webhook,
 		title:   title,
-		appCfg:  appCfg,
+		renderMode: event.GetRenderMode(
+			config,
+			event.RenderMarkdown,
+			event.RenderPlain),
+		appCfg: appCfg,
 	}
 
 }
@@ -52,12 +72,14 @@ func (r *FeiShu) Name() string {
 
 // SendEvent sends event to the provider
 func (r *FeiShu) SendEvent(e *event.Event) error {
-	formattedMsg := e.FormatMarkdown(r.appCfg.ClusterName, "", "")
+	formattedMsg := e.Render(r.renderMode, event.RenderOptions{
+		ClusterName: e.GetClusterName(r.appCfg.ClusterName),
+	})
 	return r.sendByFeiShuApi(r.buildRequestBodyFeiShu(formattedMsg))
 }
 
 func (r *FeiShu) sendByFeiShuApi(reqBody string) error {
-	client := &http.Client{}
+	client := r.client
 	buffer := bytes.NewBuffer([]byte(reqBody))
 	request, err := http.NewRequest(http.MethodPost, r.webhook, buffer)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,6 +17,12 @@ type GoogleChat struct {
 	webhook string
 	text    string
 
+	// format of event messages e.g. markdown
+	renderMode string
+
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -36,10 +43,23 @@ func NewGoogleChat(config map[string]interface{}, appCfg *config.App) *GoogleCha
 
 	text, _ := config["text"].(string)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing googlechat with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &GoogleChat{
+		client:  client,
 		webhook: webhook,
 		text:    text,
-		appCfg:  appCfg,
+		renderMode: event.GetRenderMode(
+			config,
+			event.RenderPlain,
+			event.RenderSlack),
+		appCfg: appCfg,
 	}
 }
 
@@ -50,12 +70,15 @@ func (r *GoogleChat) Name() string {
 
 // SendEvent sends event to the provider
 func (r *GoogleChat) SendEvent(e *event.Event) error {
-	formattedMsg := e.FormatText(r.appCfg.ClusterName, r.text)
+	formattedMsg := e.Render(r.renderMode, event.RenderOptions{
+		ClusterName: e.GetClusterName(r.appCfg.ClusterName),
+		Text:        r.text,
+	})
 	return r.sendAPI(r.buildRequestBody(formattedMsg))
 }
 
 func (r *GoogleChat) sendAPI(reqBody string) error {
-	client := &http.Client{}
+	client := r.client
 	buffer := bytes.NewBuffer([]byte(reqBody))
 	request, err := http.NewRequest(http.MethodPost, r.webhook, buffer)
 	if err != nil {
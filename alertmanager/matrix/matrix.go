@@ -21,6 +21,9 @@ type Matrix struct {
 	title          string
 	text           string
 
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -48,7 +51,16 @@ func NewMatrix(config map[string]interface{}, appCfg *config.App) *Matrix {
 	title, _ := config["title"].(string)
 	text, _ := config["text"].(string)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing matrix with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &Matrix{
+		client:         client,
 		homeServer:     homeServer,
 		accessToken:    accessToken,
 		internalRoomID: internalRoomID,
@@ -67,7 +79,8 @@ func (m *Matrix) SendMessage(msg string) error {
 }
 
 func (m *Matrix) SendEvent(e *event.Event) error {
-	return m.sendAPI(e.FormatHtml(m.appCfg.ClusterName, m.text))
+	clusterName := e.GetClusterName(m.appCfg.ClusterName)
+	return m.sendAPI(e.FormatHtml(clusterName, m.text))
 }
 
 func (m *Matrix) sendAPI(formattedMsg string) error {
@@ -98,7 +111,7 @@ func (m *Matrix) sendAPI(formattedMsg string) error {
 	}
 
 	request.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
+	client := m.client
 	response, err := client.Do(request)
 	if err != nil {
 		return err
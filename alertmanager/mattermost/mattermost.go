@@ -11,6 +11,7 @@ import (
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/constant"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,6 +20,13 @@ type Mattermost struct {
 	title   string
 	text    string
 
+	// severityWebhooks are webhooks events are sent to by their severity
+	// instead of default one
+	severityWebhooks util.SeverityTargets
+
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -53,11 +61,29 @@ func NewMattermost(config map[string]interface{}, appCfg *config.App) *Mattermos
 	title, _ := config["title"].(string)
 	text, _ := config["text"].(string)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing mattermost with invalid http options: %s",
+			err)
+		return nil
+	}
+
+	severityWebhooks, err := util.NewSeverityTargets(
+		config,
+		"severityWebhooks")
+	if err != nil {
+		logrus.Warnf("initializing mattermost with invalid webhooks: %s", err)
+		return nil
+	}
+
 	return &Mattermost{
-		webhook: webhook,
-		title:   title,
-		text:    text,
-		appCfg:  appCfg,
+		client:           client,
+		webhook:          webhook,
+		severityWebhooks: severityWebhooks,
+		title:            title,
+		text:             text,
+		appCfg:           appCfg,
 	}
 }
 
@@ -70,20 +96,22 @@ func (m *Mattermost) Name() string {
 func (m *Mattermost) SendMessage(msg string) error {
 	logrus.Debugf("sending to mattermost msg: %s", msg)
 
-	return m.sendAPI(m.buildMessage(nil, &msg))
+	return m.sendAPI(m.webhook, m.buildMessage(nil, &msg))
 }
 
 // SendEvent sends event to the provider
 func (m *Mattermost) SendEvent(e *event.Event) error {
 	logrus.Debugf("sending to mattermost event: %v", e)
 
-	return m.sendAPI(m.buildMessage(e, nil))
+	return m.sendAPI(
+		m.severityWebhooks.Get(e.Severity, m.webhook),
+		m.buildMessage(e, nil))
 }
 
-func (m *Mattermost) sendAPI(content []byte) error {
-	client := &http.Client{}
+func (m *Mattermost) sendAPI(webhook string, content []byte) error {
+	client := m.client
 	buffer := bytes.NewBuffer(content)
-	request, err := http.NewRequest(http.MethodPost, m.webhook, buffer)
+	request, err := http.NewRequest(http.MethodPost, webhook, buffer)
 	if err != nil {
 		return err
 	}
@@ -107,6 +135,13 @@ func (m *Mattermost) sendAPI(content []byte) error {
 	return nil
 }
 
+// sectionIcons are emojis shown before titles of text sections
+var sectionIcons = map[string]string{
+	event.SectionEvents:   ":mag:",
+	event.SectionLogs:     ":memo:",
+	event.SectionCommands: ":wrench:",
+}
+
 func (m *Mattermost) buildMessage(e *event.Event, msg *string) []byte {
 	payload := mmPayload{}
 
@@ -115,16 +150,6 @@ func (m *Mattermost) buildMessage(e *event.Event, msg *string) []byte {
 	}
 
 	if e != nil {
-		logs := constant.DefaultLogs
-		if len(e.Logs) > 0 {
-			logs = (e.Logs)
-		}
-
-		events := constant.DefaultEvents
-		if len(e.Events) > 0 {
-			events = (e.Events)
-		}
-
 		// use custom title if it's provided, otherwise use default
 		title := m.title
 		if len(title) == 0 {
@@ -137,47 +162,59 @@ func (m *Mattermost) buildMessage(e *event.Event, msg *string) []byte {
 			text = constant.DefaultText
 		}
 
-		payload.Attachments = []mmAttachment{
+		fields := []mmField{
 			{
-				Title: title,
-				Text:  text,
-				Fields: []mmField{
-					{
-						Title: "Cluster",
-						Value: m.appCfg.ClusterName,
-						Short: true,
-					},
-					{
-						Title: "Name",
-						Value: e.PodName,
-						Short: true,
-					},
-					{
-						Title: "Container",
-						Value: e.ContainerName,
-						Short: true,
-					},
-					{
-						Title: "Namespace",
-						Value: e.Namespace,
-						Short: true,
-					},
-					{
-						Title: "Reason",
-						Value: e.Reason,
+				Title: "Cluster",
+				Value: e.GetClusterName(m.appCfg.ClusterName),
+				Short: true,
+			},
+			{
+				Title: "Name",
+				Value: e.PodName,
+				Short: true,
+			},
+			{
+				Title: "Container",
+				Value: e.ContainerName,
+				Short: true,
+			},
+			{
+				Title: "Namespace",
+				Value: e.Namespace,
+				Short: true,
+			},
+			{
+				Title: "Reason",
+				Value: e.Reason,
+				Short: true,
+			},
+		}
+
+		// add sections in configured order
+		for _, section := range e.Sections() {
+			if section.IsFields() {
+				for _, field := range section.Fields {
+					fields = append(fields, mmField{
+						Title: field.Name,
+						Value: field.Value,
 						Short: true,
-					},
-					{
-						Title: ":mag: Events",
-						Value: "```\n" + events + " \n```",
-						Short: false,
-					},
-					{
-						Title: ":memo: Logs",
-						Value: "```\n" + logs + "\n```",
-						Short: false,
-					},
-				},
+					})
+				}
+				continue
+			}
+
+			fields = append(fields, mmField{
+				Title: sectionIcons[section.Name] + " " + section.Title,
+				Value: "```\n" + section.DisplayText() + "\n```",
+				Short: false,
+			})
+		}
+
+		payload.Attachments = []mmAttachment{
+			{
+				Title:  title,
+				Text:   text,
+				Fields: fields,
 			},
 		}
 	}
@@ -114,3 +114,31 @@ func TestInvaildHttpRequest(t *testing.T) {
 
 	assert.NotNil(c.SendMessage("test"))
 }
+
+func TestSendEventSeverityWebhook(t *testing.T) {
+	assert := assert.New(t)
+
+	paths := make([]string, 0)
+	s := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+		}))
+	defer s.Close()
+
+	configMap := map[string]interface{}{
+		"webhook": s.URL + "/default",
+		"severityWebhooks": map[string]interface{}{
+			"critical": s.URL + "/incidents",
+		},
+	}
+	c := NewMattermost(configMap, &config.App{ClusterName: "dev"})
+	assert.NotNil(c)
+
+	assert.Nil(c.SendEvent(&event.Event{Severity: "critical"}))
+	assert.Nil(c.SendEvent(&event.Event{Severity: "warning"}))
+	assert.Nil(c.SendMessage("test"))
+	assert.Equal([]string{"/incidents", "/default", "/default"}, paths)
+
+	configMap["severityWebhooks"] = map[string]interface{}{"fatal": s.URL}
+	assert.Nil(NewMattermost(configMap, &config.App{ClusterName: "dev"}))
+}
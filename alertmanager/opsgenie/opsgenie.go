@@ -8,8 +8,8 @@ import (
 	"net/http"
 
 	"github.com/abahmed/kwatch/config"
-	"github.com/abahmed/kwatch/constant"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -25,6 +25,9 @@ type Opsgenie struct {
 	title  string
 	text   string
 
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -49,7 +52,16 @@ func NewOpsgenie(config map[string]interface{}, appCfg *config.App) *Opsgenie {
 	title, _ := config["title"].(string)
 	text, _ := config["text"].(string)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing opsgenie with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &Opsgenie{
+		client: client,
 		apikey: apiKey,
 		url:    opsgenieAPIURL,
 		title:  title,
@@ -75,7 +87,7 @@ func (m *Opsgenie) SendEvent(e *event.Event) error {
 
 // sendAPI sends http request to Opsgenie API
 func (m *Opsgenie) sendAPI(content []byte) error {
-	client := &http.Client{}
+	client := m.client
 	buffer := bytes.NewBuffer(content)
 	request, err := http.NewRequest(http.MethodPost, m.url, buffer)
 	if err != nil {
@@ -108,16 +120,6 @@ func (m *Opsgenie) buildMessage(e *event.Event) []byte {
 		Priority: "P1",
 	}
 
-	logs := constant.DefaultLogs
-	if len(e.Logs) > 0 {
-		logs = (e.Logs)
-	}
-
-	events := constant.DefaultEvents
-	if len(e.Events) > 0 {
-		events = (e.Events)
-	}
-
 	// use custom title if it's provided, otherwise use default
 	title := m.title
 	if len(title) == 0 {
@@ -132,15 +134,22 @@ func (m *Opsgenie) buildMessage(e *event.Event) []byte {
 	}
 
 	payload.Description = text
-	payload.Details = map[string]string{
-		"Cluster":   m.appCfg.ClusterName,
+	details := map[string]string{
+		"Cluster":   e.GetClusterName(m.appCfg.ClusterName),
 		"Name":      e.PodName,
 		"Container": e.ContainerName,
 		"Namespace": e.Namespace,
 		"Reason":    e.Reason,
-		"Events":    events,
-		"Logs":      logs,
 	}
+	for _, section := range e.Sections() {
+		for _, field := range section.Fields {
+			details[field.Name] = field.Value
+		}
+		if !section.IsFields() {
+			details[section.Title] = section.DisplayText()
+		}
+	}
+	payload.Details = details
 
 	str, _ := json.Marshal(payload)
 	return str
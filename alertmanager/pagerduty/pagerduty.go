@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
@@ -21,6 +20,9 @@ type Pagerduty struct {
 	integrationKey string
 	url            string
 
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -35,7 +37,16 @@ func NewPagerDuty(config map[string]interface{}, appCfg *config.App) *Pagerduty
 
 	logrus.Infof("initializing pagerduty with the provided integration key")
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing pagerduty with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &Pagerduty{
+		client:         client,
 		integrationKey: integrationKey,
 		url:            pagerdutyAPIURL,
 		appCfg:         appCfg,
@@ -49,7 +60,7 @@ func (s *Pagerduty) Name() string {
 
 // SendEvent sends event to the provider
 func (s *Pagerduty) SendEvent(ev *event.Event) error {
-	client := &http.Client{}
+	client := s.client
 
 	reqBody := s.buildRequestBodyPagerDuty(ev, s.integrationKey)
 	buffer := bytes.NewBuffer([]byte(reqBody))
@@ -84,19 +95,20 @@ func (s *Pagerduty) SendMessage(msg string) error {
 func (s *Pagerduty) buildRequestBodyPagerDuty(
 	ev *event.Event,
 	key string) string {
-	eventsText := "No events captured"
-	logsText := "No logs captured"
-
-	// add events part if it exists
-	events := strings.TrimSpace(ev.Events)
-	if len(events) > 0 {
-		eventsText = util.JsonEscape(ev.Events)
-	}
-
-	// add logs part if it exists
-	logs := strings.TrimSpace(ev.Logs)
-	if len(logs) > 0 {
-		logsText = util.JsonEscape(ev.Logs)
+	extraDetails := ""
+	for _, section := range ev.Sections() {
+		for _, field := range section.Fields {
+			extraDetails += fmt.Sprintf(
+				`,"%s": "%s"`,
+				util.JsonEscape(field.Name),
+				util.JsonEscape(field.Value))
+		}
+		if !section.IsFields() {
+			extraDetails += fmt.Sprintf(
+				`,"%s": "%s"`,
+				section.Title,
+				util.JsonEscape(section.DisplayText()))
+		}
 	}
 
 	reqBody := fmt.Sprintf(`{
@@ -111,22 +123,19 @@ func (s *Pagerduty) buildRequestBodyPagerDuty(
 			"Name": "%s",
 			"Container": "%s",
 			"Namespace": "%s",
-			"Reason": "%s",
-			"Events": "%s",
-			"Logs": "%s"
+			"Reason": "%s"%s
 		  }
 		}
 	  }`,
 		key,
 		fmt.Sprintf(defaultEventTitle, ev.ContainerName),
 		ev.ContainerName,
-		s.appCfg.ClusterName,
+		ev.GetClusterName(s.appCfg.ClusterName),
 		ev.PodName,
 		ev.ContainerName,
 		ev.Namespace,
 		ev.Reason,
-		eventsText,
-		logsText)
+		extraDetails)
 
 	return reqBody
 }
@@ -29,6 +29,17 @@ func TestPagerduty(t *testing.T) {
 	assert.Equal(c.Name(), "PagerDuty")
 }
 
+func TestPagerdutyInvalidProxy(t *testing.T) {
+	assert := assert.New(t)
+
+	configMap := map[string]interface{}{
+		"integrationKey": "testtest",
+		"proxyURL":       "proxy",
+	}
+	c := NewPagerDuty(configMap, &config.App{ClusterName: "dev"})
+	assert.Nil(c)
+}
+
 func TestSendMessage(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,79 @@
+package alertmanager
+
+import (
+	"fmt"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
+)
+
+// PayloadLimit is max payload size of provider and strategy truncating
+// messages exceeding it, so they aren't rejected e.g. with 413 status
+type PayloadLimit struct {
+	// maxBytes is max size of rendered messages
+	maxBytes int
+
+	// strategy is one of event.TruncationStrategies
+	strategy string
+}
+
+// parsePayloadLimit returns payload limit of provider options
+// maxPayloadSize and truncation, or nil if max payload size isn't set
+func parsePayloadLimit(
+	providerCfg map[string]interface{}) (*PayloadLimit, error) {
+	var maxBytes int
+	switch size := providerCfg["maxPayloadSize"].(type) {
+	case nil:
+		return nil, nil
+	case int:
+		maxBytes = size
+	case float64:
+		maxBytes = int(size)
+	default:
+		return nil, fmt.Errorf("max payload size must be number of bytes")
+	}
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("max payload size must be positive")
+	}
+
+	limit := &PayloadLimit{
+		maxBytes: maxBytes,
+		strategy: event.TruncationStrategies[0],
+	}
+	strategy, _ := providerCfg["truncation"].(string)
+	if len(strategy) == 0 {
+		return limit, nil
+	}
+
+	for _, supported := range event.TruncationStrategies {
+		if strategy == supported {
+			limit.strategy = strategy
+			return limit, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported truncation strategy %s", strategy)
+}
+
+// truncateEvent returns event truncated to fit limit
+func (l *PayloadLimit) truncateEvent(ev *event.Event) *event.Event {
+	if l == nil {
+		return ev
+	}
+	return ev.Truncate(l.maxBytes, l.strategy)
+}
+
+// truncateMessage returns msg truncated to fit limit, messages have no logs
+// to drop so they keep their beginning and end with dropLogs strategy
+func (l *PayloadLimit) truncateMessage(msg string) string {
+	if l == nil {
+		return msg
+	}
+
+	switch l.strategy {
+	case event.TruncateHead:
+		return util.TruncateHead(msg, l.maxBytes)
+	case event.TruncateTail:
+		return util.TruncateTail(msg, l.maxBytes)
+	}
+	return util.TruncateLogs(msg, l.maxBytes)
+}
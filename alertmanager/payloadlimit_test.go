@@ -0,0 +1,132 @@
+package alertmanager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePayloadLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	limit, err := parsePayloadLimit(map[string]interface{}{})
+	assert.Nil(err)
+	assert.Nil(limit)
+
+	limit, err = parsePayloadLimit(map[string]interface{}{
+		"maxPayloadSize": 4000,
+	})
+	assert.Nil(err)
+	assert.Equal(4000, limit.maxBytes)
+	assert.Equal(event.TruncateHeadTail, limit.strategy)
+
+	limit, err = parsePayloadLimit(map[string]interface{}{
+		"maxPayloadSize": 4000,
+		"truncation":     "dropLogs",
+	})
+	assert.Nil(err)
+	assert.Equal(event.TruncateDropLogs, limit.strategy)
+
+	_, err = parsePayloadLimit(map[string]interface{}{
+		"maxPayloadSize": "4kb",
+	})
+	assert.NotNil(err)
+
+	_, err = parsePayloadLimit(map[string]interface{}{
+		"maxPayloadSize": 0,
+	})
+	assert.NotNil(err)
+
+	_, err = parsePayloadLimit(map[string]interface{}{
+		"maxPayloadSize": 4000,
+		"truncation":     "middle",
+	})
+	assert.NotNil(err)
+}
+
+func TestPayloadLimitTruncateEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	ev := &event.Event{
+		PodName:   "api",
+		Namespace: "default",
+		Reason:    "CrashLoopBackOff",
+		Events:    strings.Repeat("event\n", 50),
+		Logs: "first line\n" + strings.Repeat("log line\n", 500) +
+			"last line",
+	}
+
+	var limit *PayloadLimit
+	assert.Equal(ev, limit.truncateEvent(ev))
+
+	limit = &PayloadLimit{maxBytes: 100000, strategy: event.TruncateHead}
+	assert.Equal(ev, limit.truncateEvent(ev))
+
+	tests := []struct {
+		strategy string
+		check    func(ev *event.Event)
+	}{
+		{event.TruncateHead, func(ev *event.Event) {
+			assert.True(strings.HasPrefix(ev.Logs, "first line"))
+			assert.NotContains(ev.Logs, "last line")
+		}},
+		{event.TruncateTail, func(ev *event.Event) {
+			assert.NotContains(ev.Logs, "first line")
+			assert.True(strings.HasSuffix(ev.Logs, "last line"))
+		}},
+		{event.TruncateHeadTail, func(ev *event.Event) {
+			assert.True(strings.HasPrefix(ev.Logs, "first line"))
+			assert.True(strings.HasSuffix(ev.Logs, "last line"))
+		}},
+		{event.TruncateDropLogs, func(ev *event.Event) {
+			assert.Empty(ev.Logs)
+			assert.Equal(strings.Repeat("event\n", 50), ev.Events)
+		}},
+	}
+
+	for _, test := range tests {
+		limit := &PayloadLimit{maxBytes: 1000, strategy: test.strategy}
+		truncated := limit.truncateEvent(ev)
+		assert.LessOrEqual(len(truncated.FormatText("", "")), 1000)
+		assert.LessOrEqual(len(truncated.FormatJSON("", "")), 1000)
+		test.check(truncated)
+	}
+
+	// events are truncated too if dropping logs isn't enough
+	limit = &PayloadLimit{maxBytes: 500, strategy: event.TruncateDropLogs}
+	truncated := limit.truncateEvent(ev)
+	assert.Empty(truncated.Logs)
+	assert.Contains(truncated.Events, "bytes truncated")
+	assert.LessOrEqual(len(truncated.FormatJSON("", "")), 500)
+
+	// original event is kept as is
+	assert.Contains(ev.Logs, "last line")
+}
+
+func TestNotifyPayloadLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+
+	limited := &recordingProvider{}
+	unlimited := &recordingProvider{}
+	alertmanager.providers = []Provider{limited, unlimited}
+	alertmanager.payloadLimits[limited] = &PayloadLimit{
+		maxBytes: 100,
+		strategy: event.TruncateHead,
+	}
+
+	msg := strings.Repeat("a", 200)
+	alertmanager.Notify(msg)
+	assert.LessOrEqual(len(limited.messages[0]), 100)
+	assert.Equal(msg, unlimited.messages[0])
+
+	alertmanager.NotifyEvent(event.Event{
+		Namespace: "default",
+		Logs:      strings.Repeat("log line\n", 500),
+	})
+	assert.Less(len(limited.events[0].Logs), len(unlimited.events[0].Logs))
+}
@@ -0,0 +1,66 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SavePending writes notifications that weren't sent before shutdown to
+// path, so they're sent once kwatch starts again
+func SavePending(path string, pending []*Notification) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// ResendPending queues notifications saved on last shutdown to their
+// providers and removes saved file
+func (a *AlertManager) ResendPending(path string) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("failed to read pending notifications")
+		return
+	}
+
+	if err := os.Remove(path); err != nil {
+		logrus.WithError(err).Error("failed to remove pending notifications")
+	}
+
+	var pending []*Notification
+	if err := json.Unmarshal(data, &pending); err != nil {
+		logrus.WithError(err).Error("failed to parse pending notifications")
+		return
+	}
+
+	logrus.WithField("pending", len(pending)).
+		Info("resending notifications pending since last shutdown")
+
+	for _, n := range pending {
+		prv := a.findProvider(n.Provider)
+		if prv == nil {
+			logrus.WithField("provider", n.Provider).
+				Warn("provider of pending notification isn't configured")
+			continue
+		}
+
+		if n.Event != nil {
+			a.dispatch(prv, a.newEventNotification(prv, n.Event, nil))
+		} else {
+			a.dispatch(prv, a.newMessageNotification(prv, n.Msg))
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package alertmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResendPending(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "pending.json")
+
+	err := SavePending(path, []*Notification{
+		{Provider: "Counting", Msg: "test"},
+		{Provider: "Counting", Event: &event.Event{PodName: "api"}},
+		{Provider: "Unknown", Msg: "test"},
+	})
+	assert.NoError(err)
+
+	prv := &countingProvider{}
+
+	alertmanager := AlertManager{}
+	alertmanager.Init(nil, nil)
+	alertmanager.providers = []Provider{prv}
+	alertmanager.ResendPending(path)
+
+	assert.Eventually(
+		func() bool { return prv.Count() == 2 },
+		5*time.Second,
+		10*time.Millisecond)
+
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+
+	// missing file is ignored
+	alertmanager.ResendPending(path)
+	assert.Equal(2, prv.Count())
+}
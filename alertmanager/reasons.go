@@ -0,0 +1,48 @@
+package alertmanager
+
+import (
+	"slices"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// ReasonFilter is allow or forbid list of reasons of events sent to provider
+type ReasonFilter struct {
+	allowed   []string
+	forbidden []string
+}
+
+// parseReasonFilter returns reason filter of provider option reasons, which
+// has the same syntax as global reasons, or nil if it isn't set
+func parseReasonFilter(providerCfg map[string]interface{}) *ReasonFilter {
+	items, ok := providerCfg["reasons"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	reasons := make([]string, 0, len(items))
+	for _, item := range items {
+		if reason, ok := item.(string); ok && len(reason) > 0 {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	filter := &ReasonFilter{}
+	filter.allowed, filter.forbidden = config.GetAllowForbidSlices(reasons)
+	return filter
+}
+
+// Allows returns true if event of reason is sent to provider
+func (f *ReasonFilter) Allows(reason string) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.allowed) > 0 && !slices.Contains(f.allowed, reason) {
+		return false
+	}
+	return !slices.Contains(f.forbidden, reason)
+}
@@ -0,0 +1,136 @@
+package alertmanager
+
+// ProviderOption is an option of provider config
+type ProviderOption struct {
+	Name string
+
+	// Type is JSON Schema type of option e.g. string
+	Type string
+
+	// Required if set to true, provider isn't initialized without option
+	Required bool
+}
+
+// commonOptions are options of all providers
+var commonOptions = []ProviderOption{
+	{Name: "sections", Type: "array"},
+	{Name: "disableStartupMessage", Type: "boolean"},
+	{Name: "disableUpdateMessage", Type: "boolean"},
+	{Name: "minSeverity", Type: "string"},
+	{Name: "reasons", Type: "array"},
+	{Name: "activeHours", Type: "object"},
+	{Name: "proxyURL", Type: "string"},
+	{Name: "tls", Type: "object"},
+	{Name: "oauth2", Type: "object"},
+	{Name: "maxPayloadSize", Type: "integer"},
+	{Name: "truncation", Type: "string"},
+	{Name: "timeout", Type: "number"},
+	{Name: "fallback", Type: "array"},
+	{Name: "retries", Type: "integer"},
+}
+
+// renderOption is option of providers supporting multiple render modes
+var renderOption = ProviderOption{Name: "renderMode", Type: "string"}
+
+// ProviderOptions are options of providers config by provider name, they
+// must be kept in sync with options read by providers
+var ProviderOptions = map[string][]ProviderOption{
+	"slack": {
+		{Name: "webhook", Type: "string", Required: true},
+		{Name: "channel", Type: "string"},
+		{Name: "title", Type: "string"},
+		{Name: "text", Type: "string"},
+		{Name: "token", Type: "string"},
+		{Name: "channelId", Type: "string"},
+		{Name: "severityWebhooks", Type: "object"},
+		{Name: "severityChannels", Type: "object"},
+	},
+	"pagerduty": {
+		{Name: "integrationKey", Type: "string", Required: true},
+	},
+	"discord": {
+		{Name: "webhook", Type: "string", Required: true},
+		{Name: "title", Type: "string"},
+		{Name: "text", Type: "string"},
+		{Name: "severityWebhooks", Type: "object"},
+	},
+	"telegram": {
+		{Name: "token", Type: "string", Required: true},
+		{Name: "chatId", Type: "string", Required: true},
+	},
+	"teams": {
+		{Name: "webhook", Type: "string", Required: true},
+		{Name: "title", Type: "string"},
+		{Name: "text", Type: "string"},
+		{Name: "severityWebhooks", Type: "object"},
+		renderOption,
+	},
+	"email": {
+		{Name: "from", Type: "string", Required: true},
+		{Name: "password", Type: "string", Required: true},
+		{Name: "host", Type: "string", Required: true},
+		{Name: "port", Type: "string", Required: true},
+		{Name: "to", Type: "string", Required: true},
+	},
+	"rocketchat": {
+		{Name: "webhook", Type: "string", Required: true},
+		{Name: "text", Type: "string"},
+		renderOption,
+	},
+	"mattermost": {
+		{Name: "webhook", Type: "string", Required: true},
+		{Name: "title", Type: "string"},
+		{Name: "text", Type: "string"},
+		{Name: "severityWebhooks", Type: "object"},
+	},
+	"opsgenie": {
+		{Name: "apiKey", Type: "string", Required: true},
+		{Name: "title", Type: "string"},
+		{Name: "text", Type: "string"},
+	},
+	"matrix": {
+		{Name: "homeServer", Type: "string", Required: true},
+		{Name: "accessToken", Type: "string", Required: true},
+		{Name: "internalRoomId", Type: "string", Required: true},
+		{Name: "title", Type: "string"},
+		{Name: "text", Type: "string"},
+	},
+	"dingtalk": {
+		{Name: "accessToken", Type: "string", Required: true},
+		{Name: "secret", Type: "string"},
+		{Name: "title", Type: "string"},
+		renderOption,
+	},
+	"feishu": {
+		{Name: "webhook", Type: "string", Required: true},
+		{Name: "title", Type: "string"},
+		renderOption,
+	},
+	"webhook": {
+		{Name: "url", Type: "string", Required: true},
+		{Name: "headers", Type: "array"},
+		{Name: "basicAuth", Type: "object"},
+		{Name: "attachLogs", Type: "boolean"},
+		{Name: "signing", Type: "object"},
+		renderOption,
+	},
+	"zenduty": {
+		{Name: "integrationKey", Type: "string", Required: true},
+		{Name: "alertType", Type: "string"},
+	},
+	"googlechat": {
+		{Name: "webhook", Type: "string", Required: true},
+		{Name: "text", Type: "string"},
+		renderOption,
+	},
+}
+
+// GetProviderOptions returns options of provider including common ones
+func GetProviderOptions(name string) []ProviderOption {
+	options, ok := ProviderOptions[name]
+	if !ok {
+		return nil
+	}
+
+	return append(append([]ProviderOption{}, options...), commonOptions...)
+}
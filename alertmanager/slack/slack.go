@@ -7,15 +7,24 @@ import (
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/constant"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 
 	"github.com/sirupsen/logrus"
 	slackClient "github.com/slack-go/slack"
 )
 
 const (
-	chunkSize = 2000
+	chunkSize        = 2000
+	maxSectionFields = 10
 )
 
+// sectionIcons are emojis shown before titles of text sections
+var sectionIcons = map[string]string{
+	event.SectionEvents:   ":mag:",
+	event.SectionLogs:     ":memo:",
+	event.SectionCommands: ":wrench:",
+}
+
 type Slack struct {
 	webhook string
 	title   string
@@ -25,10 +34,23 @@ type Slack struct {
 	// instead of default one
 	channel string
 
+	// severityWebhooks and severityChannels are webhooks and channels
+	// events are sent to by their severity instead of default ones
+	severityWebhooks util.SeverityTargets
+	severityChannels util.SeverityTargets
+
+	// used to upload full logs as files to channelID, as webhooks don't
+	// support file uploads
+	token     string
+	channelID string
+
 	// reference for general app configuration
 	appCfg *config.App
 
-	send func(url string, msg *slackClient.WebhookMessage) error
+	send   func(url string, msg *slackClient.WebhookMessage) error
+	upload func(
+		params slackClient.UploadFileV2Parameters,
+	) (*slackClient.FileSummary, error)
 }
 
 // NewSlack returns new Slack instance
@@ -44,15 +66,54 @@ func NewSlack(config map[string]interface{}, appCfg *config.App) *Slack {
 	channel, _ := config["channel"].(string)
 	title, _ := config["title"].(string)
 	text, _ := config["text"].(string)
+	token, _ := config["token"].(string)
+	channelID, _ := config["channelId"].(string)
 
-	return &Slack{
-		webhook: webhook,
-		channel: channel,
-		title:   title,
-		text:    text,
-		send:    slackClient.PostWebhook,
-		appCfg:  appCfg,
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing slack with invalid http options: %s",
+			err)
+		return nil
 	}
+
+	severityWebhooks, err := util.NewSeverityTargets(
+		config,
+		"severityWebhooks")
+	if err != nil {
+		logrus.Warnf("initializing slack with invalid webhooks: %s", err)
+		return nil
+	}
+	severityChannels, err := util.NewSeverityTargets(
+		config,
+		"severityChannels")
+	if err != nil {
+		logrus.Warnf("initializing slack with invalid channels: %s", err)
+		return nil
+	}
+
+	s := &Slack{
+		webhook:          webhook,
+		channel:          channel,
+		severityWebhooks: severityWebhooks,
+		severityChannels: severityChannels,
+		title:            title,
+		text:             text,
+		token:            token,
+		channelID:        channelID,
+		send: func(url string, msg *slackClient.WebhookMessage) error {
+			return slackClient.PostWebhookCustomHTTP(url, client, msg)
+		},
+		appCfg: appCfg,
+	}
+
+	if len(token) > 0 && len(channelID) > 0 {
+		s.upload = slackClient.New(
+			token,
+			slackClient.OptionHTTPClient(client)).UploadFileV2
+	}
+
+	return s
 }
 
 // Name returns name of the provider
@@ -82,7 +143,9 @@ func (s *Slack) SendEvent(ev *event.Event) error {
 		slackClient.SectionBlock{
 			Type: "section",
 			Fields: []*slackClient.TextBlockObject{
-				markdownF("*Cluster*\n%s", s.appCfg.ClusterName),
+				markdownF(
+					"*Cluster*\n%s",
+					ev.GetClusterName(s.appCfg.ClusterName)),
 				markdownF("*Name*\n%s", ev.PodName),
 				markdownF("*Container*\n%s", ev.ContainerName),
 				markdownF("*Namespace*\n%s", ev.Namespace),
@@ -91,50 +154,106 @@ func (s *Slack) SendEvent(ev *event.Event) error {
 		},
 	}
 
-	// add events part if it exists
-	events := strings.TrimSpace(ev.Events)
-	if len(events) > 0 {
-		blocks = append(blocks,
-			markdownSection(":mag: *Events*"))
+	// add sections in configured order, adjacent fields are grouped and
+	// split in separate sections as each section is limited to 10 fields
+	fields := make([]*slackClient.TextBlockObject, 0)
+	for _, section := range ev.Sections() {
+		if section.IsFields() {
+			for _, field := range section.Fields {
+				fields = append(fields,
+					markdownF("*%s*\n%s", field.Name, field.Value))
+			}
+			continue
+		}
 
-		for _, chunk := range chunks(events, chunkSize) {
-			blocks = append(blocks,
-				markdownSectionF("```%s```", chunk))
+		blocks = appendFieldsSections(blocks, fields)
+		fields = fields[:0]
+
+		// add text part if it exists
+		sectionText := strings.TrimSpace(section.Text)
+		if len(sectionText) == 0 {
+			continue
 		}
-	}
 
-	// add logs part if it exists
-	logs := strings.TrimSpace(ev.Logs)
-	if len(logs) > 0 {
 		blocks = append(blocks,
-			markdownSection(":memo: *Logs*"))
+			markdownSectionF(
+				"%s *%s*",
+				sectionIcons[section.Name],
+				section.Title))
 
-		for _, chunk := range chunks(logs, chunkSize) {
+		for _, chunk := range chunks(sectionText, chunkSize) {
 			blocks = append(blocks,
 				markdownSectionF("```%s```", chunk))
 		}
 	}
+	blocks = appendFieldsSections(blocks, fields)
 
 	// send message
-	return s.sendAPI(&slackClient.WebhookMessage{
+	err := s.sendAPI(ev.Severity, &slackClient.WebhookMessage{
 		Blocks: &slackClient.Blocks{
 			BlockSet: append(blocks, markdownSection(constant.Footer)),
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	// message is already delivered, so failed upload isn't reported as
+	// failed send to avoid sending it again
+	if err := s.uploadLogs(ev); err != nil {
+		logrus.Warnf("failed to upload logs to slack: %s", err.Error())
+	}
+	return nil
+}
+
+// appendFieldsSections appends fields to blocks in sections of at most
+// maxSectionFields fields
+func appendFieldsSections(
+	blocks []slackClient.Block,
+	fields []*slackClient.TextBlockObject) []slackClient.Block {
+	for i := 0; i < len(fields); i += maxSectionFields {
+		end := min(i+maxSectionFields, len(fields))
+		blocks = append(blocks, slackClient.SectionBlock{
+			Type:   "section",
+			Fields: append([]*slackClient.TextBlockObject{}, fields[i:end]...),
+		})
+	}
+	return blocks
+}
+
+// uploadLogs uploads full logs as a file if they were captured and uploading
+// is configured
+func (s *Slack) uploadLogs(ev *event.Event) error {
+	if s.upload == nil || len(strings.TrimSpace(ev.FullLogs)) == 0 {
+		return nil
+	}
+
+	_, err := s.upload(slackClient.UploadFileV2Parameters{
+		Channel:  s.channelID,
+		Filename: ev.LogsFileName(),
+		Title:    ev.LogsFileName(),
+		Content:  ev.FullLogs,
+		FileSize: len(ev.FullLogs),
+	})
+	return err
 }
 
 // SendMessage sends text message to the provider
 func (s *Slack) SendMessage(msg string) error {
-	return s.sendAPI(&slackClient.WebhookMessage{
+	return s.sendAPI("", &slackClient.WebhookMessage{
 		Text: msg,
 	})
 }
 
-func (s *Slack) sendAPI(msg *slackClient.WebhookMessage) error {
-	if len(s.channel) > 0 {
-		msg.Channel = s.channel
+// sendAPI sends msg to webhook and channel of severity, or default ones
+func (s *Slack) sendAPI(
+	severity string,
+	msg *slackClient.WebhookMessage) error {
+	channel := s.severityChannels.Get(severity, s.channel)
+	if len(channel) > 0 {
+		msg.Channel = channel
 	}
-	return s.send(s.webhook, msg)
+	return s.send(s.severityWebhooks.Get(severity, s.webhook), msg)
 }
 
 func chunks(s string, chunkSize int) []string {
@@ -1,6 +1,7 @@
 package slack
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/abahmed/kwatch/config"
@@ -100,3 +101,71 @@ func TestSendEvent(t *testing.T) {
 	}
 	assert.Nil(s.SendEvent(&ev))
 }
+
+func TestSendEventUploadLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSlack(map[string]interface{}{
+		"webhook":   "testtest",
+		"token":     "xoxb-test",
+		"channelId": "C123",
+	}, &config.App{ClusterName: "dev"})
+	assert.NotNil(s)
+
+	var uploaded *slackClient.UploadFileV2Parameters
+	s.send = mockedSend
+	s.upload = func(
+		params slackClient.UploadFileV2Parameters,
+	) (*slackClient.FileSummary, error) {
+		uploaded = &params
+		return &slackClient.FileSummary{}, nil
+	}
+
+	ev := event.Event{
+		PodName:       "test-pod",
+		ContainerName: "test-container",
+		Logs:          "test",
+		FullLogs:      "full\ntest",
+	}
+	assert.Nil(s.SendEvent(&ev))
+	assert.NotNil(uploaded)
+	assert.Equal("C123", uploaded.Channel)
+	assert.Equal("test-pod-test-container.log", uploaded.Filename)
+	assert.Equal(len(ev.FullLogs), uploaded.FileSize)
+
+	s.upload = func(
+		params slackClient.UploadFileV2Parameters,
+	) (*slackClient.FileSummary, error) {
+		return nil, errors.New("upload failed")
+	}
+	assert.Nil(s.SendEvent(&ev))
+}
+
+func TestSendEventSeverityChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSlack(map[string]interface{}{
+		"webhook": "default",
+		"channel": "#alerts",
+		"severityWebhooks": map[string]interface{}{
+			"critical": "incidents",
+		},
+		"severityChannels": map[string]interface{}{
+			"critical": "#incidents",
+		},
+	}, &config.App{ClusterName: "dev"})
+	assert.NotNil(s)
+
+	sent := make([]string, 0)
+	s.send = func(url string, msg *slackClient.WebhookMessage) error {
+		sent = append(sent, url+" "+msg.Channel)
+		return nil
+	}
+
+	assert.Nil(s.SendEvent(&event.Event{Severity: "critical"}))
+	assert.Nil(s.SendEvent(&event.Event{Severity: "warning"}))
+	assert.Nil(s.SendMessage("test"))
+	assert.Equal(
+		[]string{"incidents #incidents", "default #alerts", "default #alerts"},
+		sent)
+}
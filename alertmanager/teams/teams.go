@@ -10,6 +10,7 @@ import (
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,6 +23,16 @@ type Teams struct {
 	title   string
 	text    string
 
+	// severityWebhooks are webhooks events are sent to by their severity
+	// instead of default one
+	severityWebhooks util.SeverityTargets
+
+	// format of event messages e.g. markdown
+	renderMode string
+
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -44,11 +55,34 @@ func NewTeams(config map[string]interface{}, appCfg *config.App) *Teams {
 	title, _ := config["title"].(string)
 	text, _ := config["text"].(string)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing teams with invalid http options: %s",
+			err)
+		return nil
+	}
+
+	severityWebhooks, err := util.NewSeverityTargets(
+		config,
+		"severityWebhooks")
+	if err != nil {
+		logrus.Warnf("initializing teams with invalid webhooks: %s", err)
+		return nil
+	}
+
 	return &Teams{
-		webhook: webhook,
-		title:   title,
-		text:    text,
-		appCfg:  appCfg,
+		client:           client,
+		webhook:          webhook,
+		severityWebhooks: severityWebhooks,
+		title:            title,
+		text:             text,
+		renderMode: event.GetRenderMode(
+			config,
+			event.RenderMarkdown,
+			event.RenderPlain,
+			event.RenderHTML),
+		appCfg: appCfg,
 	}
 }
 
@@ -59,7 +93,9 @@ func (t *Teams) Name() string {
 
 // SendEvent sends event to the provider
 func (t *Teams) SendEvent(e *event.Event) error {
-	return t.sendAPI(t.buildRequestBodyTeams(e))
+	return t.sendAPI(
+		t.severityWebhooks.Get(e.Severity, t.webhook),
+		t.buildRequestBodyTeams(e))
 }
 
 // SendMessage sends text message to the provider
@@ -70,19 +106,19 @@ func (t *Teams) SendMessage(msg string) error {
 	}
 
 	jsonBytes, _ := json.Marshal(msgPayload)
-	return t.sendAPI(jsonBytes)
+	return t.sendAPI(t.webhook, jsonBytes)
 }
 
-func (t *Teams) sendAPI(b []byte) error {
+func (t *Teams) sendAPI(webhook string, b []byte) error {
 	buffer := bytes.NewBuffer(b)
-	request, err := http.NewRequest(http.MethodPost, t.webhook, buffer)
+	request, err := http.NewRequest(http.MethodPost, webhook, buffer)
 	if err != nil {
 		return err
 	}
 
 	request.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
+	client := t.client
 	response, err := client.Do(request)
 	if err != nil {
 		return err
@@ -108,7 +144,11 @@ func (t *Teams) buildRequestBodyTeams(e *event.Event) []byte {
 		title = defaultTeamsTitle
 	}
 
-	msg := e.FormatMarkdown(t.appCfg.ClusterName, t.text, "\n\n")
+	msg := e.Render(t.renderMode, event.RenderOptions{
+		ClusterName: e.GetClusterName(t.appCfg.ClusterName),
+		Text:        t.text,
+		Delimiter:   "\n\n",
+	})
 	msgPayload := &teamsWebhookPayload{
 		Title: title,
 		Text:  msg,
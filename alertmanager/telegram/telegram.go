@@ -8,6 +8,7 @@ import (
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +21,9 @@ type Telegram struct {
 	chatId string
 	url    string
 
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -44,7 +48,16 @@ func NewTelegram(config map[string]interface{}, appCfg *config.App) *Telegram {
 		chatId)
 
 	// returns a new telegram object
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing telegram with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &Telegram{
+		client: client,
 		token:  token,
 		chatId: chatId,
 		url:    telegramAPIURL,
@@ -77,36 +90,39 @@ func (t *Telegram) buildRequestBodyTelegram(
 	e *event.Event,
 	chatId string,
 	customMsg string) string {
-	eventsText := "No events captured"
-	logsText := "No logs captured"
-
-	// add events part if it exists
-	events := strings.TrimSpace(e.Events)
-	if len(events) > 0 {
-		eventsText = e.Events
-	}
-
-	// add logs part if it exists
-	logs := strings.TrimSpace(e.Logs)
-	if len(logs) > 0 {
-		logsText = e.Logs
-	}
-
 	// build text will be sent in the message
 	txt := ""
 	if len(customMsg) <= 0 {
+		extraText := ""
+		sectionsText := make([]string, 0)
+		for _, section := range e.Sections() {
+			if section.IsFields() {
+				for _, field := range section.Fields {
+					extraText += fmt.Sprintf(
+						"%s: *%s* ",
+						util.JsonEscape(field.Name),
+						util.JsonEscape(field.Value))
+				}
+				continue
+			}
+
+			sectionsText = append(sectionsText, fmt.Sprintf(
+				"%s: *%s* ",
+				section.Title,
+				util.JsonEscape(section.DisplayText())))
+		}
+
 		txt = fmt.Sprintf(
 			"An alert for Cluster: *%s* Name: *%s*  "+
 				"Container: *%s* "+
-				"Namespace: *%s*  has been triggered:\\n—\\n "+
-				"Logs: *%s* \\n "+
-				"Events: *%s* ",
-			t.appCfg.ClusterName,
+				"Namespace: *%s*  %shas been triggered:\\n—\\n "+
+				"%s",
+			e.GetClusterName(t.appCfg.ClusterName),
 			e.PodName,
 			e.ContainerName,
 			e.Namespace,
-			logsText,
-			eventsText,
+			extraText,
+			strings.Join(sectionsText, "\\n "),
 		)
 	} else {
 		txt = customMsg
@@ -127,7 +143,7 @@ func (t *Telegram) buildRequestBodyTelegram(
 }
 
 func (t *Telegram) sendByTelegramApi(reqBody string) error {
-	client := &http.Client{}
+	client := t.client
 	buffer := bytes.NewBuffer([]byte(reqBody))
 	url := fmt.Sprintf(t.url, t.token)
 
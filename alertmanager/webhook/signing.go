@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSignatureHeader is header signature is sent in by default
+	defaultSignatureHeader = "X-Kwatch-Signature"
+
+	// defaultSignatureTolerance is max age of signature accepted by Verify
+	// by default
+	defaultSignatureTolerance = 5 * time.Minute
+)
+
+// Signer signs payloads with HMAC-SHA256, so receivers can authenticate
+// that they were sent by kwatch
+type Signer struct {
+	secret []byte
+	header string
+
+	// tolerance is max age of signature timestamp, older signatures are
+	// rejected to prevent replays
+	tolerance time.Duration
+}
+
+// NewSigner returns new instance of signer of signing option e.g.
+// {secret: <secret>, header: X-Signature, tolerance: 300}, or nil if it
+// isn't set
+func NewSigner(opt interface{}) (*Signer, error) {
+	if opt == nil {
+		return nil, nil
+	}
+
+	m, ok := opt.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("signing must be an object")
+	}
+
+	secret, _ := m["secret"].(string)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("signing secret is required")
+	}
+
+	signer := &Signer{
+		secret:    []byte(secret),
+		header:    defaultSignatureHeader,
+		tolerance: defaultSignatureTolerance,
+	}
+	if header, _ := m["header"].(string); len(header) > 0 {
+		signer.header = header
+	}
+
+	switch tolerance := m["tolerance"].(type) {
+	case nil:
+	case int:
+		signer.tolerance = time.Duration(tolerance) * time.Second
+	case float64:
+		signer.tolerance = time.Duration(tolerance * float64(time.Second))
+	default:
+		return nil, fmt.Errorf("signing tolerance must be seconds")
+	}
+	if signer.tolerance <= 0 {
+		return nil, fmt.Errorf("signing tolerance must be positive")
+	}
+
+	return signer, nil
+}
+
+// Header returns name of header signature is sent in
+func (s *Signer) Header() string {
+	return s.header
+}
+
+// Sign returns signature of body at time now e.g. t=1700000000,v1=<hex>,
+// which is HMAC-SHA256 of timestamp and body joined by a dot
+func (s *Signer) Sign(body []byte, now time.Time) string {
+	timestamp := now.Unix()
+	return fmt.Sprintf(
+		"t=%d,v1=%s",
+		timestamp,
+		hex.EncodeToString(s.mac(timestamp, body)))
+}
+
+// Verify returns error if signature doesn't match body or its timestamp is
+// out of tolerance at time now
+func (s *Signer) Verify(signature string, body []byte, now time.Time) error {
+	var timestamp int64
+	var mac []byte
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			mac, _ = hex.DecodeString(value)
+		}
+	}
+	if timestamp == 0 || len(mac) == 0 {
+		return fmt.Errorf("malformed signature")
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > s.tolerance || age < -s.tolerance {
+		return fmt.Errorf("signature timestamp is out of tolerance")
+	}
+
+	if !hmac.Equal(mac, s.mac(timestamp, body)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// mac returns HMAC-SHA256 of timestamp and body
+func (s *Signer) mac(timestamp int64, body []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(h, "%d.", timestamp)
+	h.Write(body)
+	return h.Sum(nil)
+}
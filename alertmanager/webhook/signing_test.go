@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	signer, err := NewSigner(nil)
+	assert.Nil(err)
+	assert.Nil(signer)
+
+	signer, err = NewSigner(map[string]interface{}{"secret": "s3cr3t"})
+	assert.Nil(err)
+	assert.Equal(defaultSignatureHeader, signer.Header())
+	assert.Equal(defaultSignatureTolerance, signer.tolerance)
+
+	signer, err = NewSigner(map[string]interface{}{
+		"secret":    "s3cr3t",
+		"header":    "X-Signature",
+		"tolerance": 60,
+	})
+	assert.Nil(err)
+	assert.Equal("X-Signature", signer.Header())
+	assert.Equal(time.Minute, signer.tolerance)
+
+	_, err = NewSigner("s3cr3t")
+	assert.NotNil(err)
+
+	_, err = NewSigner(map[string]interface{}{"header": "X-Signature"})
+	assert.NotNil(err)
+
+	_, err = NewSigner(map[string]interface{}{
+		"secret":    "s3cr3t",
+		"tolerance": "1m",
+	})
+	assert.NotNil(err)
+
+	_, err = NewSigner(map[string]interface{}{
+		"secret":    "s3cr3t",
+		"tolerance": 0,
+	})
+	assert.NotNil(err)
+}
+
+func TestSignerVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	signer, _ := NewSigner(map[string]interface{}{"secret": "s3cr3t"})
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"Reason":"OOMKilled"}`)
+
+	signature := signer.Sign(body, now)
+	assert.Regexp(`^t=1700000000,v1=[0-9a-f]{64}$`, signature)
+
+	assert.Nil(signer.Verify(signature, body, now.Add(time.Minute)))
+	assert.NotNil(signer.Verify(signature, []byte(`{}`), now))
+	assert.NotNil(signer.Verify(signature, body, now.Add(time.Hour)))
+	assert.NotNil(signer.Verify("v1=abc", body, now))
+
+	other, _ := NewSigner(map[string]interface{}{"secret": "other"})
+	assert.NotNil(other.Verify(signature, body, now))
+}
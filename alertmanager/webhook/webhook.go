@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
@@ -24,12 +26,30 @@ type Authentication struct {
 	Password string `json:"password"`
 }
 
+// defaultRenderMode sends the default flat payload
+const defaultRenderMode = "default"
+
 type Webhook struct {
 	webhook  string
 	headers  []KeyValue
 	username string
 	password string
-	appCfg   *config.App
+
+	// attachLogs if set to true, full logs are sent as a file in
+	// multipart/form-data requests when they are captured
+	attachLogs bool
+
+	// format of event requests, either default payload or json which
+	// contains sections in configured order
+	renderMode string
+
+	// signer signs request bodies if signing is configured
+	signer *Signer
+
+	// client sends requests through proxy of provider
+	client *http.Client
+
+	appCfg *config.App
 }
 
 func (w *Webhook) SendMessage(msg string) error {
@@ -61,15 +81,38 @@ func NewWebhook(config map[string]interface{}, appCfg *config.App) *Webhook {
 	var a Authentication
 	json.Unmarshal(basicAuthJson, &a)
 
+	attachLogs, _ := config["attachLogs"].(bool)
+
 	logrus.Infof("initializing  with webhook url: %s "+
 		"with headers: %s and username: %s", url, headers, a.UserName)
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing webhook with invalid http options: %s",
+			err)
+		return nil
+	}
+
+	signer, err := NewSigner(config["signing"])
+	if err != nil {
+		logrus.Warnf("initializing webhook with invalid signing: %s", err)
+		return nil
+	}
+
 	return &Webhook{
-		webhook:  url,
-		headers:  headers,
-		username: a.UserName,
-		password: a.Password,
-		appCfg:   appCfg,
+		client:     client,
+		signer:     signer,
+		webhook:    url,
+		headers:    headers,
+		username:   a.UserName,
+		password:   a.Password,
+		attachLogs: attachLogs,
+		renderMode: event.GetRenderMode(
+			config,
+			defaultRenderMode,
+			event.RenderJSON),
+		appCfg: appCfg,
 	}
 }
 
@@ -80,9 +123,17 @@ func (w *Webhook) Name() string {
 
 // SendEvent sends event to the provider
 func (w *Webhook) SendEvent(ev *event.Event) error {
-	client := &http.Client{}
+	client := w.client
 
 	reqBody := w.buildRequestBody(ev)
+	contentType := "application/json"
+	if w.attachLogs && len(strings.TrimSpace(ev.FullLogs)) > 0 {
+		var err error
+		reqBody, contentType, err = w.buildMultipartBody(reqBody, ev)
+		if err != nil {
+			return err
+		}
+	}
 	buffer := bytes.NewBuffer(reqBody)
 
 	request, err := http.NewRequest(http.MethodPost, w.webhook, buffer)
@@ -90,12 +141,19 @@ func (w *Webhook) SendEvent(ev *event.Event) error {
 		return err
 	}
 
+	request.Header.Set("Content-Type", contentType)
+
 	for _, header := range w.headers {
 		request.Header.Set(header.Name, header.Value)
 	}
 	if len(w.username) > 0 && len(w.password) > 0 {
 		request.SetBasicAuth(w.username, w.password)
 	}
+	if w.signer != nil {
+		request.Header.Set(
+			w.signer.Header(),
+			w.signer.Sign(reqBody, time.Now()))
+	}
 
 	response, err := client.Do(request)
 	if err != nil {
@@ -112,9 +170,42 @@ func (w *Webhook) SendEvent(ev *event.Event) error {
 	return nil
 }
 
+// buildMultipartBody wraps json payload and full logs file in a
+// multipart/form-data body
+func (w *Webhook) buildMultipartBody(
+	payload []byte,
+	ev *event.Event) ([]byte, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("payload", string(payload)); err != nil {
+		return nil, "", err
+	}
+
+	part, err := writer.CreateFormFile("logs", ev.LogsFileName())
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := part.Write([]byte(ev.FullLogs)); err != nil {
+		return nil, "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
 func (w *Webhook) buildRequestBody(
 	ev *event.Event,
 ) []byte {
+	if w.renderMode == event.RenderJSON {
+		clusterName := ev.GetClusterName(w.appCfg.ClusterName)
+		return []byte(ev.FormatJSON(clusterName, ""))
+	}
+
 	eventsText := "No events captured"
 	logsText := "No logs captured"
 
@@ -130,16 +221,33 @@ func (w *Webhook) buildRequestBody(
 		logsText = util.JsonEscape(ev.Logs)
 	}
 
-	postBody, _ := json.Marshal(map[string]interface{}{
-		"Cluster":   w.appCfg.ClusterName,
+	fields := make(map[string]string)
+	for _, field := range ev.ExtraFields() {
+		fields[field.Name] = field.Value
+	}
+
+	body := map[string]interface{}{
+		"Cluster":   ev.GetClusterName(w.appCfg.ClusterName),
 		"Name":      ev.PodName,
 		"Container": ev.ContainerName,
 		"Namespace": ev.Namespace,
 		"Reason":    ev.Reason,
-		"Events":    eventsText,
-		"Logs":      logsText,
 		"Labels":    ev.Labels,
-	})
+		"Fields":    fields,
+	}
+
+	// add only sections configured to be shown
+	if ev.HasSection(event.SectionEvents) {
+		body["Events"] = eventsText
+	}
+	if ev.HasSection(event.SectionLogs) {
+		body["Logs"] = logsText
+	}
+	if ev.HasSection(event.SectionCommands) {
+		body["Commands"] = ev.Commands
+	}
+
+	postBody, _ := json.Marshal(body)
 
 	return postBody
 }
@@ -1,9 +1,12 @@
 package webhook
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/abahmed/kwatch/event"
@@ -172,3 +175,145 @@ func TestInvaildHttpRequest(t *testing.T) {
 
 	assert.NotNil(assert.NotNil(c.SendEvent(&ev)))
 }
+
+func TestSendEventAttachLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	s := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			file, header, err := r.FormFile("logs")
+			if err != nil || len(r.FormValue("payload")) == 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+
+			if header.Filename != "test-pod-test-container.log" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"isOk": true}`))
+		}))
+
+	defer s.Close()
+
+	configMap := map[string]interface{}{
+		"url":        s.URL,
+		"attachLogs": true,
+	}
+	c := NewWebhook(configMap, &config.App{ClusterName: "dev"})
+	assert.NotNil(c)
+
+	ev := event.Event{
+		PodName:       "test-pod",
+		ContainerName: "test-container",
+		Namespace:     "default",
+		Reason:        "OOMKILLED",
+		Logs:          "testlogs",
+		FullLogs:      "test\ntestlogs",
+	}
+	assert.Nil(c.SendEvent(&ev))
+}
+
+func TestBuildRequestBodyFields(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewWebhook(
+		map[string]interface{}{"url": "test"},
+		&config.App{ClusterName: "dev"})
+	assert.NotNil(c)
+
+	ev := event.Event{
+		PodName: "test-pod",
+		PodMetadata: []event.Field{
+			{Name: "team", Value: "platform"},
+		},
+	}
+
+	var body map[string]interface{}
+	assert.Nil(json.Unmarshal(c.buildRequestBody(&ev), &body))
+	assert.Equal(
+		map[string]interface{}{"team": "platform"},
+		body["Fields"])
+}
+
+func TestBuildRequestBodyJSONRenderMode(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewWebhook(
+		map[string]interface{}{"url": "test", "renderMode": "json"},
+		&config.App{ClusterName: "dev"})
+	assert.NotNil(c)
+
+	ev := event.Event{
+		PodName: "test-pod",
+		Logs:    "testlogs",
+		PodMetadata: []event.Field{
+			{Name: "team", Value: "platform"},
+		},
+	}
+
+	var body struct {
+		Cluster  string `json:"cluster"`
+		Pod      string `json:"pod"`
+		Sections []struct {
+			Name   string            `json:"name"`
+			Fields map[string]string `json:"fields"`
+			Text   string            `json:"text"`
+		} `json:"sections"`
+	}
+	assert.Nil(json.Unmarshal(
+		c.buildRequestBody(ev.WithSections([]string{"logs", "labels"})),
+		&body))
+	assert.Equal("dev", body.Cluster)
+	assert.Equal("test-pod", body.Pod)
+	assert.Len(body.Sections, 2)
+	assert.Equal("logs", body.Sections[0].Name)
+	assert.Equal("testlogs", body.Sections[0].Text)
+	assert.Equal(map[string]string{"team": "platform"}, body.Sections[1].Fields)
+
+	c = NewWebhook(
+		map[string]interface{}{"url": "test", "renderMode": "html"},
+		&config.App{ClusterName: "dev"})
+	assert.Equal(defaultRenderMode, c.renderMode)
+}
+
+func TestSendEventSigned(t *testing.T) {
+	assert := assert.New(t)
+
+	var signature string
+	var body []byte
+	s := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature = r.Header.Get("X-Signature")
+			body, _ = io.ReadAll(r.Body)
+		}))
+	defer s.Close()
+
+	configMap := map[string]interface{}{
+		"url": s.URL,
+		"signing": map[string]interface{}{
+			"secret": "s3cr3t",
+			"header": "X-Signature",
+		},
+	}
+	c := NewWebhook(configMap, &config.App{ClusterName: "dev"})
+	assert.NotNil(c)
+
+	ev := event.Event{
+		PodName:   "test-pod",
+		Namespace: "default",
+		Reason:    "OOMKILLED",
+	}
+	assert.Nil(c.SendEvent(&ev))
+	assert.NotEmpty(signature)
+	assert.Nil(c.signer.Verify(signature, body, time.Now()))
+
+	configMap["signing"] = map[string]interface{}{"header": "X-Signature"}
+	assert.Nil(NewWebhook(configMap, &config.App{ClusterName: "dev"}))
+}
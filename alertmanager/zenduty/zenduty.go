@@ -9,8 +9,8 @@ import (
 	"slices"
 
 	"github.com/abahmed/kwatch/config"
-	"github.com/abahmed/kwatch/constant"
 	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -34,6 +34,9 @@ type Zenduty struct {
 	url            string
 	alertType      string
 
+	// client sends requests through proxy of provider
+	client *http.Client
+
 	// reference for general app configuration
 	appCfg *config.App
 }
@@ -61,7 +64,16 @@ func NewZenduty(config map[string]interface{}, appCfg *config.App) *Zenduty {
 		alertType = "critical"
 	}
 
+	client, err := util.NewHTTPClient(config)
+	if err != nil {
+		logrus.Warnf(
+			"initializing zenduty with invalid http options: %s",
+			err)
+		return nil
+	}
+
 	return &Zenduty{
+		client:         client,
 		integrationkey: integrationKey,
 		url:            zendutyAPIURL,
 		alertType:      alertType,
@@ -86,7 +98,7 @@ func (m *Zenduty) SendEvent(e *event.Event) error {
 
 // sendAPI sends http request to Zenduty API
 func (m *Zenduty) sendAPI(content []byte) error {
-	client := &http.Client{}
+	client := m.client
 	buffer := bytes.NewBuffer(content)
 	url := m.url + "/" + m.integrationkey + "/"
 	request, err := http.NewRequest(http.MethodPost, url, buffer)
@@ -116,16 +128,6 @@ func (m *Zenduty) buildMessage(e *event.Event) []byte {
 		AlertType: m.alertType,
 	}
 
-	logs := constant.DefaultLogs
-	if len(e.Logs) > 0 {
-		logs = (e.Logs)
-	}
-
-	events := constant.DefaultEvents
-	if len(e.Events) > 0 {
-		events = (e.Events)
-	}
-
 	payload.Message = fmt.Sprintf(defaultZendutyTitle, e.PodName)
 	payload.Summary = fmt.Sprintf(
 		"An alert has been triggered for\n\n"+
@@ -133,16 +135,14 @@ func (m *Zenduty) buildMessage(e *event.Event) []byte {
 			"Pod Name: %s\n"+
 			"Container: %s\n"+
 			"Namespace: %s\n"+
-			"Reason: %s\n\n"+
-			"Events:\n%s\n\n"+
-			"Logs:\n%s\n\n",
-		m.appCfg.ClusterName,
+			"Reason: %s\n"+
+			"%s",
+		e.GetClusterName(m.appCfg.ClusterName),
 		e.PodName,
 		e.ContainerName,
 		e.Namespace,
 		e.Reason,
-		events,
-		logs,
+		e.FormatSectionsText(),
 	)
 
 	str, _ := json.Marshal(payload)
@@ -0,0 +1,5 @@
+// Package kwatchv1 contains protobuf messages and gRPC services of kwatch
+// API, they're generated from alerts.proto
+package kwatchv1
+
+//go:generate protoc --proto_path=../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/kwatch/v1/alerts.proto
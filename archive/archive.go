@@ -0,0 +1,212 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/export"
+	"github.com/abahmed/kwatch/util"
+	"github.com/sirupsen/logrus"
+)
+
+type Archiver struct {
+	config *config.Archive
+	creds  *credentials
+	client *http.Client
+
+	mu      sync.Mutex
+	records []*export.Record
+
+	// full is signaled when batch reaches max size
+	full chan struct{}
+}
+
+// NewArchiver returns new instance of archiver
+func NewArchiver(config *config.Archive) *Archiver {
+	creds := &credentials{
+		accessKeyID:     config.AccessKeyID,
+		secretAccessKey: config.SecretAccessKey,
+		sessionToken:    config.SessionToken,
+	}
+	if len(creds.accessKeyID) == 0 {
+		creds.accessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		creds.secretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if len(creds.sessionToken) == 0 {
+		creds.sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+
+	return &Archiver{
+		config: config,
+		creds:  creds,
+		client: &http.Client{
+			Timeout: time.Duration(config.Timeout) * time.Second,
+		},
+		records: make([]*export.Record, 0),
+		full:    make(chan struct{}, 1),
+	}
+}
+
+// Enabled returns true if archive is enabled
+func (a *Archiver) Enabled() bool {
+	return a != nil && a.config.Enabled && len(a.config.Bucket) > 0
+}
+
+// Add adds sent alert to current batch
+func (a *Archiver) Add(ev *event.Event) {
+	if !a.Enabled() {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.records = append(a.records, export.NewRecord(ev, false))
+	if a.config.MaxBatchSize > 0 && len(a.records) >= a.config.MaxBatchSize {
+		select {
+		case a.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start uploads batches on configured interval, or once they're full, until
+// stopCh is closed, then the last batch is uploaded
+func (a *Archiver) Start(stopCh <-chan struct{}) {
+	if !a.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(a.config.Interval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.Flush()
+		case <-a.full:
+			a.Flush()
+		case <-stopCh:
+			a.Flush()
+			return
+		}
+	}
+}
+
+// Flush uploads current batch, records of failed uploads are kept for next
+// batch
+func (a *Archiver) Flush() {
+	a.mu.Lock()
+	records := a.records
+	a.records = make([]*export.Record, 0)
+	a.mu.Unlock()
+
+	if len(records) == 0 {
+		return
+	}
+
+	key := a.objectKey(time.Now())
+	err := a.upload(key, records)
+	if err == nil {
+		logrus.WithFields(logrus.Fields{
+			"key":     key,
+			"records": len(records),
+		}).Info("uploaded alerts archive")
+		return
+	}
+
+	logrus.WithField("key", key).
+		WithError(err).
+		Error("failed to upload alerts archive")
+
+	// keep failed records unless batch would exceed its max size, oldest
+	// records are dropped then
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(records, a.records...)
+	if max := a.config.MaxBatchSize; max > 0 && len(a.records) > max {
+		logrus.WithField("dropped", len(a.records)-max).
+			Warn("dropping oldest archived alerts as batch is full")
+		a.records = a.records[len(a.records)-max:]
+	}
+}
+
+// objectKey returns key of batch uploaded at given time e.g.
+// kwatch/2024/01/02/150405-x1y2z3.jsonl.gz
+func (a *Archiver) objectKey(now time.Time) string {
+	now = now.UTC()
+	return fmt.Sprintf(
+		"%s%s/%s-%s.jsonl.gz",
+		a.config.Prefix,
+		now.Format("2006/01/02"),
+		now.Format("150405"),
+		strings.ToLower(util.RandomString(6)))
+}
+
+// upload uploads records as gzip compressed json lines object
+func (a *Archiver) upload(key string, records []*export.Record) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	payload := buf.Bytes()
+	req, err := http.NewRequest(
+		http.MethodPut,
+		a.objectURL(key),
+		bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+
+	signRequest(req, a.creds, a.config.Region, hashHex(payload), time.Now())
+
+	response, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf(
+			"call to object storage returned status code %d: %s",
+			response.StatusCode,
+			string(body))
+	}
+
+	return nil
+}
+
+// objectURL returns URL of object, path style is used with custom
+// endpoints e.g. GCS and MinIO, otherwise AWS S3 virtual hosted style
+func (a *Archiver) objectURL(key string) string {
+	if len(a.config.Endpoint) > 0 {
+		return strings.TrimSuffix(a.config.Endpoint, "/") +
+			"/" + a.config.Bucket + "/" + key
+	}
+
+	return fmt.Sprintf(
+		"https://%s.s3.%s.amazonaws.com/%s",
+		a.config.Bucket,
+		a.config.Region,
+		key)
+}
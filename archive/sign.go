@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm  = "AWS4-HMAC-SHA256"
+	signService    = "s3"
+	amzDateFormat  = "20060102T150405Z"
+	amzShortFormat = "20060102"
+)
+
+// credentials used to sign requests
+type credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// signRequest signs request with AWS signature version 4, host and all set
+// headers are signed
+func signRequest(
+	req *http.Request,
+	creds *credentials,
+	region string,
+	payloadHash string,
+	now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if len(creds.sessionToken) > 0 {
+		req.Header.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(
+			strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf(
+		"%s/%s/%s/aws4_request",
+		now.Format(amzShortFormat),
+		region,
+		signService)
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		now.Format(amzDateFormat),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.secretAccessKey),
+		now.Format(amzShortFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, signService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm,
+		creds.accessKeyID,
+		scope,
+		signedHeaders,
+		signature))
+}
+
+// hashHex returns hex encoded sha256 hash of data
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	StatusSent   = "sent"
+	StatusFailed = "failed"
+)
+
+// Record is an audit entry of an outgoing notification
+type Record struct {
+	Time        time.Time `json:"time"`
+	Provider    string    `json:"provider"`
+	Target      string    `json:"target"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	LatencyMs   int64     `json:"latencyMs"`
+	PayloadHash string    `json:"payloadHash"`
+}
+
+type Auditor struct {
+	config *config.Audit
+	client *http.Client
+
+	mu sync.Mutex
+}
+
+// NewAuditor returns new instance of auditor
+func NewAuditor(config *config.Audit) *Auditor {
+	return &Auditor{
+		config: config,
+		client: &http.Client{
+			Timeout: time.Duration(config.Timeout) * time.Second,
+		},
+	}
+}
+
+// Enabled returns true if audit log is enabled
+func (a *Auditor) Enabled() bool {
+	return a != nil && a.config.Enabled
+}
+
+// HashPayload returns sha256 hash of notification payload
+func HashPayload(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Record writes record to configured file and sends it to configured API
+func (a *Auditor) Record(record *Record) {
+	if !a.Enabled() {
+		return
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		logrus.WithError(err).Error("failed to marshal audit record")
+		return
+	}
+
+	if len(a.config.Path) > 0 {
+		if err := a.writeFile(data); err != nil {
+			logrus.WithField("path", a.config.Path).
+				WithError(err).
+				Error("failed to write audit record")
+		}
+	}
+
+	if len(a.config.URL) > 0 {
+		go func() {
+			if err := a.post(data); err != nil {
+				logrus.WithField("url", a.config.URL).
+					WithError(err).
+					Error("failed to send audit record")
+			}
+		}()
+	}
+}
+
+// writeFile appends record as a json line to configured file
+func (a *Auditor) writeFile(data []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	f, err := os.OpenFile(
+		a.config.Path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// post sends record to configured API
+func (a *Auditor) post(data []byte) error {
+	request, err := http.NewRequest(
+		http.MethodPost,
+		a.config.URL,
+		bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := a.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf(
+			"call to audit API returned status code %d",
+			response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditorDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	var a *Auditor
+	assert.False(a.Enabled())
+	a.Record(&Record{Provider: "slack"})
+
+	assert.False(NewAuditor(&config.Audit{}).Enabled())
+}
+
+func TestRecordFile(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	a := NewAuditor(&config.Audit{Enabled: true, Path: path})
+
+	a.Record(&Record{Provider: "slack", Status: StatusSent})
+	a.Record(&Record{Provider: "email", Status: StatusFailed})
+
+	data, err := os.ReadFile(path)
+	assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(lines, 2)
+
+	var record Record
+	assert.Nil(json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal("email", record.Provider)
+	assert.Equal(StatusFailed, record.Status)
+}
+
+func TestRecordAPI(t *testing.T) {
+	assert := assert.New(t)
+
+	received := make(chan Record, 1)
+	s := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var record Record
+			json.Unmarshal(body, &record)
+			received <- record
+		}))
+	defer s.Close()
+
+	a := NewAuditor(&config.Audit{Enabled: true, URL: s.URL, Timeout: 5})
+	a.Record(&Record{
+		Provider:    "slack",
+		PayloadHash: HashPayload([]byte("test")),
+	})
+
+	select {
+	case record := <-received:
+		assert.Equal("slack", record.Provider)
+		assert.Equal(HashPayload([]byte("test")), record.PayloadHash)
+	case <-time.After(5 * time.Second):
+		t.Fatal("audit record was not sent")
+	}
+}
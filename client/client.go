@@ -1,39 +1,30 @@
 package client
 
 import (
+	"context"
+	"io"
 	"net/http"
-	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/abahmed/kwatch/config"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
 // Create returns kubernetes client after initializing it with in-cluster, or
-// out of cluster config
-func Create(appConfig *config.App) kubernetes.Interface {
-	// try to use in cluster config
-	clientConfig, err := rest.InClusterConfig()
+// out of cluster config. If kubeconfig or context is configured, out of
+// cluster config is used
+func Create(
+	appConfig *config.App,
+	kubeConfig *config.Kubernetes) kubernetes.Interface {
+	clientConfig, err := getConfig(kubeConfig)
 	if err != nil {
-		logrus.Warnf("cannot get kubernetes in cluster config: %v", err)
-
-		// try to use out of cluster config
-		kubeconfigPath := os.Getenv("KUBECONFIG")
-		if kubeconfigPath == "" {
-			home := homedir.HomeDir()
-			kubeconfigPath = filepath.Join(home, ".kube", "config")
-		}
-
-		clientConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-		if err != nil {
-			logrus.Fatalf(
-				"cannot build kubernetes out of cluster config: %v",
-				err)
-		}
+		logrus.Fatalf("cannot build kubernetes out of cluster config: %v", err)
 	}
 
 	// avoid using default app proxy if it's set
@@ -41,6 +32,8 @@ func Create(appConfig *config.App) kubernetes.Interface {
 		clientConfig.Proxy = http.ProxyURL(nil)
 	}
 
+	applyConfig(clientConfig, kubeConfig)
+
 	// creates the clientset
 	clientset, err := kubernetes.NewForConfig(clientConfig)
 	if err != nil {
@@ -51,3 +44,208 @@ func Create(appConfig *config.App) kubernetes.Interface {
 
 	return clientset
 }
+
+// CreateDynamic returns dynamic kubernetes client of the cluster kwatch runs
+// in, it's used to watch kwatch custom resources
+func CreateDynamic(
+	appConfig *config.App,
+	kubeConfig *config.Kubernetes) dynamic.Interface {
+	clientConfig, err := getConfig(kubeConfig)
+	if err != nil {
+		logrus.Fatalf("cannot build kubernetes out of cluster config: %v", err)
+	}
+
+	// avoid using default app proxy if it's set
+	if len(appConfig.ProxyURL) > 0 && clientConfig.Proxy == nil {
+		clientConfig.Proxy = http.ProxyURL(nil)
+	}
+
+	applyConfig(clientConfig, kubeConfig)
+
+	// custom resources are only served as json
+	clientConfig.ContentType = runtime.ContentTypeJSON
+	clientConfig.AcceptContentTypes = runtime.ContentTypeJSON
+
+	dynamicClient, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		logrus.Fatalf("cannot create kubernetes dynamic client: %v", err)
+	}
+
+	return dynamicClient
+}
+
+// CreateDynamicForCluster returns dynamic kubernetes client of given
+// cluster, it's used to watch custom resources of the cluster
+func CreateDynamicForCluster(
+	cluster *config.Cluster,
+	appConfig *config.App,
+	kubeConfig *config.Kubernetes) dynamic.Interface {
+	clientConfig, err := getClusterConfig(cluster)
+	if err != nil {
+		logrus.WithField("cluster", cluster.Name).
+			Fatalf("cannot build kubernetes config: %v", err)
+	}
+
+	// avoid using default app proxy if it's set
+	if len(appConfig.ProxyURL) > 0 && clientConfig.Proxy == nil {
+		clientConfig.Proxy = http.ProxyURL(nil)
+	}
+
+	applyConfig(clientConfig, kubeConfig)
+
+	// custom resources are only served as json
+	clientConfig.ContentType = runtime.ContentTypeJSON
+	clientConfig.AcceptContentTypes = runtime.ContentTypeJSON
+
+	dynamicClient, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		logrus.WithField("cluster", cluster.Name).
+			Fatalf("cannot create kubernetes dynamic client: %v", err)
+	}
+
+	return dynamicClient
+}
+
+// CreateForCluster returns kubernetes client of given cluster, either using
+// its API server and service account token, or its kubeconfig context
+func CreateForCluster(
+	cluster *config.Cluster,
+	appConfig *config.App,
+	kubeConfig *config.Kubernetes) kubernetes.Interface {
+	clientConfig, err := getClusterConfig(cluster)
+	if err != nil {
+		logrus.WithField("cluster", cluster.Name).
+			Fatalf("cannot build kubernetes config: %v", err)
+	}
+
+	// avoid using default app proxy if it's set
+	if len(appConfig.ProxyURL) > 0 && clientConfig.Proxy == nil {
+		clientConfig.Proxy = http.ProxyURL(nil)
+	}
+
+	applyConfig(clientConfig, kubeConfig)
+
+	clientset, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		logrus.WithField("cluster", cluster.Name).
+			Fatalf("cannot create kubernetes client: %v", err)
+	}
+
+	logrus.WithField("cluster", cluster.Name).
+		Debugf("created kubernetes client successfully")
+
+	return clientset
+}
+
+// getConfig returns in cluster config unless kubeconfig or context is
+// configured, it falls back to kubeconfig when not running in cluster
+func getConfig(kubeConfig *config.Kubernetes) (*rest.Config, error) {
+	outOfCluster := &config.Cluster{
+		Kubeconfig: kubeConfig.Kubeconfig,
+		Context:    kubeConfig.Context,
+	}
+
+	if len(kubeConfig.Kubeconfig) > 0 || len(kubeConfig.Context) > 0 {
+		return getClusterConfig(outOfCluster)
+	}
+
+	// try to use in cluster config
+	clientConfig, err := rest.InClusterConfig()
+	if err == nil {
+		return clientConfig, nil
+	}
+	logrus.Warnf("cannot get kubernetes in cluster config: %v", err)
+
+	// try to use out of cluster config
+	return getClusterConfig(outOfCluster)
+}
+
+// getClusterConfig returns kubernetes client config of cluster
+func getClusterConfig(cluster *config.Cluster) (*rest.Config, error) {
+	if len(cluster.Server) > 0 {
+		return &rest.Config{
+			Host:            cluster.Server,
+			BearerTokenFile: cluster.TokenFile,
+			TLSClientConfig: rest.TLSClientConfig{
+				CAFile: cluster.CAFile,
+			},
+		}, nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if len(cluster.Kubeconfig) > 0 {
+		loadingRules.ExplicitPath = cluster.Kubeconfig
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: cluster.Context},
+	).ClientConfig()
+}
+
+// applyConfig sets rate limits, timeout and content type of kubernetes client
+func applyConfig(clientConfig *rest.Config, kubeConfig *config.Kubernetes) {
+	if kubeConfig.QPS > 0 {
+		clientConfig.QPS = kubeConfig.QPS
+	}
+
+	if kubeConfig.Burst > 0 {
+		clientConfig.Burst = kubeConfig.Burst
+	}
+
+	// client timeout would also cut long running watches, so timeout is set
+	// for each non watch request instead
+	if kubeConfig.Timeout > 0 {
+		timeout := time.Duration(kubeConfig.Timeout) * time.Second
+		clientConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &timeoutRoundTripper{rt: rt, timeout: timeout}
+		})
+	}
+
+	switch strings.ToLower(kubeConfig.ContentType) {
+	case "", "json":
+	case "protobuf":
+		clientConfig.ContentType = runtime.ContentTypeProtobuf
+		clientConfig.AcceptContentTypes =
+			runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+	default:
+		logrus.WithField("contentType", kubeConfig.ContentType).
+			Warn("unknown kubernetes content type, using json")
+	}
+}
+
+// timeoutRoundTripper cancels non watch requests exceeding timeout
+type timeoutRoundTripper struct {
+	rt      http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutRoundTripper) RoundTrip(
+	req *http.Request) (*http.Response, error) {
+	watch := req.URL.Query().Get("watch")
+	if watch == "true" || watch == "1" {
+		return t.rt.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases request context once response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+)
+
+func TestApplyConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	clientConfig := &rest.Config{}
+	applyConfig(clientConfig, &config.Kubernetes{})
+	assert.Equal(float32(0), clientConfig.QPS)
+	assert.Equal(0, clientConfig.Burst)
+	assert.Nil(clientConfig.WrapTransport)
+	assert.Empty(clientConfig.ContentType)
+
+	applyConfig(clientConfig, &config.Kubernetes{
+		QPS:         50,
+		Burst:       100,
+		Timeout:     30,
+		ContentType: "protobuf",
+	})
+	assert.Equal(float32(50), clientConfig.QPS)
+	assert.Equal(100, clientConfig.Burst)
+	assert.NotNil(clientConfig.WrapTransport)
+	assert.Equal(runtime.ContentTypeProtobuf, clientConfig.ContentType)
+	assert.Equal(
+		"application/vnd.kubernetes.protobuf,application/json",
+		clientConfig.AcceptContentTypes)
+}
+
+func TestTimeoutRoundTripper(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &timeoutRoundTripper{
+			rt:      http.DefaultTransport,
+			timeout: 10 * time.Millisecond,
+		},
+	}
+
+	_, err := client.Get(server.URL + "/api/v1/pods")
+	assert.Error(err)
+
+	// watches are not cut by timeout
+	resp, err := client.Get(server.URL + "/api/v1/pods?watch=true")
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestGetClusterConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	clientConfig, err := getClusterConfig(&config.Cluster{
+		Name:      "prod",
+		Server:    "https://prod.example.com",
+		TokenFile: "/var/run/secrets/prod/token",
+		CAFile:    "/var/run/secrets/prod/ca.crt",
+	})
+	assert.NoError(err)
+	assert.Equal("https://prod.example.com", clientConfig.Host)
+	assert.Equal("/var/run/secrets/prod/token", clientConfig.BearerTokenFile)
+	assert.Equal("/var/run/secrets/prod/ca.crt", clientConfig.CAFile)
+
+	_, err = getClusterConfig(&config.Cluster{
+		Name:       "staging",
+		Kubeconfig: "/not/found/kubeconfig",
+	})
+	assert.Error(err)
+}
+
+func TestGetConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	os.WriteFile(kubeconfigPath, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev
+- name: prod
+  context:
+    cluster: prod
+`), 0600)
+
+	clientConfig, err := getConfig(&config.Kubernetes{
+		Kubeconfig: kubeconfigPath,
+	})
+	assert.NoError(err)
+	assert.Equal("https://dev.example.com", clientConfig.Host)
+
+	clientConfig, err = getConfig(&config.Kubernetes{
+		Kubeconfig: kubeconfigPath,
+		Context:    "prod",
+	})
+	assert.NoError(err)
+	assert.Equal("https://prod.example.com", clientConfig.Host)
+
+	_, err = getConfig(&config.Kubernetes{
+		Kubeconfig: kubeconfigPath,
+		Context:    "missing",
+	})
+	assert.Error(err)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/abahmed/kwatch/doctor"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check kwatch has permissions it needs",
+	Long: "Check kwatch has permissions it needs in watched clusters " +
+		"using SelfSubjectAccessReviews and print a pass/fail report.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CLUSTER\tRESOURCE\tVERB\tNAMESPACE\tRESULT")
+
+		failed := 0
+		for _, c := range newClients(cfg) {
+			for _, result := range doctor.Check(c.client, c.config) {
+				namespace := result.Namespace
+				if len(namespace) == 0 {
+					namespace = "*"
+				}
+
+				status := "pass"
+				if result.Error != nil {
+					status = "error: " + result.Error.Error()
+					failed++
+				} else if !result.Allowed {
+					status = "fail: " + result.Reason
+					failed++
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					c.config.App.ClusterName,
+					result.Resource,
+					result.Verb,
+					namespace,
+					status)
+			}
+		}
+		w.Flush()
+
+		if failed > 0 {
+			return fmt.Errorf("%d permission checks failed", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
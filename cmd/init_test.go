@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, provider := range append(providerNames(), "") {
+		content, err := generateConfig(provider)
+		assert.NoError(err)
+
+		// generated config has known fields and default values
+		cfg := config.DefaultConfig()
+		decoder := yaml.NewDecoder(bytes.NewBufferString(content))
+		decoder.KnownFields(true)
+		assert.NoError(decoder.Decode(cfg), provider)
+
+		defaultCfg := config.DefaultConfig()
+		assert.Equal(defaultCfg.PvcMonitor, cfg.PvcMonitor)
+		assert.Equal(defaultCfg.Dispatch, cfg.Dispatch)
+		assert.Equal(defaultCfg.LeaderElection, cfg.LeaderElection)
+		assert.Equal(defaultCfg.PodState, cfg.PodState)
+
+		if len(provider) > 0 {
+			assert.Contains(cfg.Alert, provider)
+		}
+	}
+
+	_, err := generateConfig("unknown")
+	assert.Error(err)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/export"
+	"github.com/abahmed/kwatch/filter"
+	"github.com/abahmed/kwatch/severity"
+	"github.com/abahmed/kwatch/storage/memory"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// replayLive is set by --live flag to send replayed events to providers
+var replayLive bool
+
+// replayIncludeSilenced is set by --include-silenced flag to replay events
+// that were silenced when recorded
+var replayIncludeSilenced bool
+
+// maxRecordSize is max size of a recorded event line, as it may contain
+// long logs
+const maxRecordSize = 16 * 1024 * 1024
+
+// replayFilters are filters applied to recorded events, they're the ones
+// that only depend on config as recorded events have no live pod
+var replayFilters = []filter.Filter{
+	filter.NamespaceFilter{},
+	filter.PodSelectorFilter{},
+	filter.PodNameFilter{},
+	filter.ContainerNameFilter{},
+	filter.ContainerReasonsFilter{},
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay FILE...",
+	Short: "Replay recorded events through filters and providers",
+	Long: "Read events recorded by export sink in jsonl format and push " +
+		"them through configured filters, message sections and " +
+		"providers, so template and routing changes can be tested " +
+		"against real failures.\n" +
+		"Messages are logged instead of sent, unless --live is given.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		if !replayLive {
+			cfg.App.DryRun = true
+		}
+
+		records := make([]*export.Record, 0)
+		for _, path := range args {
+			fileRecords, err := readRecords(path)
+			if err != nil {
+				return err
+			}
+			records = append(records, fileRecords...)
+		}
+
+		alertManager := alertmanager.AlertManager{}
+		alertManager.Init(cfg.Alert, &cfg.App)
+		alertManager.SetSeverityMapper(severity.NewMapper(&cfg.Severity))
+		if len(alertManager.ProviderStatuses()) == 0 {
+			return errors.New("no providers configured")
+		}
+
+		replayed := replayRecords(cfg, &alertManager, records)
+		fmt.Fprintf(
+			cmd.OutOrStdout(),
+			"replayed %d of %d events\n",
+			replayed,
+			len(records))
+		return nil
+	},
+}
+
+func init() {
+	replayCmd.Flags().BoolVar(
+		&replayLive,
+		"live",
+		false,
+		"send replayed events to providers instead of logging them")
+	replayCmd.Flags().BoolVar(
+		&replayIncludeSilenced,
+		"include-silenced",
+		false,
+		"replay events that were silenced when recorded")
+	rootCmd.AddCommand(replayCmd)
+}
+
+// readRecords reads records from jsonl export file
+func readRecords(path string) ([]*export.Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	defer f.Close()
+
+	records := make([]*export.Record, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		record := &export.Record{}
+		if err := json.Unmarshal(data, record); err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse line %d of %s: %w",
+				line,
+				path,
+				err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events file: %w", err)
+	}
+	return records, nil
+}
+
+// replayRecords sends records that pass filters to providers and returns
+// number of replayed ones
+func replayRecords(
+	cfg *config.Config,
+	alertManager *alertmanager.AlertManager,
+	records []*export.Record) int {
+	// a fresh pod state, so recorded events aren't deduplicated
+	podState := memory.NewMemory(&config.PodState{})
+
+	replayed := 0
+	for _, record := range records {
+		if record.Silenced && !replayIncludeSilenced {
+			continue
+		}
+
+		ctx := newReplayContext(cfg, record)
+		ctx.Memory = podState
+		if isFilteredRecord(ctx) {
+			continue
+		}
+
+		alertManager.NotifyEvent(*record.Event())
+		replayed++
+	}
+	return replayed
+}
+
+// newReplayContext returns filter context of a pod and container built from
+// recorded event
+func newReplayContext(
+	cfg *config.Config,
+	record *export.Record) *filter.Context {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      record.Pod,
+			Namespace: record.Namespace,
+			Labels:    record.Labels,
+		},
+	}
+
+	return &filter.Context{
+		Config: cfg,
+		Pod:    pod,
+		EvType: "ADDED",
+		Container: &filter.ContainerContext{
+			Container: &corev1.ContainerStatus{
+				Name: record.Container,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Reason: record.Reason,
+					},
+				},
+			},
+		},
+	}
+}
+
+// isFilteredRecord returns true if one of replay filters drops the record
+func isFilteredRecord(ctx *filter.Context) bool {
+	for _, f := range replayFilters {
+		if f.Execute(ctx) {
+			return true
+		}
+	}
+	return false
+}
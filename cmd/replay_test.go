@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/export"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRecords(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "export.jsonl")
+	data := `{"namespace":"default","pod":"api-0","reason":"OOMKilled"}
+
+{"namespace":"kube-system","pod":"dns-0","reason":"Error"}
+`
+	assert.Nil(os.WriteFile(path, []byte(data), 0644))
+
+	records, err := readRecords(path)
+	assert.Nil(err)
+	assert.Len(records, 2)
+	assert.Equal("api-0", records[0].Pod)
+	assert.Equal("Error", records[1].Reason)
+
+	assert.Nil(os.WriteFile(path, []byte("{}\nnot json\n"), 0644))
+	_, err = readRecords(path)
+	assert.ErrorContains(err, "line 2")
+
+	_, err = readRecords(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.NotNil(err)
+}
+
+func TestReplayRecords(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.DefaultConfig()
+	cfg.ForbiddenNamespaces = []string{"kube-system"}
+	cfg.ForbiddenReasons = []string{"Completed"}
+
+	reporter := &scanReporter{}
+	alertManager := alertmanager.AlertManager{}
+	alertManager.Init(nil, &cfg.App)
+	alertManager.AddProvider(reporter)
+
+	records := []*export.Record{
+		{Namespace: "default", Pod: "api-0", Container: "api",
+			Reason: "OOMKilled"},
+		{Namespace: "default", Pod: "api-0", Container: "api",
+			Reason: "OOMKilled"},
+		{Namespace: "kube-system", Pod: "dns-0", Reason: "Error"},
+		{Namespace: "default", Pod: "job-0", Reason: "Completed"},
+		{Namespace: "default", Pod: "web-0", Reason: "Error",
+			Silenced: true},
+	}
+
+	assert.Equal(2, replayRecords(cfg, &alertManager, records))
+	assert.Equal(2, reporter.count)
+
+	replayIncludeSilenced = true
+	defer func() { replayIncludeSilenced = false }()
+	assert.Equal(3, replayRecords(cfg, &alertManager, records))
+}
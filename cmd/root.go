@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/logging"
+	"github.com/abahmed/kwatch/offline"
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "kwatch",
+	Short: "kwatch monitors kubernetes clusters and alerts on crashes",
+	Long: "kwatch monitors pods of kubernetes clusters, detects crashes " +
+		"in real time and sends alerts to configured providers.\n" +
+		"Running kwatch without a command is the same as kwatch run.",
+	Args:          cobra.NoArgs,
+	RunE:          runKwatch,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+// configPath is path of config file given by --config flag
+var configPath string
+
+// kubeconfig and kubeContext are set by --kubeconfig and --context flags to
+// run out of cluster
+var kubeconfig, kubeContext string
+
+// dryRun is set by --dry-run flag to log messages instead of sending them
+var dryRun bool
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(
+		&configPath,
+		"config",
+		"",
+		"path of config file (default: CONFIG_FILE env variable, "+
+			"$XDG_CONFIG_HOME/kwatch/config.yaml or /etc/kwatch/config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"log messages instead of sending them to providers")
+	rootCmd.PersistentFlags().StringVar(
+		&kubeconfig,
+		"kubeconfig",
+		"",
+		"path of kubeconfig file to run out of cluster")
+	rootCmd.PersistentFlags().StringVar(
+		&kubeContext,
+		"context",
+		"",
+		"kubeconfig context to use when running out of cluster")
+}
+
+// exitError is returned by commands exiting with a specific status code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Execute runs command given in command line arguments
+func Execute() {
+	err := rootCmd.Execute()
+	if err == nil {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "Error:", err)
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
+	}
+	os.Exit(1)
+}
+
+// loadConfig loads config and sets up logging
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	logging.Setup(&cfg.Logging)
+	offline.Enable(&cfg.App)
+
+	if dryRun {
+		cfg.App.DryRun = true
+	}
+
+	if len(kubeconfig) > 0 {
+		cfg.Kubernetes.Kubeconfig = kubeconfig
+	}
+	if len(kubeContext) > 0 {
+		cfg.Kubernetes.Context = kubeContext
+	}
+
+	return cfg, nil
+}
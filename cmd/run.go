@@ -0,0 +1,476 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/archive"
+	"github.com/abahmed/kwatch/audit"
+	"github.com/abahmed/kwatch/client"
+	"github.com/abahmed/kwatch/conditionmonitor"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/abahmed/kwatch/dashboard"
+	"github.com/abahmed/kwatch/digest"
+	"github.com/abahmed/kwatch/escalation"
+	"github.com/abahmed/kwatch/export"
+	"github.com/abahmed/kwatch/handler"
+	"github.com/abahmed/kwatch/heartbeat"
+	"github.com/abahmed/kwatch/history"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/abahmed/kwatch/leader"
+	"github.com/abahmed/kwatch/metrics"
+	"github.com/abahmed/kwatch/namespacemonitor"
+	"github.com/abahmed/kwatch/nodemonitor"
+	"github.com/abahmed/kwatch/policy"
+	"github.com/abahmed/kwatch/policyviolation"
+	"github.com/abahmed/kwatch/pvcmonitor"
+	"github.com/abahmed/kwatch/receiver"
+	"github.com/abahmed/kwatch/reminder"
+	"github.com/abahmed/kwatch/rule"
+	"github.com/abahmed/kwatch/secretmonitor"
+	"github.com/abahmed/kwatch/security"
+	"github.com/abahmed/kwatch/server"
+	"github.com/abahmed/kwatch/severity"
+	"github.com/abahmed/kwatch/silence"
+	"github.com/abahmed/kwatch/skew"
+	"github.com/abahmed/kwatch/storage/memory"
+	"github.com/abahmed/kwatch/stream"
+	"github.com/abahmed/kwatch/upgrader"
+	"github.com/abahmed/kwatch/version"
+	"github.com/abahmed/kwatch/watcher"
+	"github.com/abahmed/kwatch/workloadmonitor"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Watch clusters and send alerts of failing pods",
+	Args:  cobra.NoArgs,
+	RunE:  runKwatch,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+}
+
+// runKwatch watches configured clusters until kwatch is asked to stop
+func runKwatch(cmd *cobra.Command, args []string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	welcomeMsg := fmt.Sprintf(
+		constant.WelcomeMsg,
+		version.Short(),
+		version.Commit(),
+		config.Hash)
+	logrus.Info(welcomeMsg)
+
+	metrics.BuildInfo.WithLabelValues(
+		version.Short(),
+		version.Commit(),
+		version.BuildDate(),
+		config.Hash).Set(1)
+	metrics.ConfigureFailures(&config.Metrics)
+
+	// create kubernetes clients and informers of watched clusters, pod
+	// watchers are created before informers are started
+	clusters := newClusters(config)
+	for _, c := range clusters {
+		c.watcher = watcher.NewWatcher(c.informer)
+	}
+
+	alertManager := alertmanager.AlertManager{}
+	alertManager.Init(config.Alert, &config.App)
+	alertManager.SetAuditor(audit.NewAuditor(&config.Audit))
+	alertManager.SetSeverityMapper(severity.NewMapper(&config.Severity))
+
+	// rendered before live streams are added, so they aren't listed
+	startupMsg := newStartupMessage(config, getProviderNames(&alertManager))
+
+	// live streams receive alerts like other providers
+	streamHub := stream.NewHub()
+	if config.GRPC.Enabled || config.SSE.Enabled {
+		alertManager.AddProvider(streamHub)
+	}
+
+	// only elected leader sends alerts when running multiple replicas
+	elector := leader.NewElector(
+		clusters[0].client,
+		&config.LeaderElection)
+	alertManager.SetLeaderCheck(elector.IsLeader)
+	alertManager.StartWorkers(&config.Dispatch)
+	if len(config.Shutdown.PendingPath) > 0 {
+		alertManager.ResendPending(config.Shutdown.PendingPath)
+	}
+	go elector.Run(context.Background())
+
+	if !config.App.DisableStartupMessage {
+		// send notification to providers
+		alertManager.NotifyKind(alertmanager.MessageStartup, startupMsg)
+	}
+
+	// check and notify if newer versions are available
+	upgrader := upgrader.NewUpgrader(
+		&config.Upgrader,
+		&config.App,
+		&alertManager)
+	upgrader.SetKubeClient(clusters[0].client)
+	go upgrader.CheckUpdates()
+
+	healthDigest := digest.NewDigest(&config.Digest, &alertManager)
+	healthDigest.SetLatestRelease(upgrader.LatestRelease)
+
+	escalator := escalation.NewEscalator(&config.Escalation, &alertManager)
+	ongoingReminder := reminder.NewReminder(&config.Reminder, &alertManager)
+
+	// start monitoring Persistent Volume Claims, version skew, nodes, node
+	// heartbeats, terminating namespaces and expiring secrets of clusters
+	for _, c := range clusters {
+		c.pvcMonitor = pvcmonitor.NewPvcMonitor(
+			c.client,
+			c.informer,
+			&c.config.PvcMonitor,
+			&c.config.Sharding,
+			&alertManager)
+		c.pvcMonitor.SetReminder(&c.config.Reminder)
+		go c.pvcMonitor.Start()
+		healthDigest.AddPvcUsages(c.pvcMonitor.Usages)
+
+		go skew.NewChecker(
+			c.client,
+			&c.config.VersionSkew,
+			c.config.App.ClusterName,
+			&alertManager).Start()
+
+		go nodemonitor.NewNodeMonitor(
+			c.client,
+			&c.config.NodeMonitor,
+			c.config.App.ClusterName,
+			&alertManager).Start()
+
+		go nodemonitor.NewHeartbeatMonitor(
+			c.client,
+			&c.config.NodeHeartbeat,
+			c.config.App.ClusterName,
+			&alertManager).Start()
+
+		go namespacemonitor.NewNamespaceMonitor(
+			c.client,
+			c.config,
+			&alertManager).Start()
+
+		go secretmonitor.NewSecretMonitor(
+			c.client,
+			c.config,
+			&alertManager).Start()
+	}
+
+	// start internal http server
+	srv := server.NewServer(&config.Server)
+	srv.Handle("/metrics", metrics.Handler())
+	srv.HandleFunc("/version", version.Handler(config.Hash))
+	srv.AddReadinessCheck("watch", func() error {
+		if !watchesEstablished(clusters) {
+			return errors.New("pod watch is not established")
+		}
+		return nil
+	})
+	srv.AddReadinessCheck("providers", func() error {
+		if !alertManager.Ready() {
+			return errors.New("no working provider")
+		}
+		return nil
+	})
+
+	silencer := silence.NewSilencer(&config.Silence, config.Server.ExternalURL)
+	if silencer.Enabled() {
+		silencer.RegisterHandlers(srv.HandleFunc)
+	}
+
+	alertHistory, err := history.NewHistory(&config.History)
+	if err != nil {
+		return err
+	}
+	if alertHistory.Enabled() {
+		alertHistory.RegisterHandlers(srv.HandleFunc)
+	}
+
+	exporter := export.NewExporter(&config.Export)
+	archiver := archive.NewArchiver(&config.Archive)
+
+	amReceiver := receiver.NewAlertmanagerReceiver(
+		&config.AlertmanagerReceiver,
+		&alertManager)
+	if amReceiver.Enabled() {
+		amReceiver.RegisterHandlers(srv.HandleFunc)
+	}
+
+	inboundReceiver := receiver.NewInboundReceiver(
+		&config.InboundAlerts,
+		&alertManager)
+	if inboundReceiver.Enabled() {
+		inboundReceiver.RegisterHandlers(srv.HandleFunc)
+	}
+
+	sseHandler := stream.NewSSEHandler(&config.SSE, streamHub)
+	if sseHandler.Enabled() {
+		sseHandler.RegisterHandlers(srv.HandleFunc)
+	}
+
+	// dashboard shows pods and pvcs of first cluster
+	dash := dashboard.NewDashboard(
+		clusters[0].informer,
+		clusters[0].config,
+		&alertManager,
+		alertHistory,
+		silencer,
+		clusters[0].pvcMonitor,
+	)
+	if dash.Enabled() {
+		dash.RegisterHandlers(srv.HandleFunc)
+	}
+
+	go srv.Start()
+
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		syscall.SIGTERM,
+		os.Interrupt)
+	defer stop()
+
+	// routing rules and silences are read from the cluster kwatch runs in
+	if config.Rules.Enabled || config.Silence.Resources {
+		dynamicClient := client.CreateDynamic(&config.App, &config.Kubernetes)
+
+		if config.Rules.Enabled {
+			router := rule.NewRouter()
+			if router.Start(
+				dynamicClient,
+				config.Rules.Namespace,
+				ctx.Done()) {
+				alertManager.SetRouter(router)
+			} else {
+				logrus.Error("failed to sync routing rules")
+			}
+		}
+
+		if config.Silence.Resources {
+			if !silencer.Start(
+				dynamicClient,
+				config.Silence.ResourcesNamespace,
+				ctx.Done()) {
+				logrus.Error("failed to sync silence resources")
+			}
+		}
+	}
+
+	// workload and custom resources are watched in each cluster
+	for _, c := range clusters {
+		if len(c.config.WorkloadResources) == 0 &&
+			len(c.config.CustomResources) == 0 {
+			continue
+		}
+
+		dynamicClient := c.newDynamicClient(config)
+		workloadmonitor.NewWorkloadMonitor(
+			dynamicClient,
+			c.config,
+			&alertManager).Start(ctx.Done())
+		conditionmonitor.NewConditionMonitor(
+			dynamicClient,
+			c.config,
+			&alertManager).Start(ctx.Done())
+	}
+
+	// heartbeats stop once watches aren't healthy, so external monitors page
+	beat := heartbeat.NewHeartbeat(&config.Heartbeat, func() bool {
+		return watchesEstablished(clusters)
+	})
+	go beat.Start(ctx.Done())
+
+	grpcServer := stream.NewGRPCServer(&config.GRPC, streamHub)
+	if grpcServer.Enabled() {
+		go grpcServer.Start(ctx.Done())
+	}
+
+	// archiver uploads its last batch once kwatch is asked to stop
+	archiverDone := make(chan struct{})
+	go func() {
+		defer close(archiverDone)
+		archiver.Start(ctx.Done())
+	}()
+
+	go healthDigest.Start(ctx.Done())
+	for _, c := range clusters {
+		go policy.NewScanner(
+			c.informer,
+			c.config,
+			&alertManager).Start(ctx.Done())
+	}
+	go escalator.Start(ctx.Done())
+	go ongoingReminder.Start(ctx.Done())
+
+	// history prunes alerts older than retention and closes its store once
+	// kwatch is asked to stop
+	historyDone := make(chan struct{})
+	go func() {
+		defer close(historyDone)
+		alertHistory.Start(ctx.Done())
+	}()
+
+	// start watchers, they run until kwatch is asked to stop
+	var wg sync.WaitGroup
+	for _, c := range clusters {
+		podState := memory.NewMemory(&c.config.PodState)
+
+		// handlers of new pods and events are added before informers are
+		// started
+		security.NewMonitor(c.config, &alertManager).Register(c.informer)
+
+		violations := policyviolation.NewForwarder(c.config, &alertManager)
+		violations.Register(c.informer)
+		go violations.Start(ctx.Done())
+
+		// Create handler
+		h := handler.NewHandler(
+			c.client,
+			c.informer,
+			c.config,
+			podState,
+			&alertManager,
+			silencer,
+			alertHistory,
+			exporter,
+			archiver,
+			healthDigest,
+			escalator,
+			ongoingReminder,
+		)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.watcher.Start(h.ProcessPod, ctx.Done())
+
+			if err := podState.Save(); err != nil {
+				logrus.WithError(err).Error("failed to save pod state")
+			}
+		}()
+	}
+	wg.Wait()
+	<-archiverDone
+	<-historyDone
+
+	shutdown(&alertManager, &config.Shutdown)
+	return nil
+}
+
+// cluster holds client and informers of a watched cluster
+type cluster struct {
+	config     *config.Config
+	client     kubernetes.Interface
+	informer   *informer.Informer
+	watcher    *watcher.Watcher
+	pvcMonitor *pvcmonitor.PvcMonitor
+
+	// clusterConfig is config of watched cluster, nil if kwatch watches the
+	// cluster it runs in
+	clusterConfig *config.Cluster
+}
+
+// newDynamicClient returns dynamic client of cluster
+func (c *cluster) newDynamicClient(cfg *config.Config) dynamic.Interface {
+	if c.clusterConfig == nil {
+		return client.CreateDynamic(&cfg.App, &cfg.Kubernetes)
+	}
+	return client.CreateDynamicForCluster(
+		c.clusterConfig,
+		&cfg.App,
+		&cfg.Kubernetes)
+}
+
+// newClusters creates clients and informers of configured clusters, if no
+// clusters are configured, it watches the cluster kwatch runs in
+func newClusters(cfg *config.Config) []*cluster {
+	clusters := newClients(cfg)
+	for _, c := range clusters {
+		c.informer = informer.NewInformer(c.client, c.config)
+	}
+	return clusters
+}
+
+// watchesEstablished returns true if pod watches of all clusters are
+// established
+func watchesEstablished(clusters []*cluster) bool {
+	for _, c := range clusters {
+		if !c.watcher.Established() {
+			return false
+		}
+	}
+	return true
+}
+
+// newClients returns kubernetes clients of watched clusters without starting
+// informers
+func newClients(cfg *config.Config) []*cluster {
+	if len(cfg.Clusters) == 0 {
+		return []*cluster{{
+			config: cfg,
+			client: client.Create(&cfg.App, &cfg.Kubernetes),
+		}}
+	}
+
+	clusters := make([]*cluster, 0, len(cfg.Clusters))
+	for i := range cfg.Clusters {
+		clusters = append(clusters, &cluster{
+			config: cfg.ForCluster(&cfg.Clusters[i]),
+			client: client.CreateForCluster(
+				&cfg.Clusters[i],
+				&cfg.App,
+				&cfg.Kubernetes),
+			clusterConfig: &cfg.Clusters[i],
+		})
+	}
+	return clusters
+}
+
+// shutdown sends queued notifications until timeout and saves the ones that
+// weren't sent
+func shutdown(
+	alertManager *alertmanager.AlertManager,
+	cfg *config.Shutdown) {
+	logrus.Info("shutting down")
+
+	if cfg.Notify {
+		alertManager.Notify(fmt.Sprintf(constant.ShutdownMsg, version.Short()))
+	}
+
+	pending := alertManager.Stop(time.Duration(cfg.Timeout) * time.Second)
+	if len(pending) == 0 {
+		return
+	}
+
+	if len(cfg.PendingPath) == 0 {
+		logrus.WithField("pending", len(pending)).
+			Warn("dropping notifications that weren't sent")
+		return
+	}
+
+	err := alertmanager.SavePending(cfg.PendingPath, pending)
+	if err != nil {
+		logrus.WithError(err).Error("failed to save pending notifications")
+	}
+}
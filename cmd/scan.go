@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/handler"
+	"github.com/abahmed/kwatch/history"
+	"github.com/abahmed/kwatch/pvcmonitor"
+	"github.com/abahmed/kwatch/silence"
+	"github.com/abahmed/kwatch/storage/memory"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// scanFoundIssuesExitCode is exit code of scan when failures are found
+const scanFoundIssuesExitCode = 2
+
+// scanStdout is set by --stdout flag to print failures instead of sending
+// them to providers
+var scanStdout bool
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Report currently failing pods and PVCs once and exit",
+	Long: "List watched clusters once, report currently failing pods and " +
+		"PVCs through configured providers, or to stdout, and exit.\n" +
+		"It exits with status 2 if any failure is found, so it can be used " +
+		"as a CI gate or a cron job.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		alertCfg := cfg.Alert
+		if scanStdout {
+			alertCfg = nil
+		}
+
+		reporter := &scanReporter{}
+		if scanStdout {
+			reporter.out = cmd.OutOrStdout()
+		}
+
+		alertManager := alertmanager.AlertManager{}
+		alertManager.Init(alertCfg, &cfg.App)
+		alertManager.AddProvider(reporter)
+
+		for _, c := range newClusters(cfg) {
+			scanCluster(c, &alertManager)
+		}
+
+		if reporter.count > 0 {
+			return &exitError{
+				code: scanFoundIssuesExitCode,
+				err:  fmt.Errorf("found %d failures", reporter.count),
+			}
+		}
+
+		fmt.Fprintln(cmd.ErrOrStderr(), "no failures found")
+		return nil
+	},
+}
+
+func init() {
+	scanCmd.Flags().BoolVar(
+		&scanStdout,
+		"stdout",
+		false,
+		"print failures to stdout instead of sending them to providers")
+	rootCmd.AddCommand(scanCmd)
+}
+
+// scanCluster reports failing pods and pvcs of cluster once
+func scanCluster(c *cluster, alertManager *alertmanager.AlertManager) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	if !c.informer.Start(stopCh) {
+		logrus.WithField("cluster", c.config.App.ClusterName).
+			Error("failed to sync informer caches")
+		return
+	}
+
+	// disabled history is kept in memory, so it can't fail
+	alertHistory, _ := history.NewHistory(&config.History{})
+
+	h := handler.NewHandler(
+		c.client,
+		c.informer,
+		c.config,
+		memory.NewMemory(&config.PodState{}),
+		alertManager,
+		silence.NewSilencer(&config.Silence{}, ""),
+		alertHistory,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	pods, err := c.informer.ListPods("")
+	if err != nil {
+		logrus.WithError(err).Error("failed to list pods")
+	}
+	for _, pod := range pods {
+		h.ProcessPod("ADDED", pod)
+	}
+
+	pvcMonitor := pvcmonitor.NewPvcMonitor(
+		c.client,
+		c.informer,
+		&c.config.PvcMonitor,
+		&c.config.Sharding,
+		alertManager)
+	pvcMonitor.Check()
+}
+
+// scanReporter is a provider counting reported failures, it prints them if
+// output is set
+type scanReporter struct {
+	out   io.Writer
+	mu    sync.Mutex
+	count int
+}
+
+func (r *scanReporter) Name() string {
+	return "Scan"
+}
+
+func (r *scanReporter) SendEvent(ev *event.Event) error {
+	return r.report(ev.FormatText(ev.Cluster, ""))
+}
+
+func (r *scanReporter) SendMessage(msg string) error {
+	return r.report(msg)
+}
+
+func (r *scanReporter) report(msg string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count++
+	if r.out != nil {
+		fmt.Fprintf(r.out, "%s\n\n", msg)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScanCluster(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:         "api",
+						RestartCount: 3,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{
+								Reason:  "CrashLoopBackOff",
+								Message: "back-off restarting failed container",
+							},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:  "web",
+						Ready: true,
+						State: corev1.ContainerState{
+							Running: &corev1.ContainerStateRunning{},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	cfg := config.DefaultConfig()
+	cfg.PvcMonitor.Enabled = false
+
+	out := new(bytes.Buffer)
+	reporter := &scanReporter{out: out}
+
+	alertManager := alertmanager.AlertManager{}
+	alertManager.Init(nil, &cfg.App)
+	alertManager.AddProvider(reporter)
+
+	scanCluster(&cluster{
+		config:   cfg,
+		client:   cli,
+		informer: informer.NewInformer(cli, cfg),
+	}, &alertManager)
+
+	assert.Equal(1, reporter.count)
+	assert.Contains(out.String(), "api")
+	assert.Contains(out.String(), "CrashLoopBackOff")
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/abahmed/kwatch/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print JSON Schema of config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(schema.Generate())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaCmd(t *testing.T) {
+	assert := assert.New(t)
+
+	out := new(bytes.Buffer)
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs([]string{"schema"})
+	defer rootCmd.SetArgs(nil)
+
+	assert.NoError(rootCmd.Execute())
+
+	var s map[string]interface{}
+	assert.Nil(json.Unmarshal(out.Bytes(), &s))
+	assert.Equal("object", s["type"])
+	assert.Contains(s["properties"], "alert")
+}
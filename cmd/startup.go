@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/abahmed/kwatch/version"
+	"github.com/sirupsen/logrus"
+)
+
+// startupData holds variables available in startup message template
+type startupData struct {
+	Version    string
+	Commit     string
+	ConfigHash string
+	Cluster    string
+
+	// Clusters are names of watched clusters if multiple ones are watched
+	Clusters []string
+
+	// Namespaces are watched namespaces, empty if all are watched
+	Namespaces []string
+
+	// ForbiddenNamespaces are namespaces which aren't watched
+	ForbiddenNamespaces []string
+
+	// Providers are names of enabled providers
+	Providers []string
+
+	// Summary is a short summary of config scope of this instance
+	Summary string
+}
+
+// newStartupMessage returns message sent to providers when kwatch starts
+// rendered from configured template, or default message if it's not set
+func newStartupMessage(cfg *config.Config, providers []string) string {
+	defaultMsg := fmt.Sprintf(
+		constant.WelcomeMsg,
+		version.Short(),
+		version.Commit(),
+		cfg.Hash)
+	if cfg.App.StartupMessageTemplate == nil {
+		return defaultMsg
+	}
+
+	clusters := make([]string, 0, len(cfg.Clusters))
+	for _, cluster := range cfg.Clusters {
+		clusters = append(clusters, cluster.Name)
+	}
+
+	data := startupData{
+		Version:             version.Short(),
+		Commit:              version.Commit(),
+		ConfigHash:          cfg.Hash,
+		Cluster:             cfg.App.ClusterName,
+		Clusters:            clusters,
+		Namespaces:          cfg.AllowedNamespaces,
+		ForbiddenNamespaces: cfg.ForbiddenNamespaces,
+		Providers:           providers,
+		Summary:             getConfigSummary(cfg, clusters, providers),
+	}
+
+	var msg strings.Builder
+	if err := cfg.App.StartupMessageTemplate.Execute(&msg, data); err != nil {
+		logrus.WithError(err).Error("failed to render startup message")
+		return defaultMsg
+	}
+	return msg.String()
+}
+
+// getConfigSummary returns scope of this instance e.g. watched namespaces
+// and reasons, one item per line
+func getConfigSummary(
+	cfg *config.Config,
+	clusters []string,
+	providers []string) string {
+	lines := make([]string, 0)
+	if len(clusters) > 0 {
+		lines = append(lines, "clusters: "+strings.Join(clusters, ", "))
+	} else if len(cfg.App.ClusterName) > 0 {
+		lines = append(lines, "cluster: "+cfg.App.ClusterName)
+	}
+
+	lines = append(lines,
+		"namespaces: "+describeScope(
+			cfg.AllowedNamespaces,
+			cfg.ForbiddenNamespaces),
+		"reasons: "+describeScope(cfg.AllowedReasons, cfg.ForbiddenReasons),
+		"providers: "+describeList(providers))
+
+	if len(cfg.PodSelector) > 0 {
+		lines = append(lines, "pod selector: "+cfg.PodSelector)
+	}
+	if cfg.PvcMonitor.Enabled {
+		lines = append(lines, fmt.Sprintf(
+			"pvc monitor: above %.0f%%",
+			cfg.PvcMonitor.Threshold))
+	}
+	if cfg.NodeMonitor.Enabled {
+		lines = append(lines, "node monitor: enabled")
+	}
+	if cfg.NodeHeartbeat.Enabled {
+		lines = append(lines, fmt.Sprintf(
+			"node heartbeat: stale after %ds",
+			cfg.NodeHeartbeat.Threshold))
+	}
+	if cfg.TerminatingNamespaces.Enabled {
+		lines = append(lines, fmt.Sprintf(
+			"terminating namespaces: stuck after %dm",
+			cfg.TerminatingNamespaces.Threshold))
+	}
+	if cfg.SecretExpiry.Enabled {
+		lines = append(lines, fmt.Sprintf(
+			"secret expiry: %dh before expiry",
+			cfg.SecretExpiry.LeadTime))
+	}
+	if len(cfg.WorkloadResources) > 0 {
+		kinds := make([]string, 0, len(cfg.WorkloadResources))
+		for _, resource := range cfg.WorkloadResources {
+			kinds = append(kinds, resource.Kind)
+		}
+		lines = append(lines, "workload resources: "+strings.Join(kinds, ", "))
+	}
+	if len(cfg.CustomResources) > 0 {
+		kinds := make([]string, 0, len(cfg.CustomResources))
+		for _, resource := range cfg.CustomResources {
+			kinds = append(kinds, resource.Kind)
+		}
+		lines = append(lines, "custom resources: "+strings.Join(kinds, ", "))
+	}
+	if cfg.Policy.Enabled {
+		policies := make([]string, 0, 2)
+		if len(cfg.Policy.RequiredLimits) > 0 {
+			policies = append(policies,
+				strings.Join(cfg.Policy.RequiredLimits, ", ")+" limits")
+		}
+		if cfg.Policy.ImageTags {
+			policies = append(policies, "image tags")
+		}
+		lines = append(lines, "policy: "+describeList(policies))
+	}
+	if cfg.Security.Enabled {
+		lines = append(lines, "security: "+describeList(
+			cfg.Security.Disallowed)+" disallowed")
+	}
+	if cfg.PolicyViolations.Enabled {
+		lines = append(lines, "policy violations: enabled")
+	}
+	if cfg.Sharding.Enabled {
+		lines = append(lines, fmt.Sprintf(
+			"shard: %d of %d",
+			cfg.Sharding.Index+1,
+			cfg.Sharding.Shards))
+	}
+	if cfg.LeaderElection.Enabled {
+		lines = append(lines, "leader election: enabled")
+	}
+	if cfg.App.DryRun {
+		lines = append(lines, "dry run: enabled")
+	}
+	if cfg.App.Offline {
+		lines = append(lines, "offline: enabled")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// describeScope returns allowed items, or all items except forbidden ones
+func describeScope(allowed []string, forbidden []string) string {
+	if len(allowed) > 0 {
+		return strings.Join(allowed, ", ")
+	}
+	if len(forbidden) > 0 {
+		return "all except " + strings.Join(forbidden, ", ")
+	}
+	return "all"
+}
+
+// describeList returns comma separated items, or none if it's empty
+func describeList(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// getProviderNames returns sorted names of providers of alert manager
+func getProviderNames(alertManager *alertmanager.AlertManager) []string {
+	names := make([]string, 0)
+	for _, status := range alertManager.ProviderStatuses() {
+		names = append(names, status.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStartupMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Hash = "abc123"
+	assert.Contains(
+		newStartupMessage(cfg, nil),
+		"kwatch@"+version.Short())
+	assert.Contains(newStartupMessage(cfg, nil), "config: abc123")
+
+	cfg.App.ClusterName = "prod"
+	cfg.AllowedNamespaces = []string{"api", "web"}
+	cfg.App.StartupMessageTemplate = template.Must(
+		template.New("startup").
+			Funcs(template.FuncMap{"join": strings.Join}).
+			Parse("{{.Cluster}}: {{join .Providers \"+\"}}\n{{.Summary}}"))
+
+	msg := newStartupMessage(cfg, []string{"pagerduty", "slack"})
+	assert.Contains(msg, "prod: pagerduty+slack\n")
+	assert.Contains(msg, "cluster: prod")
+	assert.Contains(msg, "namespaces: api, web")
+	assert.Contains(msg, "reasons: all")
+	assert.Contains(msg, "providers: pagerduty, slack")
+	assert.Contains(msg, "pvc monitor: above 80%")
+
+	// default message is sent if template fails
+	cfg.App.StartupMessageTemplate = template.Must(
+		template.New("startup").Parse("{{.Unknown}}"))
+	assert.Contains(newStartupMessage(cfg, nil), "config: abc123")
+}
+
+func TestDescribeScope(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("all", describeScope(nil, nil))
+	assert.Equal("a, b", describeScope([]string{"a", "b"}, nil))
+	assert.Equal("all except c", describeScope(nil, []string{"c"}))
+}
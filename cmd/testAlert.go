@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testAlertEventFile is path of JSON file of event sent by test-alert
+var testAlertEventFile string
+
+var testAlertCmd = &cobra.Command{
+	Use:   "test-alert",
+	Short: "Send a test alert to configured providers",
+	Long: "Send a realistic fake pod failure alert, or the one read from " +
+		"--event-file, to configured providers, so formatting and " +
+		"credentials can be verified before a real incident.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		ev := newTestEvent()
+		if len(testAlertEventFile) > 0 {
+			ev, err = readTestEvent(testAlertEventFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		alertManager := alertmanager.AlertManager{}
+		alertManager.Init(cfg.Alert, &cfg.App)
+		alertManager.NotifyEvent(*ev)
+
+		statuses := alertManager.ProviderStatuses()
+		failed := 0
+		for _, status := range statuses {
+			result := "ok"
+			if status.Failing {
+				result = "failed"
+				failed++
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", status.Name, result)
+		}
+
+		if len(statuses) == 0 {
+			return errors.New("no providers configured")
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d providers failed", failed, len(statuses))
+		}
+		return nil
+	},
+}
+
+func init() {
+	testAlertCmd.Flags().StringVar(
+		&testAlertEventFile,
+		"event-file",
+		"",
+		"path of JSON file of event to send instead of the fake one")
+	rootCmd.AddCommand(testAlertCmd)
+}
+
+// readTestEvent reads event from JSON file
+func readTestEvent(path string) (*event.Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event file: %w", err)
+	}
+
+	ev := &event.Event{}
+	if err := json.Unmarshal(data, ev); err != nil {
+		return nil, fmt.Errorf("failed to parse event file: %w", err)
+	}
+	return ev, nil
+}
+
+// newTestEvent returns fake event of a crash looping pod
+func newTestEvent() *event.Event {
+	now := time.Now()
+	podEvents := []corev1.Event{
+		{
+			Type:          corev1.EventTypeNormal,
+			Reason:        "Pulled",
+			Message:       "Container image \"example/api:1.4.2\" already present",
+			Count:         5,
+			LastTimestamp: metav1.NewTime(now.Add(-3 * time.Minute)),
+		},
+		{
+			Type:          corev1.EventTypeWarning,
+			Reason:        "BackOff",
+			Message:       "Back-off restarting failed container api",
+			Count:         12,
+			LastTimestamp: metav1.NewTime(now.Add(-30 * time.Second)),
+		},
+	}
+
+	return &event.Event{
+		PodName:       "api-7d9f8b6c5-x2x4z",
+		ContainerName: "api",
+		Namespace:     "default",
+		Reason:        "CrashLoopBackOff",
+		Events:        util.GetRecentPodEventsTable(&podEvents, 0),
+		Logs: "starting api server on :8080\n" +
+			"connecting to database at postgres:5432\n" +
+			"panic: failed to connect to database: connection refused\n\n" +
+			"goroutine 1 [running]:\n" +
+			"main.main()\n" +
+			"\t/app/main.go:42 +0x1d4",
+		Labels: map[string]string{"app": "api"},
+		Summary: "This is a test alert sent by kwatch test-alert, " +
+			"the api container can't connect to its database.",
+		Details: []event.Field{
+			{Name: "Owner", Value: "Deployment/api"},
+			{Name: "Node", Value: "node-1"},
+		},
+	}
+}
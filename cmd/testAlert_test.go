@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTestEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "event.json")
+	os.WriteFile(
+		path,
+		[]byte(`{"PodName": "web", "Namespace": "prod", "Reason": "OOMKilled"}`),
+		0644)
+
+	ev, err := readTestEvent(path)
+	assert.NoError(err)
+	assert.Equal("web", ev.PodName)
+	assert.Equal("prod", ev.Namespace)
+	assert.Equal("OOMKilled", ev.Reason)
+
+	_, err = readTestEvent(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(err)
+}
+
+func TestNewTestEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	ev := newTestEvent()
+	assert.Equal("CrashLoopBackOff", ev.Reason)
+	assert.Contains(ev.Events, "BackOff")
+	assert.NotEmpty(ev.Logs)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/abahmed/kwatch/version"
+	"github.com/spf13/cobra"
+)
+
+var versionShort bool
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version of kwatch",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if versionShort {
+			fmt.Fprintln(cmd.OutOrStdout(), version.Short())
+			return
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), version.Version())
+	},
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(
+		&versionShort,
+		"short",
+		false,
+		"print version number only")
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/abahmed/kwatch/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionCmd(t *testing.T) {
+	assert := assert.New(t)
+
+	out := new(bytes.Buffer)
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs([]string{"version", "--short"})
+	defer rootCmd.SetArgs(nil)
+
+	assert.NoError(rootCmd.Execute())
+	assert.Equal(version.Short()+"\n", out.String())
+}
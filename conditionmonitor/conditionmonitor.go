@@ -0,0 +1,236 @@
+package conditionmonitor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// checkInterval is interval between checks of durations of conditions,
+// conditions of objects are also checked on every change of them
+const checkInterval = time.Minute
+
+type ConditionMonitor struct {
+	client       dynamic.Interface
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+
+	mu sync.Mutex
+
+	// objects are last synced objects by index of custom resource and key
+	// of object
+	objects map[int]map[string]*unstructured.Unstructured
+
+	// since is when conditions started to hold by key of resource, object
+	// and condition
+	since map[string]time.Time
+
+	// notified are keys of notified conditions, so a condition is notified
+	// once until it stops holding
+	notified map[string]bool
+}
+
+// NewConditionMonitor returns new instance of custom resource condition
+// monitor of cluster
+func NewConditionMonitor(
+	client dynamic.Interface,
+	config *config.Config,
+	alertManager *alertmanager.AlertManager) *ConditionMonitor {
+	return &ConditionMonitor{
+		client:       client,
+		config:       config,
+		alertManager: alertManager,
+		objects:      make(map[int]map[string]*unstructured.Unstructured),
+		since:        make(map[string]time.Time),
+		notified:     make(map[string]bool),
+	}
+}
+
+// Start watches configured custom resources in background and checks their
+// conditions until stopCh is closed
+func (m *ConditionMonitor) Start(stopCh <-chan struct{}) {
+	if len(m.config.CustomResources) == 0 {
+		return
+	}
+
+	for i := range m.config.CustomResources {
+		resource := &m.config.CustomResources[i]
+		go func() {
+			synced := informer.WatchResourceChanges(
+				m.client,
+				getGroupVersionResource(resource),
+				resource.Namespace,
+				stopCh,
+				func(key string, obj *unstructured.Unstructured) {
+					m.Update(i, key, obj, time.Now())
+				},
+				func(key string) {
+					m.Delete(i, key)
+				})
+			if !synced {
+				logrus.WithField("kind", resource.Kind).
+					Error("failed to sync custom resources")
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				m.Check(now)
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Update stores added or changed object with key of custom resource of
+// index and checks its conditions
+func (m *ConditionMonitor) Update(
+	i int,
+	key string,
+	obj *unstructured.Unstructured,
+	now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.objects[i] == nil {
+		m.objects[i] = make(map[string]*unstructured.Unstructured)
+	}
+	m.objects[i][key] = obj
+	m.checkObject(&m.config.CustomResources[i], key, obj, now)
+}
+
+// Delete forgets deleted object with key of custom resource of index
+func (m *ConditionMonitor) Delete(i int, key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects[i], key)
+	for _, expr := range m.config.CustomResources[i].Expressions {
+		conditionKey := getKey(&m.config.CustomResources[i], key, expr)
+		delete(m.since, conditionKey)
+		delete(m.notified, conditionKey)
+	}
+}
+
+// Check notifies conditions of synced objects holding for their duration
+func (m *ConditionMonitor) Check(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.config.CustomResources {
+		for key, obj := range m.objects[i] {
+			m.checkObject(&m.config.CustomResources[i], key, obj, now)
+		}
+	}
+}
+
+// checkObject notifies conditions of object with key holding for their
+// duration, conditions which stopped holding are forgotten. It should be
+// called while holding lock
+func (m *ConditionMonitor) checkObject(
+	resource *config.CustomResource,
+	key string,
+	obj *unstructured.Unstructured,
+	now time.Time) {
+	watched := m.isWatched(obj.GetNamespace())
+	for _, expr := range resource.Expressions {
+		conditionKey := getKey(resource, key, expr)
+		if !watched || !Evaluate(expr, obj) {
+			delete(m.since, conditionKey)
+			delete(m.notified, conditionKey)
+			continue
+		}
+
+		start, ok := m.since[conditionKey]
+		if !ok {
+			start = now
+			m.since[conditionKey] = start
+		}
+
+		if m.notified[conditionKey] || now.Sub(start) < expr.For {
+			continue
+		}
+		m.notified[conditionKey] = true
+		m.notify(resource, obj, expr)
+	}
+}
+
+// notify notifies condition of object holding
+func (m *ConditionMonitor) notify(
+	resource *config.CustomResource,
+	obj *unstructured.Unstructured,
+	expr *config.ConditionExpression) {
+	logrus.WithFields(logrus.Fields{
+		"kind":      resource.Kind,
+		"namespace": obj.GetNamespace(),
+		"name":      obj.GetName(),
+		"condition": expr.Text,
+	}).Warn("custom resource condition holds")
+
+	m.alertManager.Notify(fmt.Sprintf(
+		constant.CustomResourceConditionMsg,
+		resource.Kind,
+		m.describeObject(obj),
+		expr.Text))
+}
+
+// isWatched returns true if objects in namespace are watched by this
+// instance, cluster scoped objects are always watched
+func (m *ConditionMonitor) isWatched(namespace string) bool {
+	if len(namespace) == 0 {
+		return true
+	}
+
+	return m.config.IsNamespaceWatched(namespace)
+}
+
+// describeObject returns name of object with its namespace and cluster if
+// they're known
+func (m *ConditionMonitor) describeObject(
+	obj *unstructured.Unstructured) string {
+	name := obj.GetName()
+	if len(obj.GetNamespace()) > 0 {
+		name += " in namespace " + obj.GetNamespace()
+	}
+	if len(m.config.App.ClusterName) > 0 {
+		name += " of cluster " + m.config.App.ClusterName
+	}
+	return name
+}
+
+// getKey returns key of condition of object with key
+func getKey(
+	resource *config.CustomResource,
+	key string,
+	expr *config.ConditionExpression) string {
+	return getGroupVersionResource(resource).String() + "/" +
+		key + "/" +
+		expr.Text
+}
+
+// getGroupVersionResource returns group, version and resource of custom
+// resource
+func getGroupVersionResource(
+	resource *config.CustomResource) schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    resource.Group,
+		Version:  resource.Version,
+		Resource: resource.Resource,
+	}
+}
@@ -0,0 +1,135 @@
+package conditionmonitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func newCertificate(namespace string, ready string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "tls",
+			"namespace": namespace,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Issuing", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": ready},
+			},
+			"revision": int64(3),
+		},
+	}}
+}
+
+func mustParse(t *testing.T, text string) *config.ConditionExpression {
+	expr, err := config.ParseConditionExpression(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expr
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := &config.Config{
+		AllowedNamespaces: []string{"default"},
+		CustomResources: []config.CustomResource{{
+			Group:    "cert-manager.io",
+			Version:  "v1",
+			Kind:     "Certificate",
+			Resource: "certificates",
+			Expressions: []*config.ConditionExpression{
+				mustParse(t, "status.conditions[type=Ready].status != True "+
+					"for 10m"),
+			},
+		}},
+	}
+	cfg.App.ClusterName = "prod"
+
+	m := NewConditionMonitor(nil, cfg, alertManager)
+
+	// condition must hold for its duration
+	now := time.Now()
+	m.Update(0, "default/tls", newCertificate("default", "False"), now)
+	m.Update(0, "kube-system/tls", newCertificate("kube-system", "False"), now)
+	m.Check(now.Add(5 * time.Minute))
+	assert.Len(prv.messages, 0)
+
+	m.Check(now.Add(10 * time.Minute))
+	m.Check(now.Add(20 * time.Minute))
+	assert.Len(prv.messages, 1)
+	assert.Contains(prv.messages[0],
+		"Certificate tls in namespace default of cluster prod")
+	assert.Contains(prv.messages[0], "status != True for 10m")
+
+	// condition which stopped holding is notified again
+	m.Update(
+		0,
+		"default/tls",
+		newCertificate("default", "True"),
+		now.Add(21*time.Minute))
+	assert.Empty(m.since)
+	assert.Empty(m.notified)
+
+	m.Update(
+		0,
+		"default/tls",
+		newCertificate("default", "Unknown"),
+		now.Add(22*time.Minute))
+	m.Check(now.Add(32 * time.Minute))
+	assert.Len(prv.messages, 2)
+
+	// deleted objects are forgotten
+	m.Delete(0, "default/tls")
+	assert.Empty(m.since)
+	assert.Empty(m.notified)
+	assert.Len(m.objects[0], 1)
+}
+
+func TestEvaluate(t *testing.T) {
+	assert := assert.New(t)
+
+	obj := newCertificate("default", "True")
+	for text, expected := range map[string]bool{
+		"status.conditions[type=Ready].status == True":   true,
+		"status.conditions[type=Ready].status != True":   false,
+		"status.conditions[1].type == Ready":             true,
+		"status.conditions[5].type == Ready":             false,
+		"status.conditions[type=Missing].status != True": true,
+		"status.missing.field == ''":                     true,
+		"status.revision == 3":                           true,
+		"status.revision > 2":                            true,
+		"status.revision <= 2":                           false,
+		"status.conditions[type=Ready].status > 1":       false,
+		"metadata.name.first == tls":                     false,
+	} {
+		assert.Equal(expected, Evaluate(mustParse(t, text), obj), text)
+	}
+}
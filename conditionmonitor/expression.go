@@ -0,0 +1,98 @@
+package conditionmonitor
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/abahmed/kwatch/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Evaluate returns true if condition expression holds for object, missing
+// fields have empty value e.g. missing Ready condition isn't True
+func Evaluate(
+	expr *config.ConditionExpression,
+	obj *unstructured.Unstructured) bool {
+	value := getValue(obj.Object, expr.Path)
+
+	switch expr.Operator {
+	case "==":
+		return value == expr.Value
+	case "!=":
+		return value != expr.Value
+	}
+
+	// other operators compare numbers
+	left, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	right, err := strconv.ParseFloat(expr.Value, 64)
+	if err != nil {
+		return false
+	}
+
+	switch expr.Operator {
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	}
+	return false
+}
+
+// getValue returns value of field at path as string, or empty string if
+// it's missing
+func getValue(obj map[string]interface{}, path []config.PathSegment) string {
+	var current interface{} = obj
+	for _, segment := range path {
+		fields, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+
+		current, ok = fields[segment.Field]
+		if !ok {
+			return ""
+		}
+
+		if segment.Selected {
+			current = selectItem(current, &segment)
+		}
+	}
+
+	if current == nil {
+		return ""
+	}
+	return fmt.Sprint(current)
+}
+
+// selectItem returns selected item of list, or nil if it's missing
+func selectItem(list interface{}, segment *config.PathSegment) interface{} {
+	items, ok := list.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	if len(segment.Key) == 0 {
+		if segment.Index >= len(items) {
+			return nil
+		}
+		return items[segment.Index]
+	}
+
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if fmt.Sprint(fields[segment.Key]) == segment.Value {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ForCluster returns copy of config used to watch given cluster
+func (c *Config) ForCluster(cluster *Cluster) *Config {
+	clusterConfig := *c
+	clusterConfig.App.ClusterName = cluster.Name
+	if len(c.PodState.Path) > 0 {
+		clusterConfig.PodState.Path = c.PodState.Path + "." + cluster.Name
+	}
+	return &clusterConfig
+}
+
+// validateClusters checks clusters have unique names, as names tell alerts
+// of different clusters apart
+func validateClusters(clusters []Cluster) error {
+	names := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		if len(cluster.Name) == 0 {
+			return errors.New("cluster name is required")
+		}
+
+		if names[cluster.Name] {
+			return fmt.Errorf("duplicate cluster name %s", cluster.Name)
+		}
+		names[cluster.Name] = true
+	}
+	return nil
+}
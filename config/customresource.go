@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConditionOperators are supported operators of condition expressions,
+// longer ones first so they're matched before their prefixes e.g. >= and >
+var ConditionOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// CustomResource confing struct
+type CustomResource struct {
+	// Group of custom resource e.g. cert-manager.io
+	Group string `yaml:"group"`
+
+	// Version of custom resource e.g. v1
+	Version string `yaml:"version"`
+
+	// Kind of custom resource e.g. Certificate
+	Kind string `yaml:"kind"`
+
+	// Resource optional plural name of custom resource used by API server
+	// e.g. certificates, by default it's lowercase kind with s suffix
+	Resource string `yaml:"resource"`
+
+	// Namespace optional namespace resources are watched in, if it's not
+	// provided resources of all namespaces are watched
+	Namespace string `yaml:"namespace"`
+
+	// Conditions are expressions resources are alerted on e.g.
+	// status.conditions[type=Ready].status != True for 10m
+	Conditions []string `yaml:"conditions"`
+
+	// Expressions are parsed conditions
+	Expressions []*ConditionExpression `yaml:"-"`
+}
+
+// ConditionExpression compares value of a field of resources, e.g.
+// status.conditions[type=Ready].status != True for 10m
+type ConditionExpression struct {
+	// Text of expression as configured
+	Text string
+
+	// Path of compared field
+	Path []PathSegment
+
+	// Operator is one of condition operators
+	Operator string
+
+	// Value compared with field, numbers are compared numerically
+	Value string
+
+	// For is how long expression must hold before it's alerted on
+	For time.Duration
+}
+
+// PathSegment is a field of a path, list fields select an item either by
+// index e.g. containers[0], or by value of its field e.g.
+// conditions[type=Ready]
+type PathSegment struct {
+	Field string
+
+	// Selected if set to true, an item of list field is selected
+	Selected bool
+
+	// Index of selected item, if key is empty
+	Index int
+
+	// Key and value of field of selected item
+	Key   string
+	Value string
+}
+
+// ParseConditionExpression returns parsed condition expression in form of
+// <path> <operator> <value> [for <duration>]
+func ParseConditionExpression(text string) (*ConditionExpression, error) {
+	expr := &ConditionExpression{Text: text}
+	rest := strings.TrimSpace(text)
+
+	// duration is optional, values may contain for too e.g. waiting for x
+	if i := strings.LastIndex(rest, " for "); i >= 0 {
+		d, err := time.ParseDuration(strings.TrimSpace(rest[i+5:]))
+		if err == nil {
+			if d < 0 {
+				return nil, fmt.Errorf("negative duration of %s", text)
+			}
+			expr.For = d
+			rest = strings.TrimSpace(rest[:i])
+		}
+	}
+
+	// first operator is used, values may contain operators too
+	opIndex := -1
+	for _, op := range ConditionOperators {
+		i := strings.Index(rest, op)
+		if i >= 0 && (opIndex < 0 || i < opIndex) {
+			opIndex = i
+			expr.Operator = op
+		}
+	}
+	if opIndex < 0 {
+		return nil, fmt.Errorf("missing operator of %s", text)
+	}
+	path := strings.TrimSpace(rest[:opIndex])
+	value := strings.TrimSpace(rest[opIndex+len(expr.Operator):])
+
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	} else if len(value) >= 2 && value[0] == '\'' &&
+		value[len(value)-1] == '\'' {
+		value = value[1 : len(value)-1]
+	}
+	expr.Value = value
+
+	var err error
+	expr.Path, err = parsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path of %s: %w", text, err)
+	}
+
+	return expr, nil
+}
+
+// parsePath returns segments of dot separated path, e.g.
+// status.conditions[type=Ready].status
+func parsePath(path string) ([]PathSegment, error) {
+	if len(path) == 0 {
+		return nil, errors.New("path is empty")
+	}
+
+	segments := make([]PathSegment, 0)
+	for len(path) > 0 {
+		end := strings.IndexAny(path, ".[")
+		if end < 0 {
+			end = len(path)
+		}
+
+		segment := PathSegment{Field: path[:end]}
+		if len(segment.Field) == 0 {
+			return nil, errors.New("field name is empty")
+		}
+		path = path[end:]
+
+		if strings.HasPrefix(path, "[") {
+			closing := strings.Index(path, "]")
+			if closing < 0 {
+				return nil, errors.New("missing ]")
+			}
+
+			selector := path[1:closing]
+			path = path[closing+1:]
+			segment.Selected = true
+			if key, value, ok := strings.Cut(selector, "="); ok {
+				segment.Key = strings.TrimSpace(key)
+				segment.Value = strings.TrimSpace(value)
+			} else {
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid selector %s", selector)
+				}
+				segment.Index = index
+			}
+		}
+
+		segments = append(segments, segment)
+		if len(path) > 0 {
+			if path[0] != '.' {
+				return nil, fmt.Errorf("unexpected %s", path)
+			}
+			path = path[1:]
+		}
+	}
+	return segments, nil
+}
+
+// validateCustomResources checks custom resources can be watched, parses
+// their conditions and sets defaults of optional fields
+func validateCustomResources(resources []CustomResource) error {
+	for i := range resources {
+		resource := &resources[i]
+		if len(resource.Version) == 0 || len(resource.Kind) == 0 {
+			return errors.New("version and kind of custom resource are " +
+				"required")
+		}
+
+		if len(resource.Resource) == 0 {
+			resource.Resource = strings.ToLower(resource.Kind) + "s"
+		}
+
+		if len(resource.Conditions) == 0 {
+			return fmt.Errorf("conditions of %s are required", resource.Kind)
+		}
+
+		resource.Expressions = make(
+			[]*ConditionExpression,
+			0,
+			len(resource.Conditions))
+		for _, condition := range resource.Conditions {
+			expr, err := ParseConditionExpression(condition)
+			if err != nil {
+				return err
+			}
+			resource.Expressions = append(resource.Expressions, expr)
+		}
+	}
+	return nil
+}
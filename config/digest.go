@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// ScheduledWeekday returns weekday weekly digest is sent on
+func (d *Digest) ScheduledWeekday() (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), d.Weekday) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown weekday %s", d.Weekday)
+}
+
+// validateDigest checks schedule of digest is valid
+func validateDigest(digest *Digest) error {
+	if digest.Schedule != DigestDaily && digest.Schedule != DigestWeekly {
+		return fmt.Errorf("unknown digest schedule %s", digest.Schedule)
+	}
+
+	if digest.Hour < 0 || digest.Hour > 23 {
+		return errors.New("digest hour must be between 0 and 23")
+	}
+
+	if digest.Schedule == DigestWeekly {
+		if _, err := digest.ScheduledWeekday(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
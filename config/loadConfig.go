@@ -1,20 +1,30 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-// LoadConfig loads yaml configuration from file if provided, otherwise
-// loads default configuration
-func LoadConfig() (*Config, error) {
+// defaultConfigPath is system wide config file path
+const defaultConfigPath = "/etc/kwatch/config.yaml"
+
+// LoadConfig loads yaml configuration from given config file path, if it's
+// empty config file is looked up by GetConfigPath
+func LoadConfig(configPath string) (*Config, error) {
 	// initialize configuration
-	configFile := os.Getenv("CONFIG_FILE")
+	configFile := GetConfigPath(configPath)
 
 	config := DefaultConfig()
 	yamlFile, err := os.ReadFile(configFile)
@@ -23,15 +33,173 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	config.Hash = getHash(yamlFile)
+
 	err = yaml.Unmarshal(yamlFile, config)
 	if err != nil {
 		logrus.Warnf("unable to parse config file: %s", err.Error())
 		return nil, err
 	}
 
+	if err := validateClusters(config.Clusters); err != nil {
+		logrus.Warnf("invalid clusters config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.Export.Enabled &&
+		config.Export.Format != "jsonl" &&
+		config.Export.Format != "csv" {
+		err := fmt.Errorf("unknown export format %s", config.Export.Format)
+		logrus.Warnf("invalid export config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.History.Enabled &&
+		config.History.Backend != "memory" &&
+		config.History.Backend != "sqlite" &&
+		config.History.Backend != "postgres" {
+		err := fmt.Errorf("unknown history backend %s", config.History.Backend)
+		logrus.Warnf("invalid history config: %s", err.Error())
+		return nil, err
+	}
+
+	if len(config.Upgrader.Channel) > 0 &&
+		config.Upgrader.Channel != "stable" &&
+		config.Upgrader.Channel != "prerelease" {
+		err := fmt.Errorf("unknown release channel %s", config.Upgrader.Channel)
+		logrus.Warnf("invalid upgrader config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.Archive.Enabled {
+		if len(config.Archive.Bucket) == 0 {
+			err := errors.New("archive bucket is required")
+			logrus.Warnf("invalid archive config: %s", err.Error())
+			return nil, err
+		}
+		if config.Archive.Interval <= 0 {
+			err := errors.New("archive interval must be positive")
+			logrus.Warnf("invalid archive config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if config.FlapSuppression.Enabled &&
+		(config.FlapSuppression.Occurrences <= 0 ||
+			config.FlapSuppression.Window <= 0) {
+		err := errors.New("occurrences and window must be positive")
+		logrus.Warnf("invalid flap suppression config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.CrashGrouping.Enabled && config.CrashGrouping.Window <= 0 {
+		err := errors.New("window must be positive")
+		logrus.Warnf("invalid crash grouping config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.ReplicaCorrelation.Enabled {
+		err := validateReplicaCorrelation(&config.ReplicaCorrelation)
+		if err != nil {
+			logrus.Warnf("invalid replica correlation config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if config.LeaderElection.Enabled {
+		err := validateLeaderElection(
+			&config.LeaderElection,
+			&config.Sharding)
+		if err != nil {
+			logrus.Warnf("invalid leader election config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if config.FailureStats.Enabled && config.FailureStats.Retention <= 0 {
+		err := errors.New("retention must be positive")
+		logrus.Warnf("invalid failure stats config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.Digest.Enabled {
+		if err := validateDigest(&config.Digest); err != nil {
+			logrus.Warnf("invalid digest config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	for _, pattern := range config.IgnoreInitContainersNamespaces {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			err := fmt.Errorf("invalid namespace pattern %s", pattern)
+			logrus.Warnf("invalid init containers config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if err := validateIgnoreJobs(&config.IgnoreJobs); err != nil {
+		logrus.Warnf("invalid ignore jobs config: %s", err.Error())
+		return nil, err
+	}
+
+	// an invalid selector fails instead of watching all pods
+	config.PodLabelSelector, err = labels.Parse(config.PodSelector)
+	if err != nil {
+		logrus.Warnf("invalid pod selector: %s", err.Error())
+		return nil, err
+	}
+
+	if config.Escalation.Enabled {
+		if err := validateEscalation(&config.Escalation); err != nil {
+			logrus.Warnf("invalid escalation config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	err = validateWorkloadResources(config.WorkloadResources)
+	if err != nil {
+		logrus.Warnf("invalid workload resources config: %s", err.Error())
+		return nil, err
+	}
+
+	if err := validateCustomResources(config.CustomResources); err != nil {
+		logrus.Warnf("invalid custom resources config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.Security.Enabled {
+		if err := validateSecurity(&config.Security); err != nil {
+			logrus.Warnf("invalid security config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if config.Reminder.Enabled && config.Reminder.Interval <= 0 {
+		err := errors.New("reminder interval must be positive")
+		logrus.Warnf("invalid reminder config: %s", err.Error())
+		return nil, err
+	}
+
+	if err := validateSeverity(&config.Severity); err != nil {
+		logrus.Warnf("invalid severity config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.Heartbeat.Enabled && config.Heartbeat.Interval <= 0 {
+		err := errors.New("heartbeat interval must be positive")
+		logrus.Warnf("invalid heartbeat config: %s", err.Error())
+		return nil, err
+	}
+
+	if config.InboundAlerts.Enabled && len(config.InboundAlerts.Token) == 0 {
+		err := errors.New("inbound alerts token is required")
+		logrus.Warnf("invalid inbound alerts config: %s", err.Error())
+		return nil, err
+	}
+
 	// Parse namespace allow/forbid lists
 	config.AllowedNamespaces, config.ForbiddenNamespaces =
-		getAllowForbidSlices(config.Namespaces)
+		GetAllowForbidSlices(config.Namespaces)
 	if len(config.AllowedNamespaces) > 0 &&
 		len(config.ForbiddenNamespaces) > 0 {
 		logrus.Error(
@@ -41,7 +209,7 @@ func LoadConfig() (*Config, error) {
 
 	// Parse reason allow/forbid lists
 	config.AllowedReasons, config.ForbiddenReasons =
-		getAllowForbidSlices(config.Reasons)
+		GetAllowForbidSlices(config.Reasons)
 	if len(config.AllowedReasons) > 0 &&
 		len(config.ForbiddenReasons) > 0 {
 		logrus.Error("Either allowed or forbidden reasons must be set. " +
@@ -55,6 +223,88 @@ func LoadConfig() (*Config, error) {
 		logrus.Errorf("Failed to compile pod name pattern: %s", err.Error())
 	}
 
+	// Prepare ignored container name patterns, a bad pattern fails loading
+	// instead of alerting on all ignored containers
+	config.IgnoreContainerNamePatterns, err =
+		getCompiledIgnoreContainerNamePatterns(config.IgnoreContainerNames)
+	if err != nil {
+		logrus.Warnf("invalid ignored container names: %s", err.Error())
+		return nil, err
+	}
+
+	// Prepare log filter patterns, a bad pattern fails loading instead of
+	// dropping other patterns of the list
+	config.LogFilters.IncludePatterns, err =
+		getCompiledPatterns(config.LogFilters.Include)
+	if err != nil {
+		logrus.Warnf("invalid log include patterns: %s", err.Error())
+		return nil, err
+	}
+
+	config.LogFilters.ExcludePatterns, err =
+		getCompiledPatterns(config.LogFilters.Exclude)
+	if err != nil {
+		logrus.Warnf("invalid log exclude patterns: %s", err.Error())
+		return nil, err
+	}
+
+	// Prepare redaction patterns
+	if config.Redaction.Enabled {
+		patterns := config.Redaction.Patterns
+		if !config.Redaction.DisableDefaultPatterns {
+			patterns = append(
+				append([]string{}, DefaultRedactionPatterns...),
+				patterns...)
+		}
+
+		// a bad pattern fails loading, so secrets matching other patterns
+		// aren't sent unmasked
+		config.Redaction.CompiledPatterns, err = getCompiledPatterns(patterns)
+		if err != nil {
+			logrus.Warnf("invalid redaction config: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	// Prepare link templates
+	for i := range config.Links {
+		link := &config.Links[i]
+		link.Template, err = template.New(link.Name).Parse(link.URL)
+		if err != nil {
+			logrus.Errorf(
+				"Failed to parse link %s template: %s",
+				link.Name,
+				err.Error())
+		}
+	}
+
+	// Prepare startup message template
+	if len(config.App.StartupMessage) > 0 {
+		config.App.StartupMessageTemplate, err = template.New("startup").
+			Funcs(template.FuncMap{"join": strings.Join}).
+			Parse(config.App.StartupMessage)
+		if err != nil {
+			logrus.Errorf(
+				"Failed to parse startup message template: %s",
+				err.Error())
+		}
+	}
+
+	// Resolve shard index from pod name
+	if config.Sharding.Enabled && config.Sharding.Index < 0 {
+		config.Sharding.Index = getPodOrdinal(os.Getenv("POD_NAME"))
+		if config.Sharding.Index < 0 {
+			logrus.Error("Sharding index is not set and can't be taken " +
+				"from pod name, using 0")
+			config.Sharding.Index = 0
+		}
+	}
+
+	// Fallback to deprecated log formatter
+	if len(config.Logging.Format) == 0 {
+		config.Logging.Format = config.App.LogFormatter
+	}
+
 	// Parse proxy config
 	if len(config.App.ProxyURL) > 0 {
 		os.Setenv("HTTPS_PROXY", config.App.ProxyURL)
@@ -63,8 +313,38 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
-// getAllowForbidSlices split input slice into two slices by items start with !
-func getAllowForbidSlices(items []string) (allow []string, forbid []string) {
+// GetConfigPath returns path of config file, in order of precedence, it's
+// either given path (e.g. --config flag), CONFIG_FILE env variable,
+// $XDG_CONFIG_HOME/kwatch/config.yaml or /etc/kwatch/config.yaml
+func GetConfigPath(configPath string) string {
+	if len(configPath) > 0 {
+		return configPath
+	}
+
+	if envPath := os.Getenv("CONFIG_FILE"); len(envPath) > 0 {
+		return envPath
+	}
+
+	paths := []string{defaultConfigPath}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		paths = []string{
+			filepath.Join(configDir, "kwatch", "config.yaml"),
+			defaultConfigPath,
+		}
+	}
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	// last path is reported as not found
+	return paths[len(paths)-1]
+}
+
+// GetAllowForbidSlices split input slice into two slices by items start with !
+func GetAllowForbidSlices(items []string) (allow []string, forbid []string) {
 	allow = make([]string, 0)
 	forbid = make([]string, 0)
 	for _, item := range items {
@@ -78,6 +358,26 @@ func getAllowForbidSlices(items []string) (allow []string, forbid []string) {
 }
 
 func getCompiledIgnorePodNamePatterns(patterns []string) (compiledPatterns []*regexp.Regexp, err error) {
+	return getCompiledPatterns(patterns)
+}
+
+// getCompiledIgnoreContainerNamePatterns compiles container name patterns,
+// they're anchored so plain names only match containers of the same name
+func getCompiledIgnoreContainerNamePatterns(
+	patterns []string) ([]*regexp.Regexp, error) {
+	compiledPatterns := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiledPattern, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile pattern '%s'", pattern)
+		}
+		compiledPatterns = append(compiledPatterns, compiledPattern)
+	}
+	return compiledPatterns, nil
+}
+
+// getCompiledPatterns compiles list of regexp patterns
+func getCompiledPatterns(patterns []string) (compiledPatterns []*regexp.Regexp, err error) {
 	compiledPatterns = make([]*regexp.Regexp, 0)
 
 	for _, pattern := range patterns {
@@ -92,3 +392,89 @@ func getCompiledIgnorePodNamePatterns(patterns []string) (compiledPatterns []*re
 
 	return compiledPatterns, nil
 }
+
+// validateReplicaCorrelation checks window and namespace patterns of
+// replica correlation
+func validateReplicaCorrelation(correlation *ReplicaCorrelation) error {
+	if correlation.Window <= 0 {
+		return errors.New("window must be positive")
+	}
+
+	for _, pattern := range correlation.Namespaces {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %s", pattern)
+		}
+	}
+	return nil
+}
+
+// validateLeaderElection checks durations of leader election the way
+// leader elector does, so invalid ones fail loading instead of panicking
+// in background, and that it isn't combined with sharding, as non-leader
+// shards would never send alerts of their namespaces
+func validateLeaderElection(
+	leaderElection *LeaderElection,
+	sharding *Sharding) error {
+	if sharding.Enabled {
+		return errors.New("leader election can't be combined with sharding")
+	}
+
+	if leaderElection.LeaseDuration <= 0 ||
+		leaderElection.RenewDeadline <= 0 ||
+		leaderElection.RetryPeriod <= 0 {
+		return errors.New("durations must be positive")
+	}
+
+	if leaderElection.LeaseDuration <= leaderElection.RenewDeadline {
+		return errors.New("leaseDuration must be greater than renewDeadline")
+	}
+
+	// leader elector adds up to 20% jitter to retry period
+	if leaderElection.RenewDeadline*5 <= leaderElection.RetryPeriod*6 {
+		return errors.New(
+			"renewDeadline must be greater than 1.2 times retryPeriod")
+	}
+	return nil
+}
+
+// validateIgnoreJobs checks name patterns of ignored jobs and parses their
+// label selector
+func validateIgnoreJobs(ignoreJobs *IgnoreJobs) error {
+	for _, pattern := range ignoreJobs.Names {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid job name pattern %s", pattern)
+		}
+	}
+
+	if len(ignoreJobs.Selector) == 0 {
+		return nil
+	}
+
+	selector, err := labels.Parse(ignoreJobs.Selector)
+	if err != nil {
+		return err
+	}
+	ignoreJobs.LabelSelector = selector
+	return nil
+}
+
+// getHash returns short sha256 hash of config file content
+func getHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// getPodOrdinal returns ordinal of StatefulSet pod name e.g. 2 for kwatch-2,
+// it returns -1 if name has no ordinal
+func getPodOrdinal(podName string) int {
+	idx := strings.LastIndex(podName, "-")
+	if idx < 0 {
+		return -1
+	}
+
+	ordinal, err := strconv.Atoi(podName[idx+1:])
+	if err != nil || ordinal < 0 {
+		return -1
+	}
+	return ordinal
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	SecurityPrivileged  = "privileged"
+	SecurityRoot        = "root"
+	SecurityHostPath    = "hostPath"
+	SecurityHostNetwork = "hostNetwork"
+)
+
+// SecuritySettings are pod settings security monitor can disallow
+var SecuritySettings = []string{
+	SecurityPrivileged,
+	SecurityRoot,
+	SecurityHostPath,
+	SecurityHostNetwork,
+}
+
+// validateSecurity checks disallowed settings and namespace patterns of
+// security monitor
+func validateSecurity(security *Security) error {
+	for _, setting := range security.Disallowed {
+		if !slices.Contains(SecuritySettings, setting) {
+			return fmt.Errorf("unknown security setting %s", setting)
+		}
+	}
+
+	for _, pattern := range security.Namespaces {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %s", pattern)
+		}
+	}
+	return nil
+}
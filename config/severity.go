@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// SeverityLevels are known severities of alerts from lowest to highest
+var SeverityLevels = []string{"info", "warning", "critical"}
+
+// validateSeverity checks severities and namespace patterns of severity
+// config are valid
+func validateSeverity(severity *Severity) error {
+	if err := validateSeverityLevel(severity.Default); err != nil {
+		return err
+	}
+
+	for _, ns := range severity.Namespaces {
+		if len(ns.Namespace) == 0 {
+			return fmt.Errorf("namespace of severity rule must be set")
+		}
+		if _, err := filepath.Match(ns.Namespace, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %s", ns.Namespace)
+		}
+
+		for _, level := range []string{ns.Severity, ns.Min, ns.Max} {
+			if err := validateSeverityLevel(level); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateEscalation checks escalation has providers, delay and a known
+// severity
+func validateEscalation(escalation *Escalation) error {
+	if escalation.After <= 0 {
+		return fmt.Errorf("escalation delay must be positive")
+	}
+	if len(escalation.Providers) == 0 {
+		return fmt.Errorf("escalation providers are required")
+	}
+	return validateSeverityLevel(escalation.Severity)
+}
+
+// validateSeverityLevel checks severity is empty or a known level
+func validateSeverityLevel(level string) error {
+	if len(level) == 0 {
+		return nil
+	}
+
+	for _, known := range SeverityLevels {
+		if strings.EqualFold(level, known) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %s", level)
+}
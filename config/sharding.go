@@ -0,0 +1,43 @@
+package config
+
+import (
+	"hash/fnv"
+	"slices"
+)
+
+// IsNamespaceWatched returns true if namespace is allowed, isn't forbidden
+// and is handled by this replica
+func (c *Config) IsNamespaceWatched(namespace string) bool {
+	if len(c.AllowedNamespaces) > 0 &&
+		!slices.Contains(c.AllowedNamespaces, namespace) {
+		return false
+	}
+
+	if slices.Contains(c.ForbiddenNamespaces, namespace) {
+		return false
+	}
+
+	// namespaces of other shards are handled by other replicas
+	return c.Sharding.Owns(namespace)
+}
+
+// Owns returns true if namespace is handled by this replica, namespaces are
+// either explicitly assigned to a shard or split by hash of their name
+func (s *Sharding) Owns(namespace string) bool {
+	if !s.Enabled || s.Shards <= 1 {
+		return true
+	}
+
+	return s.Of(namespace) == s.Index
+}
+
+// Of returns shard index namespace is assigned to
+func (s *Sharding) Of(namespace string) int {
+	if index, ok := s.Assignments[namespace]; ok {
+		return index
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(s.Shards))
+}
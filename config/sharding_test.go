@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnsDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True((&Sharding{}).Owns("default"))
+	assert.True((&Sharding{Enabled: true, Shards: 1}).Owns("default"))
+}
+
+func TestOwns(t *testing.T) {
+	assert := assert.New(t)
+
+	shards := make([]*Sharding, 3)
+	for i := range shards {
+		shards[i] = &Sharding{
+			Enabled:     true,
+			Shards:      3,
+			Index:       i,
+			Assignments: map[string]int{"payments": 2},
+		}
+	}
+
+	// each namespace is owned by exactly one shard
+	for n := 0; n < 50; n++ {
+		namespace := fmt.Sprintf("namespace-%d", n)
+		owners := 0
+		for _, cfg := range shards {
+			if cfg.Owns(namespace) {
+				owners++
+			}
+		}
+		assert.Equal(1, owners, namespace)
+	}
+
+	assert.False(shards[0].Owns("payments"))
+	assert.True(shards[2].Owns("payments"))
+}
+
+func TestIsNamespaceWatched(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &Config{}
+	assert.True(cfg.IsNamespaceWatched("default"))
+
+	cfg = &Config{
+		AllowedNamespaces:   []string{"default", "payments"},
+		ForbiddenNamespaces: []string{"payments"},
+	}
+	assert.True(cfg.IsNamespaceWatched("default"))
+	assert.False(cfg.IsNamespaceWatched("payments"))
+	assert.False(cfg.IsNamespaceWatched("kube-system"))
+
+	cfg = &Config{
+		Sharding: Sharding{
+			Enabled:     true,
+			Shards:      2,
+			Assignments: map[string]int{"payments": 1},
+		},
+	}
+	assert.False(cfg.IsNamespaceWatched("payments"))
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WorkloadResource confing struct
+type WorkloadResource struct {
+	// Group of custom resource e.g. argoproj.io
+	Group string `yaml:"group"`
+
+	// Version of custom resource e.g. v1alpha1
+	Version string `yaml:"version"`
+
+	// Kind of custom resource e.g. Rollout
+	Kind string `yaml:"kind"`
+
+	// Resource optional plural name of custom resource used by API server
+	// e.g. rollouts, by default it's lowercase kind with s suffix
+	Resource string `yaml:"resource"`
+
+	// Phases are values of status.phase of failing resources e.g. Degraded
+	Phases []string `yaml:"phases"`
+
+	// Conditions are status conditions of failing resources, if neither
+	// phases nor conditions are set, Ready condition with False status is
+	// a failure
+	Conditions []ResourceCondition `yaml:"conditions"`
+}
+
+// ResourceCondition confing struct
+type ResourceCondition struct {
+	// Type of condition e.g. Ready
+	Type string `yaml:"type"`
+
+	// Status of condition of failing resources
+	// By default, this value is False
+	Status string `yaml:"status"`
+
+	// Reasons optional list of condition reasons, any of them must match
+	Reasons []string `yaml:"reasons"`
+}
+
+// validateWorkloadResources checks workload resources can be watched and
+// sets defaults of optional fields
+func validateWorkloadResources(resources []WorkloadResource) error {
+	for i := range resources {
+		resource := &resources[i]
+		if len(resource.Version) == 0 || len(resource.Kind) == 0 {
+			return errors.New("version and kind of workload resource are " +
+				"required")
+		}
+
+		if len(resource.Resource) == 0 {
+			resource.Resource = strings.ToLower(resource.Kind) + "s"
+		}
+
+		if len(resource.Phases) == 0 && len(resource.Conditions) == 0 {
+			resource.Conditions = []ResourceCondition{{Type: "Ready"}}
+		}
+
+		for j := range resource.Conditions {
+			condition := &resource.Conditions[j]
+			if len(condition.Type) == 0 {
+				return fmt.Errorf("condition type of %s is required",
+					resource.Kind)
+			}
+			if len(condition.Status) == 0 {
+				condition.Status = "False"
+			}
+		}
+	}
+	return nil
+}
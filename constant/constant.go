@@ -1,7 +1,7 @@
 package constant
 
 // WelcomeMsg is used to be sent to all providers when kwatch starts
-const WelcomeMsg = ":tada: kwatch@%s just started!"
+const WelcomeMsg = ":tada: kwatch@%s (commit: %s, config: %s) just started!"
 
 // KwatchUpdateMsg is used to notify all registered providers when a newer
 // version is available
@@ -9,6 +9,75 @@ const KwatchUpdateMsg = ":tada: A newer version " +
 	"<https://github.com/abahmed/kwatch/releases/tag/%[1]s|%[1]s> of Kwatch " +
 	"is available! Please update to the latest version."
 
+// KwatchHelmUpdateMsg is appended to update notifications when kwatch was
+// installed with Helm
+const KwatchHelmUpdateMsg = " Helm release %s uses chart %s, upgrade to " +
+	"chart %s with: `%s`"
+
+// ProviderFailureMsg is used to notify healthy providers when a provider
+// fails to send consecutive notifications
+const ProviderFailureMsg = ":warning: kwatch failed to send %d consecutive " +
+	"notifications with %s, last error: %s"
+
+// VersionSkewMsg is used to notify all registered providers when Kubernetes
+// version of a cluster is outside supported range of kwatch client
+const VersionSkewMsg = ":warning: Kubernetes %s is outside " +
+	"supported range of kwatch client (%s ±%d minor versions), watches may " +
+	"silently break. Please update kwatch or the cluster."
+
+// NodeCapacityMsg is used to notify all registered providers when
+// allocatable resource of a node drops
+const NodeCapacityMsg = ":warning: Node %s lost %s capacity, allocatable " +
+	"dropped from %s to %s. Workloads depending on it may not be scheduled."
+
+// NodeUnhealthyDevicesMsg is used to notify all registered providers when
+// device plugin of a node reports unhealthy devices
+const NodeUnhealthyDevicesMsg = ":warning: Node %s has unhealthy %s " +
+	"devices, only %s of %s are allocatable."
+
+// NodeHeartbeatStaleMsg is used to notify all registered providers when
+// kubelet of a node stops sending heartbeats
+const NodeHeartbeatStaleMsg = ":warning: Node %s hasn't sent a heartbeat " +
+	"for %s (last at %s), it may be failing or unreachable."
+
+// NamespaceTerminatingMsg is used to notify all registered providers when
+// a namespace is stuck in Terminating
+const NamespaceTerminatingMsg = ":warning: Namespace %s has been stuck in " +
+	"Terminating for %s."
+
+// SecretExpiringMsg is used to notify all registered providers when a
+// certificate or token of a secret approaches expiry
+const SecretExpiringMsg = ":warning: %s of secret %s in namespace %s " +
+	"expires at %s (in %s)."
+
+// SecretExpiredMsg is used to notify all registered providers when a
+// certificate or token of a secret expired
+const SecretExpiredMsg = ":red_circle: %s of secret %s in namespace %s " +
+	"expired at %s."
+
+// SecurityViolationMsg is used to notify providers when a new pod runs
+// with disallowed security settings
+const SecurityViolationMsg = ":shield: Pod %s in namespace %s runs with " +
+	"disallowed settings: %s"
+
+// PolicyViolationsMsg is used to notify providers of violations of a policy
+// in a namespace
+const PolicyViolationsMsg = ":no_entry: %d violations of policy %s in " +
+	"namespace %s:"
+
+// WorkloadResourceFailureMsg is used to notify all registered providers
+// when a watched workload custom resource fails
+const WorkloadResourceFailureMsg = ":red_circle: %s %s in namespace %s is " +
+	"failing: %s"
+
+// CustomResourceConditionMsg is used to notify all registered providers
+// when a condition expression of a custom resource holds
+const CustomResourceConditionMsg = ":warning: %s %s matches condition %s"
+
+// ShutdownMsg is used to notify all registered providers when kwatch shuts
+// down
+const ShutdownMsg = ":wave: kwatch@%s is shutting down"
+
 const (
 	Footer        = "<https://github.com/abahmed/kwatch|kwatch>"
 	DefaultTitle  = ":red_circle: kwatch detected a crash in pod"
@@ -0,0 +1,137 @@
+package correlation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+)
+
+// maxListedPods is max number of failed replicas listed by name
+const maxListedPods = 5
+
+// Correlator holds first failure of a workload replica for window, and
+// merges failures of other replicas with the same reason into it
+type Correlator struct {
+	config *config.ReplicaCorrelation
+
+	mu sync.Mutex
+
+	// pending are held failures by workload and reason
+	pending map[string]*pendingFailure
+
+	// afterFunc calls f after d, it's replaced in tests
+	afterFunc func(d time.Duration, f func())
+}
+
+// pendingFailure is first failure of a workload waiting for failures of
+// other replicas
+type pendingFailure struct {
+	event event.Event
+	pods  []string
+	send  func(ev *event.Event)
+}
+
+// NewCorrelator returns new instance of replica failures correlator
+func NewCorrelator(config *config.ReplicaCorrelation) *Correlator {
+	return &Correlator{
+		config:  config,
+		pending: make(map[string]*pendingFailure),
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+// Enabled returns true if replica correlation is enabled
+func (c *Correlator) Enabled() bool {
+	return c != nil && c.config.Enabled
+}
+
+// Add holds failure of workload with key e.g. default/Deployment/api/Error,
+// it's sent by send once window passes with failed replicas. It returns
+// false if failure isn't correlated and should be sent right away
+func (c *Correlator) Add(
+	key string,
+	ev *event.Event,
+	send func(ev *event.Event)) bool {
+	if !c.Enabled() || !c.isCorrelated(ev.Namespace) {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pending, ok := c.pending[key]; ok {
+		for _, pod := range pending.pods {
+			if pod == ev.PodName {
+				return true
+			}
+		}
+		pending.pods = append(pending.pods, ev.PodName)
+		return true
+	}
+
+	c.pending[key] = &pendingFailure{
+		event: *ev,
+		pods:  []string{ev.PodName},
+		send:  send,
+	}
+	c.afterFunc(
+		time.Duration(c.config.Window)*time.Second,
+		func() { c.flush(key) })
+	return true
+}
+
+// flush sends held failure of key with its failed replicas
+func (c *Correlator) flush(key string) {
+	c.mu.Lock()
+	pending, ok := c.pending[key]
+	delete(c.pending, key)
+	c.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	ev := pending.event
+	if len(pending.pods) > 1 {
+		ev.Details = append(ev.Details, event.Field{
+			Name:  "Failed Replicas",
+			Value: describePods(pending.pods),
+		})
+	}
+	pending.send(&ev)
+}
+
+// isCorrelated returns true if failures of namespace are correlated
+func (c *Correlator) isCorrelated(namespace string) bool {
+	if len(c.config.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range c.config.Namespaces {
+		if matched, _ := filepath.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// describePods returns number of failed replicas with their names e.g.
+// 3 (api-1, api-2, api-3)
+func describePods(pods []string) string {
+	names := pods
+	if len(names) > maxListedPods {
+		names = names[:maxListedPods]
+	}
+
+	description := fmt.Sprintf("%d (%s", len(pods), strings.Join(names, ", "))
+	if len(pods) > len(names) {
+		description += fmt.Sprintf(" and %d more", len(pods)-len(names))
+	}
+	return description + ")"
+}
@@ -0,0 +1,86 @@
+package correlation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewCorrelator(&config.ReplicaCorrelation{Enabled: true, Window: 60})
+	flushes := make([]func(), 0)
+	c.afterFunc = func(d time.Duration, f func()) {
+		assert.Equal(time.Minute, d)
+		flushes = append(flushes, f)
+	}
+
+	sent := make([]*event.Event, 0)
+	send := func(ev *event.Event) { sent = append(sent, ev) }
+
+	key := "default/Deployment/api/Error"
+	assert.True(c.Add(key, &event.Event{
+		Namespace: "default",
+		PodName:   "api-1",
+		Reason:    "Error",
+	}, send))
+	for _, pod := range []string{"api-2", "api-2", "api-3"} {
+		ev := &event.Event{Namespace: "default", PodName: pod}
+		assert.True(c.Add(key, ev, send))
+	}
+	assert.True(c.Add(
+		"default/Deployment/db/Error",
+		&event.Event{Namespace: "default", PodName: "db-1"},
+		send))
+	assert.Len(flushes, 2)
+	assert.Empty(sent)
+
+	for _, flush := range flushes {
+		flush()
+	}
+	assert.Len(sent, 2)
+	assert.Empty(c.pending)
+
+	assert.Equal("api-1", sent[0].PodName)
+	assert.Equal([]event.Field{{
+		Name:  "Failed Replicas",
+		Value: "3 (api-1, api-2, api-3)",
+	}}, sent[0].Details)
+	assert.Equal("db-1", sent[1].PodName)
+	assert.Empty(sent[1].Details)
+}
+
+func TestAddNotCorrelated(t *testing.T) {
+	assert := assert.New(t)
+
+	send := func(ev *event.Event) { assert.Fail("unexpected send") }
+	ev := &event.Event{Namespace: "default", PodName: "api-1"}
+
+	c := NewCorrelator(&config.ReplicaCorrelation{Window: 60})
+	assert.False(c.Add("default/Deployment/api/Error", ev, send))
+
+	c = NewCorrelator(&config.ReplicaCorrelation{
+		Enabled:    true,
+		Window:     60,
+		Namespaces: []string{"prod-*"},
+	})
+	assert.False(c.Add("default/Deployment/api/Error", ev, send))
+
+	var nilCorrelator *Correlator
+	assert.False(nilCorrelator.Add("default/Deployment/api/Error", ev, send))
+}
+
+func TestDescribePods(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("2 (api-1, api-2)", describePods([]string{"api-1", "api-2"}))
+	assert.Equal(
+		"7 (api-1, api-2, api-3, api-4, api-5 and 2 more)",
+		describePods([]string{
+			"api-1", "api-2", "api-3", "api-4", "api-5", "api-6", "api-7",
+		}))
+}
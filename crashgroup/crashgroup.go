@@ -0,0 +1,122 @@
+package crashgroup
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// Grouper groups crashes with the same key, e.g. log fingerprint of crashes
+// of a workload, across pods, and suppresses duplicates of reported crash
+// within window
+type Grouper struct {
+	config *config.CrashGrouping
+
+	mu sync.Mutex
+
+	// groups are crashes seen within window by key
+	groups map[string]*group
+
+	// lastPrune is time stale groups were last removed
+	lastPrune time.Time
+
+	// now returns current time, it's replaced in tests
+	now func() time.Time
+}
+
+// group is crashes with the same key
+type group struct {
+	occurrences int
+	pods        map[string]bool
+	lastSeen    time.Time
+	lastSent    time.Time
+}
+
+// Seen is number of crashes with a key, and pods they were seen in
+type Seen struct {
+	Occurrences int
+	Pods        int
+}
+
+// String returns description of seen crashes e.g. seen 5 times across
+// 3 pods
+func (s Seen) String() string {
+	return fmt.Sprintf("seen %d times across %d pods", s.Occurrences, s.Pods)
+}
+
+// NewGrouper returns new instance of crash grouper
+func NewGrouper(config *config.CrashGrouping) *Grouper {
+	return &Grouper{
+		config:    config,
+		groups:    make(map[string]*group),
+		lastPrune: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Enabled returns true if crash grouping is enabled
+func (g *Grouper) Enabled() bool {
+	return g != nil && g.config.Enabled
+}
+
+// Allow records crash with key of pod, and returns true if it should be
+// reported as no crash with the same key was reported within window
+func (g *Grouper) Allow(key string, pod string) bool {
+	if !g.Enabled() || len(key) == 0 {
+		return true
+	}
+
+	now := g.now()
+	window := time.Duration(g.config.Window) * time.Minute
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if now.Sub(g.lastPrune) > window {
+		g.prune(now.Add(-window))
+		g.lastPrune = now
+	}
+
+	grp, ok := g.groups[key]
+	if !ok || now.Sub(grp.lastSeen) > window {
+		grp = &group{pods: make(map[string]bool)}
+		g.groups[key] = grp
+	}
+
+	grp.occurrences++
+	grp.pods[pod] = true
+	grp.lastSeen = now
+
+	if !grp.lastSent.IsZero() && now.Sub(grp.lastSent) < window {
+		return false
+	}
+	grp.lastSent = now
+	return true
+}
+
+// Seen returns crashes seen with key since it started recurring
+func (g *Grouper) Seen(key string) Seen {
+	if !g.Enabled() {
+		return Seen{}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	grp, ok := g.groups[key]
+	if !ok {
+		return Seen{}
+	}
+	return Seen{Occurrences: grp.occurrences, Pods: len(grp.pods)}
+}
+
+// prune removes groups without crashes since given time
+func (g *Grouper) prune(since time.Time) {
+	for key, grp := range g.groups {
+		if grp.lastSeen.Before(since) {
+			delete(g.groups, key)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package crashgroup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllow(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	g := NewGrouper(&config.CrashGrouping{Enabled: true, Window: 30})
+	g.now = func() time.Time { return now }
+
+	assert.True(g.Allow("a1b2c3", "default/api-1"))
+	now = now.Add(5 * time.Minute)
+	assert.False(g.Allow("a1b2c3", "default/api-2"))
+	assert.False(g.Allow("a1b2c3", "default/api-1"))
+	assert.True(g.Allow("d4e5f6", "default/api-1"))
+	assert.Equal(Seen{Occurrences: 3, Pods: 2}, g.Seen("a1b2c3"))
+
+	// crash is reported again with its count once window passes
+	now = now.Add(26 * time.Minute)
+	assert.True(g.Allow("a1b2c3", "default/api-3"))
+	assert.Equal(Seen{Occurrences: 4, Pods: 3}, g.Seen("a1b2c3"))
+	assert.Equal("seen 4 times across 3 pods", g.Seen("a1b2c3").String())
+
+	// counts are reset once crash stops recurring
+	now = now.Add(31 * time.Minute)
+	assert.True(g.Allow("a1b2c3", "default/api-1"))
+	assert.Equal(Seen{Occurrences: 1, Pods: 1}, g.Seen("a1b2c3"))
+	assert.Len(g.groups, 1)
+}
+
+func TestAllowDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewGrouper(&config.CrashGrouping{Window: 30})
+	assert.True(g.Allow("a1b2c3", "default/api-1"))
+	assert.True(g.Allow("a1b2c3", "default/api-1"))
+	assert.Equal(Seen{}, g.Seen("a1b2c3"))
+
+	g = NewGrouper(&config.CrashGrouping{Enabled: true, Window: 30})
+	assert.True(g.Allow("", "default/api-1"))
+	assert.True(g.Allow("", "default/api-1"))
+
+	var nilGrouper *Grouper
+	assert.True(nilGrouper.Allow("a1b2c3", "default/api-1"))
+}
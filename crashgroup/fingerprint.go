@@ -0,0 +1,91 @@
+package crashgroup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxTraceLines is max number of exception and stack trace lines a
+	// fingerprint is computed from
+	maxTraceLines = 30
+
+	// fingerprintLength is number of hex characters of fingerprint
+	fingerprintLength = 12
+)
+
+var (
+	// traceStart matches first line of an exception or stack trace
+	traceStart = regexp.MustCompile(`^(panic: |fatal error: |` +
+		`Traceback \(most recent call last\)|Exception in thread |` +
+		`Uncaught |[\w.$]*(Exception|Error)(: |$))`)
+
+	// traceFrame matches stack frame lines of common runtimes e.g. Go,
+	// Java, Python and Node.js
+	traceFrame = regexp.MustCompile(`^(at |File "|goroutine \d+ |` +
+		`Caused by: |\.\.\. \d+ more|\S+\.go:\d+|[\w./*()]+\(.*\)$)`)
+
+	// numbers match numbers e.g. line numbers and goroutine ids
+	numbers = regexp.MustCompile(`\d+`)
+
+	// variableParts match other parts of trace lines varying between
+	// crashes e.g. addresses, ids and quoted values
+	variableParts = []*regexp.Regexp{
+		regexp.MustCompile(`0x[0-9a-fA-F]+`),
+		regexp.MustCompile(
+			`[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}`),
+		regexp.MustCompile(`"[^"]*"|'[^']*'`),
+	}
+)
+
+// Fingerprint returns fingerprint of first exception or stack trace in logs,
+// or empty string if logs have none
+func Fingerprint(logs string) string {
+	lines := getTraceLines(logs)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
+
+// getTraceLines returns normalized lines of first exception or stack trace
+// in logs, other log lines in between are skipped
+func getTraceLines(logs string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(logs, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if len(lines) == 0 {
+			if !traceStart.MatchString(line) {
+				continue
+			}
+		} else if !traceStart.MatchString(line) &&
+			!traceFrame.MatchString(line) {
+			continue
+		}
+
+		lines = append(lines, normalize(line))
+		if len(lines) == maxTraceLines {
+			break
+		}
+	}
+	return lines
+}
+
+// normalize removes parts of trace line varying between crashes
+func normalize(line string) string {
+	// quoted file names of Python frames are kept
+	if !strings.HasPrefix(line, `File "`) {
+		for _, re := range variableParts {
+			line = re.ReplaceAllString(line, "N")
+		}
+	}
+	return numbers.ReplaceAllString(line, "N")
+}
@@ -0,0 +1,52 @@
+package crashgroup
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	assert := assert.New(t)
+
+	goPanic := `2024/01/02 15:04:05 starting server on :%d
+panic: runtime error: index out of range [%d] with length 3
+
+goroutine %d [running]:
+main.handle(0x%x, {0xc000012345, 0x3})
+	/app/main.go:%d +0x1d
+main.main()
+	/app/main.go:12 +0x25
+exit status 2`
+
+	first := Fingerprint(fmt.Sprintf(goPanic, 8080, 5, 1, 0xc0001, 42))
+	second := Fingerprint(fmt.Sprintf(goPanic, 9090, 7, 18, 0xd00f2, 43))
+	assert.Len(first, fingerprintLength)
+	assert.Equal(first, second)
+
+	java := `12:00:01 INFO starting
+java.lang.NullPointerException: user "bob" not found
+	at com.example.Users.get(Users.java:42)
+	at com.example.Api.handle(Api.java:17)
+	... 12 more`
+	python := `Traceback (most recent call last):
+  File "/app/main.py", line 10, in <module>
+    main()
+  File "/app/main.py", line 6, in main
+    int(value)
+ValueError: invalid literal for int() with base 10: 'abc'`
+
+	assert.NotEqual(first, Fingerprint(java))
+	assert.NotEqual(Fingerprint(java), Fingerprint(python))
+	assert.Equal(
+		Fingerprint(python),
+		Fingerprint(python[:len(python)-5]+"'xyz'"))
+	assert.NotEqual(
+		Fingerprint(python),
+		Fingerprint(strings.ReplaceAll(python, "main.py", "other.py")))
+
+	assert.Empty(Fingerprint("starting server\nlistening on :8080"))
+	assert.Empty(Fingerprint(""))
+}
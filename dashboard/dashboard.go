@@ -0,0 +1,214 @@
+package dashboard
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/history"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/abahmed/kwatch/pvcmonitor"
+	"github.com/abahmed/kwatch/silence"
+	"github.com/abahmed/kwatch/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxAlerts is max number of recent alerts shown
+const maxAlerts = 50
+
+type Dashboard struct {
+	informer     *informer.Informer
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+	history      *history.History
+	silencer     *silence.Silencer
+	pvcMonitor   *pvcmonitor.PvcMonitor
+}
+
+type failingPod struct {
+	Namespace string
+	Name      string
+	Reason    string
+}
+
+type pageData struct {
+	ClusterName    string
+	FailingPods    []failingPod
+	FailingPodsErr string
+	HistoryEnabled bool
+	Alerts         []history.Entry
+	SilenceEnabled bool
+	Silences       []silence.Silence
+	PvcEnabled     bool
+	PvcThreshold   float64
+	Pvcs           []*pvcmonitor.PvcUsage
+	Providers      []alertmanager.ProviderStatus
+}
+
+// NewDashboard returns new instance of read-only web dashboard
+func NewDashboard(
+	inf *informer.Informer,
+	config *config.Config,
+	alertManager *alertmanager.AlertManager,
+	alertHistory *history.History,
+	silencer *silence.Silencer,
+	pvcMonitor *pvcmonitor.PvcMonitor) *Dashboard {
+	return &Dashboard{
+		informer:     inf,
+		config:       config,
+		alertManager: alertManager,
+		history:      alertHistory,
+		silencer:     silencer,
+		pvcMonitor:   pvcMonitor,
+	}
+}
+
+// Enabled returns true if dashboard is enabled
+func (d *Dashboard) Enabled() bool {
+	return d.config.Dashboard.Enabled
+}
+
+// RegisterHandlers registers dashboard handlers
+func (d *Dashboard) RegisterHandlers(
+	handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle("/dashboard", d.handleDashboard)
+}
+
+// handleDashboard renders dashboard page
+func (d *Dashboard) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	data := pageData{
+		ClusterName:    d.config.App.ClusterName,
+		HistoryEnabled: d.history.Enabled(),
+		Alerts:         d.history.List(history.Query{Limit: maxAlerts}),
+		SilenceEnabled: d.silencer.Enabled(),
+		Silences:       d.silencer.List(),
+		PvcEnabled:     d.pvcMonitor.Enabled(),
+		PvcThreshold:   d.pvcMonitor.Threshold(),
+		Pvcs:           d.pvcMonitor.Usages(),
+		Providers:      d.alertManager.ProviderStatuses(),
+	}
+
+	pods, err := d.getFailingPods()
+	if err != nil {
+		logrus.WithError(err).Error("failed to get failing pods")
+		data.FailingPodsErr = err.Error()
+	}
+	data.FailingPods = pods
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		logrus.WithError(err).Error("failed to render dashboard")
+	}
+}
+
+// getFailingPods returns failing pods in watched namespaces sorted by
+// namespace and name
+func (d *Dashboard) getFailingPods() ([]failingPod, error) {
+	namespaces := d.config.AllowedNamespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{corev1.NamespaceAll}
+	}
+
+	result := make([]failingPod, 0)
+	for _, namespace := range namespaces {
+		pods, err := d.informer.ListPods(namespace)
+		if err != nil {
+			return result, err
+		}
+
+		for _, pod := range pods {
+			if slices.Contains(d.config.ForbiddenNamespaces, pod.Namespace) {
+				continue
+			}
+
+			reason := util.GetPodFailureReason(pod)
+			if len(reason) == 0 {
+				continue
+			}
+
+			result = append(result, failingPod{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Reason:    reason,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+var pageTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="30">
+<title>kwatch{{if .ClusterName}} - {{.ClusterName}}{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f3f3f3; }
+.failing { color: #c0392b; }
+.ok { color: #27ae60; }
+</style>
+</head>
+<body>
+<h1>kwatch{{if .ClusterName}} - {{.ClusterName}}{{end}}</h1>
+
+<h2>Failing Pods</h2>
+{{if .FailingPodsErr}}<p class="failing">{{.FailingPodsErr}}</p>{{end}}
+{{if .FailingPods}}
+<table>
+<tr><th>Namespace</th><th>Pod</th><th>Reason</th></tr>
+{{range .FailingPods}}<tr><td>{{.Namespace}}</td><td>{{.Name}}</td><td class="failing">{{.Reason}}</td></tr>
+{{end}}</table>
+{{else}}<p>No failing pods</p>{{end}}
+
+<h2>Recent Alerts</h2>
+{{if not .HistoryEnabled}}<p>Alert history is disabled</p>
+{{else if .Alerts}}
+<table>
+<tr><th>Time</th><th>Namespace</th><th>Pod</th><th>Container</th><th>Reason</th></tr>
+{{range .Alerts}}<tr><td>{{.Time.Format "2006-01-02 15:04:05"}}</td><td>{{.Namespace}}</td><td>{{.Pod}}</td><td>{{.Container}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>
+{{else}}<p>No recent alerts</p>{{end}}
+
+<h2>Silences</h2>
+{{if not .SilenceEnabled}}<p>Silence API is disabled</p>
+{{else if .Silences}}
+<table>
+<tr><th>Namespace</th><th>Workload</th><th>Until</th></tr>
+{{range .Silences}}<tr><td>{{.Namespace}}</td><td>{{.Workload}}</td><td>{{.Until.Format "2006-01-02 15:04:05"}}</td></tr>
+{{end}}</table>
+{{else}}<p>No active silences</p>{{end}}
+
+<h2>Persistent Volume Claims</h2>
+{{if not .PvcEnabled}}<p>PVC monitor is disabled</p>
+{{else if .Pvcs}}
+<table>
+<tr><th>Namespace</th><th>PVC</th><th>PV</th><th>Pod</th><th>Usage</th></tr>
+{{range .Pvcs}}<tr><td>{{.Namespace}}</td><td>{{.Name}}</td><td>{{.PVName}}</td><td>{{.PodName}}</td><td class="{{if ge .UsagePercentage $.PvcThreshold}}failing{{else}}ok{{end}}">{{printf "%.2f%%" .UsagePercentage}}</td></tr>
+{{end}}</table>
+{{else}}<p>No PVC usages reported yet</p>{{end}}
+
+<h2>Providers</h2>
+{{if .Providers}}
+<table>
+<tr><th>Provider</th><th>Status</th></tr>
+{{range .Providers}}<tr><td>{{.Name}}</td>{{if .Failing}}<td class="failing">failing</td>{{else}}<td class="ok">ok</td>{{end}}</tr>
+{{end}}</table>
+{{else}}<p>No providers configured</p>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/history"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/abahmed/kwatch/pvcmonitor"
+	"github.com/abahmed/kwatch/silence"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason: "CrashLoopBackOff",
+						},
+					},
+				}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		},
+	)
+
+	cfg := config.DefaultConfig()
+	cfg.Dashboard.Enabled = true
+	cfg.History.Enabled = true
+
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, &cfg.App)
+
+	alertHistory, err := history.NewHistory(&cfg.History)
+	assert.Nil(err)
+	alertHistory.Add(&event.Event{
+		Namespace: "kube-system",
+		PodName:   "dns",
+		Reason:    "OOMKilled",
+	})
+
+	inf := informer.NewInformer(cli, cfg)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	assert.True(inf.Start(stopCh))
+
+	d := NewDashboard(
+		inf,
+		cfg,
+		alertManager,
+		alertHistory,
+		silence.NewSilencer(&cfg.Silence, ""),
+		pvcmonitor.NewPvcMonitor(
+			cli,
+			inf,
+			&cfg.PvcMonitor,
+			&cfg.Sharding,
+			alertManager))
+	assert.True(d.Enabled())
+
+	rr := httptest.NewRecorder()
+	d.handleDashboard(rr, httptest.NewRequest(
+		http.MethodGet,
+		"/dashboard",
+		nil))
+
+	body := rr.Body.String()
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Contains(body, "CrashLoopBackOff")
+	assert.NotContains(body, "<td>web</td>")
+	assert.Contains(body, "OOMKilled")
+	assert.Contains(body, "Silence API is disabled")
+	assert.Contains(body, "No providers configured")
+}
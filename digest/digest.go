@@ -0,0 +1,275 @@
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/pvcmonitor"
+	"github.com/sirupsen/logrus"
+)
+
+// topReasons is number of most common failure reasons listed
+const topReasons = 3
+
+type Digest struct {
+	config       *config.Digest
+	alertManager *alertmanager.AlertManager
+
+	// pvcUsages return pvc usages of last check of pvc monitors
+	pvcUsages []func() []*pvcmonitor.PvcUsage
+
+	// latestRelease returns tag of newer kwatch release if there's one
+	latestRelease func() string
+
+	mu        sync.Mutex
+	since     time.Time
+	failures  int
+	reasons   map[string]int
+	workloads map[string]int
+
+	// lastUsages are pvc usage percentages of last digest by volume name
+	lastUsages map[string]float64
+}
+
+// count is a number of failures of a reason or workload
+type count struct {
+	name  string
+	count int
+}
+
+// NewDigest returns new instance of digest
+func NewDigest(
+	config *config.Digest,
+	alertManager *alertmanager.AlertManager) *Digest {
+	return &Digest{
+		config:       config,
+		alertManager: alertManager,
+		since:        time.Now(),
+		reasons:      make(map[string]int),
+		workloads:    make(map[string]int),
+		lastUsages:   make(map[string]float64),
+	}
+}
+
+// Enabled returns true if digest is enabled
+func (d *Digest) Enabled() bool {
+	return d != nil && d.config.Enabled
+}
+
+// AddPvcUsages adds function returning pvc usages of last check, volumes
+// trending toward full are listed in digest
+func (d *Digest) AddPvcUsages(usages func() []*pvcmonitor.PvcUsage) {
+	d.pvcUsages = append(d.pvcUsages, usages)
+}
+
+// SetLatestRelease sets function returning tag of newer kwatch release,
+// pending update is listed in digest
+func (d *Digest) SetLatestRelease(latestRelease func() string) {
+	d.latestRelease = latestRelease
+}
+
+// Add counts sent alert in current digest
+func (d *Digest) Add(ev *event.Event) {
+	if !d.Enabled() {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.failures++
+	d.reasons[ev.Reason]++
+	d.workloads[getWorkload(ev)]++
+}
+
+// Start sends digest on configured schedule until stopCh is closed
+func (d *Digest) Start(stopCh <-chan struct{}) {
+	if !d.Enabled() {
+		return
+	}
+
+	for {
+		next := d.nextRun(time.Now())
+		logrus.WithField("at", next).Debug("scheduled next digest")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			d.Send()
+		case <-stopCh:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Send sends digest of failures since last digest to configured providers
+func (d *Digest) Send() {
+	d.alertManager.NotifyProviders(
+		d.config.Providers,
+		d.message(time.Now()))
+}
+
+// nextRun returns time of next digest after now
+func (d *Digest) nextRun(now time.Time) time.Time {
+	next := time.Date(
+		now.Year(),
+		now.Month(),
+		now.Day(),
+		d.config.Hour,
+		0,
+		0,
+		0,
+		now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	if d.config.Schedule != config.DigestWeekly {
+		return next
+	}
+
+	weekday, err := d.config.ScheduledWeekday()
+	if err != nil {
+		return next
+	}
+	days := (int(weekday) - int(next.Weekday()) + 7) % 7
+	return next.AddDate(0, 0, days)
+}
+
+// message returns digest of failures since last digest, counters are reset
+func (d *Digest) message(now time.Time) string {
+	d.mu.Lock()
+	since := d.since
+	failures := d.failures
+	reasons := topCounts(d.reasons, topReasons)
+	workloads := topCounts(d.workloads, d.config.TopWorkloads)
+	d.since = now
+	d.failures = 0
+	d.reasons = make(map[string]int)
+	d.workloads = make(map[string]int)
+	d.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b,
+		":bar_chart: kwatch %s digest since %s\n",
+		d.config.Schedule,
+		since.Format("2006-01-02 15:04"))
+
+	if failures == 0 {
+		b.WriteString("No failures were seen :white_check_mark:\n")
+	} else {
+		fmt.Fprintf(&b, "Failures: %d\n", failures)
+
+		names := make([]string, 0, len(reasons))
+		for _, r := range reasons {
+			names = append(names, fmt.Sprintf("%s (%d)", r.name, r.count))
+		}
+		fmt.Fprintf(&b, "Top reasons: %s\n", strings.Join(names, ", "))
+
+		b.WriteString("Top crashing workloads:\n")
+		for _, w := range workloads {
+			fmt.Fprintf(&b, "• %s: %d\n", w.name, w.count)
+		}
+	}
+
+	if lines := d.pvcLines(); len(lines) > 0 {
+		b.WriteString("Volumes trending toward full:\n")
+		for _, line := range lines {
+			fmt.Fprintf(&b, "• %s\n", line)
+		}
+	}
+
+	if d.latestRelease != nil {
+		if tag := d.latestRelease(); len(tag) > 0 {
+			fmt.Fprintf(&b, "Update available: kwatch %s\n", tag)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// pvcLines returns descriptions of volumes above threshold whose usage
+// grew since last digest, fullest first
+func (d *Digest) pvcLines() []string {
+	usages := make([]*pvcmonitor.PvcUsage, 0)
+	for _, pvcUsages := range d.pvcUsages {
+		usages = append(usages, pvcUsages()...)
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].UsagePercentage > usages[j].UsagePercentage
+	})
+
+	lastUsages := make(map[string]float64, len(usages))
+	lines := make([]string, 0)
+	for _, usage := range usages {
+		lastUsages[usage.PVName] = usage.UsagePercentage
+
+		if usage.UsagePercentage < d.config.PvcThreshold {
+			continue
+		}
+
+		// volumes seen by last digest are listed only if they grew
+		last, ok := d.lastUsages[usage.PVName]
+		if ok && usage.UsagePercentage <= last {
+			continue
+		}
+
+		line := fmt.Sprintf("%s (%s) in namespace %s: %.2f%%",
+			usage.Name,
+			usage.PVName,
+			usage.Namespace,
+			usage.UsagePercentage)
+		if ok {
+			line += fmt.Sprintf(" (+%.2f%%)", usage.UsagePercentage-last)
+		}
+		lines = append(lines, line)
+	}
+	d.lastUsages = lastUsages
+
+	return lines
+}
+
+// getWorkload returns owning workload of event's pod e.g.
+// default/Deployment/api, or the pod itself if it has no owner
+func getWorkload(ev *event.Event) string {
+	workload := "Pod/" + ev.PodName
+	for _, detail := range ev.Details {
+		if detail.Name == "Workload" {
+			// ready replicas are dropped e.g. Deployment/api (2/3 ready)
+			workload = strings.SplitN(detail.Value, " (", 2)[0]
+			break
+		}
+	}
+
+	key := ev.Namespace + "/" + workload
+	if len(ev.Cluster) > 0 {
+		key = ev.Cluster + "/" + key
+	}
+	return key
+}
+
+// topCounts returns up to n largest counts sorted by count and name
+func topCounts(counts map[string]int, n int) []count {
+	sorted := make([]count, 0, len(counts))
+	for name, c := range counts {
+		sorted = append(sorted, count{name: name, count: c})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
@@ -0,0 +1,108 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/pvcmonitor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDigest() *Digest {
+	cfg := config.DefaultConfig().Digest
+	cfg.Enabled = true
+	return NewDigest(&cfg, nil)
+}
+
+func TestNextRun(t *testing.T) {
+	assert := assert.New(t)
+
+	d := newTestDigest()
+
+	// Thursday
+	now := time.Date(2024, 5, 16, 8, 30, 0, 0, time.UTC)
+	assert.Equal(
+		time.Date(2024, 5, 16, 9, 0, 0, 0, time.UTC),
+		d.nextRun(now))
+	assert.Equal(
+		time.Date(2024, 5, 17, 9, 0, 0, 0, time.UTC),
+		d.nextRun(now.Add(time.Hour)))
+
+	d.config.Schedule = config.DigestWeekly
+	assert.Equal(
+		time.Date(2024, 5, 20, 9, 0, 0, 0, time.UTC),
+		d.nextRun(now))
+
+	d.config.Weekday = "thursday"
+	assert.Equal(
+		time.Date(2024, 5, 16, 9, 0, 0, 0, time.UTC),
+		d.nextRun(now))
+	assert.Equal(
+		time.Date(2024, 5, 23, 9, 0, 0, 0, time.UTC),
+		d.nextRun(now.Add(time.Hour)))
+}
+
+func TestMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	d := newTestDigest()
+	d.since = time.Date(2024, 5, 16, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		d.Add(&event.Event{
+			Namespace: "default",
+			PodName:   "api-5d8f7-abcde",
+			Reason:    "CrashLoopBackOff",
+			Details: []event.Field{{
+				Name:  "Workload",
+				Value: "Deployment/api (2/3 ready)",
+			}},
+		})
+	}
+	d.Add(&event.Event{
+		Cluster:   "prod",
+		Namespace: "jobs",
+		PodName:   "backup",
+		Reason:    "OOMKilled",
+	})
+
+	usages := []*pvcmonitor.PvcUsage{
+		{Name: "data", PVName: "pv-1", Namespace: "db", UsagePercentage: 70},
+		{Name: "logs", PVName: "pv-2", Namespace: "db", UsagePercentage: 20},
+	}
+	d.AddPvcUsages(func() []*pvcmonitor.PvcUsage { return usages })
+	d.SetLatestRelease(func() string { return "v0.10.0" })
+
+	msg := d.message(time.Now())
+	assert.Contains(msg, "kwatch daily digest since 2024-05-16 09:00")
+	assert.Contains(msg, "Failures: 4")
+	assert.Contains(msg, "Top reasons: CrashLoopBackOff (3), OOMKilled (1)")
+	assert.Contains(msg, "• default/Deployment/api: 3")
+	assert.Contains(msg, "• prod/jobs/Pod/backup: 1")
+	assert.Contains(msg, "data (pv-1) in namespace db: 70.00%")
+	assert.NotContains(msg, "pv-2")
+	assert.Contains(msg, "Update available: kwatch v0.10.0")
+
+	// counters are reset and volumes are listed only if they grew
+	msg = d.message(time.Now())
+	assert.Contains(msg, "No failures were seen")
+	assert.NotContains(msg, "pv-1")
+
+	usages[0].UsagePercentage = 75
+	msg = d.message(time.Now())
+	assert.Contains(msg, "data (pv-1) in namespace db: 75.00% (+5.00%)")
+}
+
+func TestAddDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	var nilDigest *Digest
+	nilDigest.Add(&event.Event{})
+	assert.False(nilDigest.Enabled())
+
+	d := NewDigest(&config.Digest{}, nil)
+	d.Add(&event.Event{})
+	assert.Equal(0, d.failures)
+}
@@ -0,0 +1,188 @@
+package doctor
+
+import (
+	"context"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/rule"
+	"github.com/abahmed/kwatch/silence"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Permission is an access kwatch needs to kubernetes API
+type Permission struct {
+	Group    string
+	Resource string
+	Verb     string
+
+	// Reason tells what doesn't work without permission
+	Reason string
+}
+
+// Result is result of checking a permission
+type Result struct {
+	Permission
+	Namespace string
+	Allowed   bool
+
+	// Error is set if permission couldn't be checked
+	Error error
+}
+
+// RequiredPermissions returns permissions kwatch needs with given config
+func RequiredPermissions(cfg *config.Config) []Permission {
+	permissions := []Permission{
+		{Resource: "pods", Verb: "list", Reason: "pods can't be watched"},
+		{Resource: "pods", Verb: "watch", Reason: "pods can't be watched"},
+		{Resource: "pods", Verb: "get", Reason: "pods can't be watched"},
+		{
+			Resource: "pods/log",
+			Verb:     "get",
+			Reason:   "alerts have empty logs",
+		},
+		{Resource: "events", Verb: "list", Reason: "alerts have no events"},
+		{Resource: "events", Verb: "watch", Reason: "alerts have no events"},
+		{
+			Resource: "nodes",
+			Verb:     "get",
+			Reason:   "node details and PVC usage are missing",
+		},
+	}
+
+	for _, resource := range []string{"deployments", "replicasets"} {
+		permissions = append(permissions, Permission{
+			Group:    "apps",
+			Resource: resource,
+			Verb:     "get",
+			Reason:   "workload replicas and Helm upgrade commands are missing",
+		})
+	}
+
+	if cfg.PvcMonitor.Enabled {
+		permissions = append(permissions,
+			Permission{
+				Resource: "persistentvolumeclaims",
+				Verb:     "list",
+				Reason:   "PVC usage isn't monitored",
+			},
+			Permission{
+				Resource: "persistentvolumeclaims",
+				Verb:     "watch",
+				Reason:   "PVC usage isn't monitored",
+			})
+	}
+
+	if cfg.IgnoreJobs.Enabled() {
+		permissions = append(permissions, Permission{
+			Group:    "batch",
+			Resource: "jobs",
+			Verb:     "get",
+			Reason:   "CronJobs of ignored jobs aren't matched",
+		})
+	}
+
+	if cfg.LeaderElection.Enabled {
+		for _, verb := range []string{"get", "create", "update"} {
+			permissions = append(permissions, Permission{
+				Group:    "coordination.k8s.io",
+				Resource: "leases",
+				Verb:     verb,
+				Reason:   "leader can't be elected",
+			})
+		}
+	}
+
+	if cfg.Rules.Enabled {
+		for _, verb := range []string{"list", "watch"} {
+			permissions = append(permissions, Permission{
+				Group:    rule.Group,
+				Resource: rule.Resource,
+				Verb:     verb,
+				Reason:   "routing rules aren't applied",
+			})
+		}
+	}
+
+	if cfg.Silence.Resources {
+		for _, verb := range []string{"list", "watch"} {
+			permissions = append(permissions, Permission{
+				Group:    silence.Group,
+				Resource: silence.Resource,
+				Verb:     verb,
+				Reason:   "silence resources aren't applied",
+			})
+		}
+	}
+
+	return permissions
+}
+
+// Check reviews whether kwatch has required permissions in watched
+// namespaces
+func Check(client kubernetes.Interface, cfg *config.Config) []Result {
+	// pods are watched in all namespaces unless only one is allowed
+	namespace := ""
+	if len(cfg.AllowedNamespaces) == 1 {
+		namespace = cfg.AllowedNamespaces[0]
+	}
+
+	results := make([]Result, 0)
+	for _, permission := range RequiredPermissions(cfg) {
+		// rules and silences are watched in their own namespaces
+		switch permission.Resource {
+		case rule.Resource:
+			results = append(results,
+				check(client, permission, cfg.Rules.Namespace))
+			continue
+		case silence.Resource:
+			results = append(results,
+				check(client, permission, cfg.Silence.ResourcesNamespace))
+			continue
+		}
+		results = append(results, check(client, permission, namespace))
+	}
+	return results
+}
+
+// check reviews whether kwatch has given permission
+func check(
+	client kubernetes.Interface,
+	permission Permission,
+	namespace string) Result {
+	resource, subresource := permission.Resource, ""
+	if permission.Resource == "pods/log" {
+		resource, subresource = "pods", "log"
+	}
+
+	// nodes and leases aren't namespaced by pod namespace
+	if permission.Resource == "nodes" ||
+		permission.Resource == "leases" {
+		namespace = ""
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        permission.Verb,
+				Group:       permission.Group,
+				Resource:    resource,
+				Subresource: subresource,
+			},
+		},
+	}
+
+	result := Result{Permission: permission, Namespace: namespace}
+	review, err := client.AuthorizationV1().
+		SelfSubjectAccessReviews().
+		Create(context.TODO(), review, metav1.CreateOptions{})
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Allowed = review.Status.Allowed
+	return result
+}
@@ -0,0 +1,67 @@
+package doctor
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := fake.NewSimpleClientset()
+	cli.PrependReactor(
+		"create",
+		"selfsubjectaccessreviews",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			obj := action.(k8stesting.CreateAction).GetObject()
+			review := obj.(*authorizationv1.SelfSubjectAccessReview)
+			attrs := review.Spec.ResourceAttributes
+
+			// pods/log is not allowed
+			review.Status.Allowed = attrs.Subresource != "log"
+			return true, review, nil
+		})
+
+	cfg := config.DefaultConfig()
+	cfg.AllowedNamespaces = []string{"prod"}
+
+	results := Check(cli, cfg)
+	assert.Len(results, len(RequiredPermissions(cfg)))
+
+	for _, result := range results {
+		assert.Nil(result.Error)
+		if result.Resource == "pods/log" {
+			assert.False(result.Allowed)
+		} else {
+			assert.True(result.Allowed, result.Resource)
+		}
+
+		if result.Resource == "nodes" {
+			assert.Empty(result.Namespace)
+		} else {
+			assert.Equal("prod", result.Namespace)
+		}
+	}
+}
+
+func TestRequiredPermissions(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.DefaultConfig()
+	cfg.PvcMonitor.Enabled = false
+	basePermissions := RequiredPermissions(cfg)
+
+	cfg.PvcMonitor.Enabled = true
+	cfg.LeaderElection.Enabled = true
+	assert.Len(RequiredPermissions(cfg), len(basePermissions)+5)
+
+	cfg.Rules.Enabled = true
+	cfg.Silence.Resources = true
+	assert.Len(RequiredPermissions(cfg), len(basePermissions)+9)
+}
@@ -0,0 +1,153 @@
+package escalation
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+)
+
+// checkInterval is how often pending alerts are checked
+const checkInterval = time.Minute
+
+type Escalator struct {
+	config       *config.Escalation
+	alertManager *alertmanager.AlertManager
+
+	mu sync.Mutex
+
+	// pending are sent alerts waiting for escalation by failing container
+	pending map[string]*pendingAlert
+
+	// now returns current time, it's replaced in tests
+	now func() time.Time
+}
+
+// pendingAlert is a sent alert that's escalated if it isn't resolved by due
+// time
+type pendingAlert struct {
+	event event.Event
+	due   time.Time
+
+	// isResolved returns true if failure recovered or it was silenced
+	isResolved func() bool
+}
+
+// NewEscalator returns new instance of escalator
+func NewEscalator(
+	config *config.Escalation,
+	alertManager *alertmanager.AlertManager) *Escalator {
+	return &Escalator{
+		config:       config,
+		alertManager: alertManager,
+		pending:      make(map[string]*pendingAlert),
+		now:          time.Now,
+	}
+}
+
+// Enabled returns true if escalation is enabled
+func (e *Escalator) Enabled() bool {
+	return e != nil && e.config.Enabled
+}
+
+// Add schedules escalation of sent alert, it's escalated unless isResolved
+// returns true by then. Repeated alerts of the same container keep their
+// first due time
+func (e *Escalator) Add(ev *event.Event, isResolved func() bool) {
+	if !e.Enabled() {
+		return
+	}
+
+	key := getKey(ev)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.pending[key]; ok {
+		return
+	}
+
+	e.pending[key] = &pendingAlert{
+		event:      *ev,
+		due:        e.now().Add(time.Duration(e.config.After) * time.Minute),
+		isResolved: isResolved,
+	}
+}
+
+// Start escalates due alerts until stopCh is closed
+func (e *Escalator) Start(stopCh <-chan struct{}) {
+	if !e.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.Check()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// Check escalates due alerts which aren't resolved
+func (e *Escalator) Check() {
+	now := e.now()
+
+	e.mu.Lock()
+	due := make([]*pendingAlert, 0)
+	for key, alert := range e.pending {
+		if now.Before(alert.due) {
+			continue
+		}
+		due = append(due, alert)
+		delete(e.pending, key)
+	}
+	e.mu.Unlock()
+
+	for _, alert := range due {
+		if alert.isResolved() {
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"namespace": alert.event.Namespace,
+			"pod":       alert.event.PodName,
+			"container": alert.event.ContainerName,
+		}).Info("escalating unresolved alert")
+
+		e.alertManager.NotifyEventProviders(
+			e.config.Providers,
+			e.escalate(alert.event))
+	}
+}
+
+// escalate returns escalated copy of event with configured severity
+func (e *Escalator) escalate(ev event.Event) event.Event {
+	ev.Severity = e.config.Severity
+	ev.Details = append(
+		append([]event.Field{}, ev.Details...),
+		event.Field{
+			Name: "Escalation",
+			Value: fmt.Sprintf(
+				"not resolved after %d minutes",
+				e.config.After),
+		})
+	return ev
+}
+
+// getKey returns key of failing container of event
+func getKey(ev *event.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%s",
+		ev.Cluster,
+		ev.Namespace,
+		ev.PodName,
+		ev.ContainerName)
+}
@@ -0,0 +1,82 @@
+package escalation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewEscalator(&config.Escalation{After: 30}, nil)
+	e.Add(&event.Event{PodName: "api"}, func() bool { return false })
+	assert.Len(e.pending, 0)
+
+	now := time.Now()
+	e.config.Enabled = true
+	e.now = func() time.Time { return now }
+	e.Add(&event.Event{PodName: "api"}, func() bool { return false })
+
+	// repeated alerts keep first due time
+	now = now.Add(10 * time.Minute)
+	e.Add(&event.Event{PodName: "api"}, func() bool { return false })
+	e.Add(&event.Event{PodName: "db"}, func() bool { return false })
+	assert.Len(e.pending, 2)
+	assert.Equal(now.Add(20*time.Minute), e.pending["//api/"].due)
+
+	var nilEscalator *Escalator
+	nilEscalator.Add(&event.Event{PodName: "api"}, nil)
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	am := &alertmanager.AlertManager{}
+	am.Init(nil, &config.App{DryRun: true})
+
+	now := time.Now()
+	e := NewEscalator(&config.Escalation{
+		Enabled:   true,
+		After:     30,
+		Providers: []string{"pagerduty"},
+		Severity:  "critical",
+	}, am)
+	e.now = func() time.Time { return now }
+
+	resolvedChecks := 0
+	e.Add(&event.Event{PodName: "api"}, func() bool {
+		resolvedChecks++
+		return true
+	})
+	e.Add(&event.Event{PodName: "db"}, func() bool {
+		resolvedChecks++
+		return false
+	})
+
+	e.Check()
+	assert.Equal(0, resolvedChecks)
+	assert.Len(e.pending, 2)
+
+	now = now.Add(30 * time.Minute)
+	e.Check()
+	assert.Equal(2, resolvedChecks)
+	assert.Len(e.pending, 0)
+}
+
+func TestEscalate(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewEscalator(&config.Escalation{After: 30, Severity: "critical"}, nil)
+
+	details := []event.Field{{Name: "Workload", Value: "Deployment/api"}}
+	ev := e.escalate(event.Event{Severity: "info", Details: details})
+	assert.Equal("critical", ev.Severity)
+	assert.Len(ev.Details, 2)
+	assert.Equal("not resolved after 30 minutes", ev.Details[1].Value)
+	assert.Len(details, 1)
+}
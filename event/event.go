@@ -2,11 +2,64 @@ package event
 
 // Event used to represent info needed by providers to send messages
 type Event struct {
+	// Cluster is name of cluster the pod runs in, it's set when watching
+	// multiple clusters
+	Cluster string
+
 	PodName       string
 	ContainerName string
 	Namespace     string
 	Reason        string
 	Events        string
 	Logs          string
+	FullLogs      string
 	Labels        map[string]string
+
+	// Severity is an optional severity of alerts received from other tools
+	// e.g. critical
+	Severity string
+
+	// Summary is an optional generated plain-language summary of the failure
+	Summary string
+
+	// Details are extra context about the failure e.g. owning workload
+	Details []Field
+
+	// PodMetadata are selected pod labels and annotations shown in messages
+	PodMetadata []Field
+
+	// Links are rendered external links e.g. dashboards of the pod
+	Links []Field
+
+	// Commands are suggested kubectl commands to troubleshoot the pod
+	Commands []string
+
+	// sections are names of sections shown in messages in order, it's set
+	// per provider using WithSections
+	sections []string
+}
+
+// GetClusterName returns name of cluster of event, if it's not set
+// defaultName is returned
+func (e *Event) GetClusterName(defaultName string) string {
+	if len(e.Cluster) > 0 {
+		return e.Cluster
+	}
+	return defaultName
+}
+
+// Field is a named value shown in messages along with basic event info
+type Field struct {
+	Name  string
+	Value string
+}
+
+// ExtraFields returns optional fields of shown sections in the order they
+// should be displayed after basic event info
+func (e *Event) ExtraFields() []Field {
+	fields := make([]Field, 0)
+	for _, section := range e.Sections() {
+		fields = append(fields, section.Fields...)
+	}
+	return fields
 }
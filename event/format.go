@@ -7,21 +7,29 @@ import (
 	"github.com/abahmed/kwatch/constant"
 )
 
-func (e *Event) FormatMarkdown(clusterName, text, delimiter string) string {
-	// add events part if it exists
-	eventsText := constant.DefaultEvents
-	events := strings.TrimSpace(e.Events)
-	if len(events) > 0 {
-		eventsText = e.Events
+// LogsFileName returns file name used when full logs are attached to messages
+func (e *Event) LogsFileName() string {
+	if len(e.ContainerName) == 0 {
+		return e.PodName + ".log"
 	}
+	return e.PodName + "-" + e.ContainerName + ".log"
+}
 
-	// add logs part if it exist
-	logsText := constant.DefaultLogs
-	logs := strings.TrimSpace(e.Logs)
-	if len(logs) > 0 {
-		logsText = e.Logs
+// FormatExtraFieldsText returns extra fields as plain text lines
+func (e *Event) FormatExtraFieldsText() string {
+	text := ""
+	for _, field := range e.ExtraFields() {
+		text += fmt.Sprintf("%s: %s\n", field.Name, field.Value)
 	}
+	return text
+}
 
+// FormatCommandsText returns suggested commands as plain text lines
+func (e *Event) FormatCommandsText() string {
+	return strings.Join(e.Commands, "\n")
+}
+
+func (e *Event) FormatMarkdown(clusterName, text, delimiter string) string {
 	// use custom text if it's provided, otherwise use default
 	if len(text) == 0 {
 		text = constant.DefaultText
@@ -31,6 +39,22 @@ func (e *Event) FormatMarkdown(clusterName, text, delimiter string) string {
 		delimiter = "\n"
 	}
 
+	parts := make([]string, 0)
+	for _, section := range e.Sections() {
+		if section.IsFields() {
+			for _, field := range section.Fields {
+				parts = append(parts,
+					fmt.Sprintf("**%s:** %s", field.Name, field.Value))
+			}
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf(
+			"**%s:**\n```\n%s\n```",
+			section.Title,
+			section.DisplayText()))
+	}
+
 	msg := fmt.Sprintf(
 		"%s"+delimiter+
 			"**Cluster:** %s"+delimiter+
@@ -38,41 +62,40 @@ func (e *Event) FormatMarkdown(clusterName, text, delimiter string) string {
 			"**Container:** %s"+delimiter+
 			"**Namespace:** %s"+delimiter+
 			"**Reason:** %s"+delimiter+
-			"**Events:**\n```\n%s\n```"+delimiter+
-			"**Logs:**\n```\n%s\n```",
+			"%s",
 		text,
 		clusterName, e.PodName,
 		e.ContainerName,
 		e.Namespace,
 		e.Reason,
-		eventsText,
-		logsText,
+		strings.Join(parts, delimiter),
 	)
 
 	return msg
 }
 
 func (e *Event) FormatHtml(clusterName, text string) string {
-	eventsText := constant.DefaultEvents
-	logsText := constant.DefaultLogs
-
-	// add events part if it exists
-	events := strings.TrimSpace(e.Events)
-	if len(events) > 0 {
-		eventsText = e.Events
-	}
-
-	// add logs part if it exists
-	logs := strings.TrimSpace(e.Logs)
-	if len(logs) > 0 {
-		logsText = e.Logs
-	}
-
 	// use custom text if it's provided, otherwise use default
 	if len(text) == 0 {
 		text = constant.DefaultText
 	}
 
+	sectionsText := ""
+	for _, section := range e.Sections() {
+		if section.IsFields() {
+			for _, field := range section.Fields {
+				sectionsText += fmt.Sprintf(
+					"<b>%s:</b> %s<br/>", field.Name, field.Value)
+			}
+			continue
+		}
+
+		sectionsText += fmt.Sprintf(
+			"<b>%s:</b><br/><blockquote>%s</blockquote>",
+			section.Title,
+			strings.ReplaceAll(section.DisplayText(), "\n", "<br/>"))
+	}
+
 	msg := fmt.Sprintf(
 		"%s<br/>"+
 			"<b>Cluster:</b> %s <br/>"+
@@ -80,58 +103,68 @@ func (e *Event) FormatHtml(clusterName, text string) string {
 			"<b>Container:</b> %s<br/>"+
 			"<b>Namespace:</b> %s<br/>"+
 			"<b>Reason:</b> %s<br/>"+
-			"<b>Events:</b><br/><blockquote>%s</blockquote>"+
-			"<b>Logs:</b> <br/><blockquote>%s</blockquote>",
+			"%s",
 		text,
 		clusterName,
 		e.PodName,
 		e.ContainerName,
 		e.Namespace,
 		e.Reason,
-		strings.ReplaceAll(eventsText, "\n", "<br/>"),
-		strings.ReplaceAll(logsText, "\n", "<br/>"),
+		sectionsText,
 	)
 
 	return msg
 }
 
-func (e *Event) FormatText(clusterName, text string) string {
-	eventsText := constant.DefaultEvents
-	logsText := constant.DefaultLogs
-
-	// add events part if it exists
-	events := strings.TrimSpace(e.Events)
-	if len(events) > 0 {
-		eventsText = e.Events
+// FormatSectionsText returns sections in configured order as plain text
+func (e *Event) FormatSectionsText() string {
+	// text sections are separated from previous lines by an empty line
+	sectionsText := ""
+	needsEmptyLine := true
+	for _, section := range e.Sections() {
+		if section.IsFields() {
+			for _, field := range section.Fields {
+				sectionsText += fmt.Sprintf("%s: %s\n", field.Name, field.Value)
+			}
+			needsEmptyLine = true
+			continue
+		}
+
+		if needsEmptyLine {
+			sectionsText += "\n"
+			needsEmptyLine = false
+		}
+
+		sectionsText += fmt.Sprintf(
+			"%s:\n%s\n\n",
+			section.Title,
+			section.DisplayText())
 	}
 
-	// add logs part if it exists
-	logs := strings.TrimSpace(e.Logs)
-	if len(logs) > 0 {
-		logsText = e.Logs
-	}
+	return sectionsText
+}
 
+func (e *Event) FormatText(clusterName, text string) string {
 	// use custom text if it's provided, otherwise use default
 	if len(text) == 0 {
 		text = constant.DefaultText
 	}
 
 	msg := fmt.Sprintf(
-		"There is an issue with container in a pod!\n\n"+
+		"%s\n\n"+
 			"cluster: %s\n"+
 			"Pod Name: %s\n"+
 			"Container: %s\n"+
 			"Namespace: %s\n"+
-			"Reason: %s\n\n"+
-			"Events:\n%s\n\n"+
-			"Logs:\n%s\n\n",
+			"Reason: %s\n"+
+			"%s",
+		text,
 		clusterName,
 		e.PodName,
 		e.ContainerName,
 		e.Namespace,
 		e.Reason,
-		eventsText,
-		logsText,
+		e.FormatSectionsText(),
 	)
 
 	return msg
@@ -0,0 +1,67 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatHtml(t *testing.T) {
+	assert := assert.New(t)
+
+	ev := &Event{
+		PodName:       "test-pod",
+		ContainerName: "test-container",
+		Namespace:     "default",
+		Reason:        "OOMKilled",
+		Events:        "ev",
+		Logs:          "a\nb",
+		Details:       []Field{{Name: "Owner", Value: "Deployment/test"}},
+	}
+
+	assert.Equal(
+		"text<br/>"+
+			"<b>Cluster:</b> dev <br/>"+
+			"<b>Pod:</b> test-pod <br/>"+
+			"<b>Container:</b> test-container<br/>"+
+			"<b>Namespace:</b> default<br/>"+
+			"<b>Reason:</b> OOMKilled<br/>"+
+			"<b>Owner:</b> Deployment/test<br/>"+
+			"<b>Events:</b><br/><blockquote>ev</blockquote>"+
+			"<b>Logs:</b><br/><blockquote>a<br/>b</blockquote>",
+		ev.FormatHtml("dev", "text"))
+
+	ev = ev.WithSections([]string{SectionLogs})
+	assert.Equal(
+		"text<br/>"+
+			"<b>Cluster:</b> dev <br/>"+
+			"<b>Pod:</b> test-pod <br/>"+
+			"<b>Container:</b> test-container<br/>"+
+			"<b>Namespace:</b> default<br/>"+
+			"<b>Reason:</b> OOMKilled<br/>"+
+			"<b>Logs:</b><br/><blockquote>a<br/>b</blockquote>",
+		ev.FormatHtml("dev", "text"))
+}
+
+func TestFormatText(t *testing.T) {
+	assert := assert.New(t)
+
+	ev := &Event{
+		PodName:       "test-pod",
+		ContainerName: "test-container",
+		Namespace:     "default",
+		Reason:        "OOMKilled",
+		Events:        "ev",
+		Logs:          "a\nb",
+	}
+
+	assert.Equal(
+		"text\n\n"+
+			"cluster: dev\n"+
+			"Pod Name: test-pod\n"+
+			"Container: test-container\n"+
+			"Namespace: default\n"+
+			"Reason: OOMKilled\n"+
+			"\nEvents:\nev\n\nLogs:\na\nb\n\n",
+		ev.FormatText("dev", "text"))
+}
@@ -0,0 +1,155 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abahmed/kwatch/constant"
+	"github.com/sirupsen/logrus"
+)
+
+// Render modes of messages, providers support a subset of them based on
+// their capabilities
+const (
+	RenderMarkdown = "markdown"
+	RenderSlack    = "slack"
+	RenderPlain    = "plain"
+	RenderHTML     = "html"
+	RenderJSON     = "json"
+)
+
+// RenderOptions used to render messages
+type RenderOptions struct {
+	// ClusterName shown in messages
+	ClusterName string
+
+	// Text is custom text shown at the beginning of messages
+	Text string
+
+	// Delimiter used between lines of markdown messages
+	Delimiter string
+}
+
+// GetRenderMode returns render mode configured for provider in renderMode if
+// it's supported, otherwise it returns default mode which is the first
+// supported one
+func GetRenderMode(
+	providerCfg map[string]interface{},
+	supportedModes ...string) string {
+	mode, _ := providerCfg["renderMode"].(string)
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if len(mode) == 0 {
+		return supportedModes[0]
+	}
+
+	for _, supportedMode := range supportedModes {
+		if supportedMode == mode {
+			return mode
+		}
+	}
+
+	logrus.Warnf(
+		"unsupported render mode %s, supported modes are %s",
+		mode,
+		strings.Join(supportedModes, ", "))
+	return supportedModes[0]
+}
+
+// Render returns event rendered in given mode
+func (e *Event) Render(mode string, opts RenderOptions) string {
+	switch mode {
+	case RenderSlack:
+		return e.FormatSlackMarkdown(opts.ClusterName, opts.Text)
+	case RenderPlain:
+		return e.FormatText(opts.ClusterName, opts.Text)
+	case RenderHTML:
+		return e.FormatHtml(opts.ClusterName, opts.Text)
+	case RenderJSON:
+		return e.FormatJSON(opts.ClusterName, opts.Text)
+	}
+	return e.FormatMarkdown(opts.ClusterName, opts.Text, opts.Delimiter)
+}
+
+// FormatSlackMarkdown returns event formatted as Slack mrkdwn which uses
+// single asterisks for bold text
+func (e *Event) FormatSlackMarkdown(clusterName, text string) string {
+	// use custom text if it's provided, otherwise use default
+	if len(text) == 0 {
+		text = constant.DefaultText
+	}
+
+	lines := []string{
+		text,
+		"*Cluster:* " + clusterName,
+		"*Pod:* " + e.PodName,
+		"*Container:* " + e.ContainerName,
+		"*Namespace:* " + e.Namespace,
+		"*Reason:* " + e.Reason,
+	}
+
+	for _, section := range e.Sections() {
+		if section.IsFields() {
+			for _, field := range section.Fields {
+				lines = append(lines,
+					fmt.Sprintf("*%s:* %s", field.Name, field.Value))
+			}
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"*%s:*\n```\n%s\n```",
+			section.Title,
+			section.DisplayText()))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// jsonSection is a section of messages rendered as JSON
+type jsonSection struct {
+	Name   string            `json:"name"`
+	Title  string            `json:"title"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Text   string            `json:"text,omitempty"`
+}
+
+// FormatJSON returns event formatted as JSON object with sections in
+// configured order
+func (e *Event) FormatJSON(clusterName, text string) string {
+	// use custom text if it's provided, otherwise use default
+	if len(text) == 0 {
+		text = constant.DefaultText
+	}
+
+	sections := make([]jsonSection, 0)
+	for _, section := range e.Sections() {
+		jsonSection := jsonSection{
+			Name:  section.Name,
+			Title: section.Title,
+		}
+
+		if section.IsFields() {
+			jsonSection.Fields = make(map[string]string)
+			for _, field := range section.Fields {
+				jsonSection.Fields[field.Name] = field.Value
+			}
+		} else {
+			jsonSection.Text = section.Text
+		}
+
+		sections = append(sections, jsonSection)
+	}
+
+	msg, _ := json.Marshal(map[string]interface{}{
+		"text":      text,
+		"cluster":   clusterName,
+		"pod":       e.PodName,
+		"container": e.ContainerName,
+		"namespace": e.Namespace,
+		"reason":    e.Reason,
+		"labels":    e.Labels,
+		"sections":  sections,
+	})
+	return string(msg)
+}
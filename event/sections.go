@@ -0,0 +1,150 @@
+package event
+
+import (
+	"strings"
+
+	"github.com/abahmed/kwatch/constant"
+	"github.com/sirupsen/logrus"
+)
+
+// Sections of messages which can be selected and ordered per provider
+const (
+	SectionMetadata = "metadata"
+	SectionLabels   = "labels"
+	SectionLinks    = "links"
+	SectionEvents   = "events"
+	SectionLogs     = "logs"
+	SectionCommands = "commands"
+)
+
+// DefaultSections are sections shown in messages when providers don't
+// configure their own
+var DefaultSections = []string{
+	SectionMetadata,
+	SectionLabels,
+	SectionLinks,
+	SectionEvents,
+	SectionLogs,
+	SectionCommands,
+}
+
+// Section is a part of message shown after basic event info, it has either
+// fields or text
+type Section struct {
+	Name   string
+	Title  string
+	Fields []Field
+	Text   string
+}
+
+// IsFields returns true if section is rendered as a list of fields
+func (s *Section) IsFields() bool {
+	return s.Name == SectionMetadata ||
+		s.Name == SectionLabels ||
+		s.Name == SectionLinks
+}
+
+// DisplayText returns text of section, or a placeholder if events or logs
+// are not captured
+func (s *Section) DisplayText() string {
+	if len(strings.TrimSpace(s.Text)) > 0 {
+		return s.Text
+	}
+
+	switch s.Name {
+	case SectionEvents:
+		return constant.DefaultEvents
+	case SectionLogs:
+		return constant.DefaultLogs
+	}
+	return s.Text
+}
+
+// WithSections returns a copy of event showing only given sections in given
+// order, unknown sections are skipped. If sections is empty, default
+// sections are used
+func (e *Event) WithSections(sections []string) *Event {
+	ev := *e
+	ev.sections = nil
+	for _, name := range sections {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isKnownSection(name) {
+			logrus.Warnf("unknown message section %s", name)
+			continue
+		}
+		ev.sections = append(ev.sections, name)
+	}
+	return &ev
+}
+
+// SectionNames returns names of sections shown in messages in order
+func (e *Event) SectionNames() []string {
+	if len(e.sections) == 0 {
+		return DefaultSections
+	}
+	return e.sections
+}
+
+// HasSection returns true if section is shown in messages
+func (e *Event) HasSection(name string) bool {
+	for _, section := range e.SectionNames() {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Sections returns sections shown in messages in order. Fields and commands
+// sections without content are skipped, while events and logs sections are
+// always returned so providers can show placeholders
+func (e *Event) Sections() []Section {
+	result := make([]Section, 0, len(e.SectionNames()))
+	for _, name := range e.SectionNames() {
+		section := e.section(name)
+		if section.IsFields() && len(section.Fields) == 0 {
+			continue
+		}
+		if name == SectionCommands && len(section.Text) == 0 {
+			continue
+		}
+		result = append(result, section)
+	}
+	return result
+}
+
+func (e *Event) section(name string) Section {
+	switch name {
+	case SectionMetadata:
+		fields := make([]Field, 0, len(e.Details)+1)
+		if len(e.Summary) > 0 {
+			fields = append(fields, Field{Name: "Summary", Value: e.Summary})
+		}
+		fields = append(fields, e.Details...)
+		return Section{Name: name, Title: "Details", Fields: fields}
+	case SectionLabels:
+		return Section{Name: name, Title: "Labels", Fields: e.PodMetadata}
+	case SectionLinks:
+		return Section{Name: name, Title: "Links", Fields: e.Links}
+	case SectionEvents:
+		return Section{Name: name, Title: "Events", Text: e.Events}
+	case SectionLogs:
+		return Section{Name: name, Title: "Logs", Text: e.Logs}
+	case SectionCommands:
+		return Section{
+			Name:  name,
+			Title: "Commands",
+			Text:  e.FormatCommandsText(),
+		}
+	}
+	return Section{Name: name}
+}
+
+func isKnownSection(name string) bool {
+	for _, section := range DefaultSections {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package event
+
+import (
+	"encoding/json"
+
+	"github.com/abahmed/kwatch/util"
+)
+
+// Truncation strategies of events exceeding max payload size of providers
+const (
+	// TruncateHead keeps beginning of logs and events
+	TruncateHead = "head"
+
+	// TruncateTail keeps end of logs and events
+	TruncateTail = "tail"
+
+	// TruncateHeadTail keeps beginning and end of logs and events
+	TruncateHeadTail = "headTail"
+
+	// TruncateDropLogs drops logs first, then truncates events keeping
+	// their beginning and end
+	TruncateDropLogs = "dropLogs"
+)
+
+// TruncationStrategies are supported truncation strategies, the first one is
+// default
+var TruncationStrategies = []string{
+	TruncateHeadTail,
+	TruncateHead,
+	TruncateTail,
+	TruncateDropLogs,
+}
+
+// maxTruncatePasses is max number of passes truncating event, rendered size
+// shrinks less than text as escaping is dropped too
+const maxTruncatePasses = 3
+
+// Truncate returns a copy of event whose logs, then events, are truncated
+// using strategy so it's rendered within maxBytes, or event itself if it
+// fits or maxBytes is 0
+func (e *Event) Truncate(maxBytes int, strategy string) *Event {
+	if maxBytes <= 0 || e.renderedSize() <= maxBytes {
+		return e
+	}
+
+	ev := *e
+	if strategy == TruncateDropLogs {
+		ev.Logs = ""
+		ev.FullLogs = ""
+		strategy = TruncateHeadTail
+	}
+
+	for i := 0; i < maxTruncatePasses; i++ {
+		excess := ev.renderedSize() - maxBytes
+		if excess <= 0 || (len(ev.Logs) == 0 && len(ev.Events) == 0) {
+			break
+		}
+
+		ev.Logs, excess = truncateText(ev.Logs, excess, strategy)
+		ev.Events, _ = truncateText(ev.Events, excess, strategy)
+	}
+	return &ev
+}
+
+// renderedSize returns size of event rendered as plain text or json,
+// whichever is larger
+func (e *Event) renderedSize() int {
+	size := len(e.FormatText(e.Cluster, ""))
+	if jsonSize := len(e.FormatJSON(e.Cluster, "")); jsonSize > size {
+		size = jsonSize
+	}
+	return size
+}
+
+// truncateText returns text shortened using strategy so its rendered size
+// shrinks by excess bytes, and excess left if text is too short for it
+func truncateText(text string, excess int, strategy string) (string, int) {
+	if excess <= 0 || len(text) == 0 {
+		return text, excess
+	}
+
+	// escaping in json grows rendered size of text, so excess is converted
+	// to bytes of text
+	size := escapedSize(text)
+	maxBytes := len(text) - (excess*len(text)+size-1)/size
+	if maxBytes <= 0 {
+		return "", excess - size
+	}
+
+	var truncated string
+	switch strategy {
+	case TruncateHead:
+		truncated = util.TruncateHead(text, maxBytes)
+	case TruncateTail:
+		truncated = util.TruncateTail(text, maxBytes)
+	default:
+		truncated = util.TruncateLogs(text, maxBytes)
+	}
+	return truncated, excess - (size - escapedSize(truncated))
+}
+
+// escapedSize returns size of text escaped in json
+func escapedSize(text string) int {
+	escaped, _ := json.Marshal(text)
+	return len(escaped) - 2
+}
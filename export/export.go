@@ -0,0 +1,205 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// csvHeader is header row of csv export files, logs and events are left out
+// as they're multi-line and rarely useful in spreadsheets
+var csvHeader = []string{
+	"time",
+	"cluster",
+	"namespace",
+	"pod",
+	"container",
+	"reason",
+	"silenced",
+	"summary",
+}
+
+// Record is an exported failure event
+type Record struct {
+	Time      time.Time         `json:"time"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Namespace string            `json:"namespace"`
+	Pod       string            `json:"pod"`
+	Container string            `json:"container,omitempty"`
+	Reason    string            `json:"reason"`
+	Silenced  bool              `json:"silenced"`
+	Summary   string            `json:"summary,omitempty"`
+	Events    string            `json:"events,omitempty"`
+	Logs      string            `json:"logs,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// NewRecord returns record of event observed now
+func NewRecord(ev *event.Event, silenced bool) *Record {
+	return &Record{
+		Time:      time.Now(),
+		Cluster:   ev.Cluster,
+		Namespace: ev.Namespace,
+		Pod:       ev.PodName,
+		Container: ev.ContainerName,
+		Reason:    ev.Reason,
+		Silenced:  silenced,
+		Summary:   ev.Summary,
+		Events:    ev.Events,
+		Logs:      ev.Logs,
+		Labels:    ev.Labels,
+	}
+}
+
+// Event returns event of record
+func (r *Record) Event() *event.Event {
+	return &event.Event{
+		Cluster:       r.Cluster,
+		PodName:       r.Pod,
+		ContainerName: r.Container,
+		Namespace:     r.Namespace,
+		Reason:        r.Reason,
+		Summary:       r.Summary,
+		Events:        r.Events,
+		Logs:          r.Logs,
+		Labels:        r.Labels,
+	}
+}
+
+type Exporter struct {
+	config *config.Export
+
+	mu sync.Mutex
+}
+
+// NewExporter returns new instance of exporter
+func NewExporter(config *config.Export) *Exporter {
+	return &Exporter{
+		config: config,
+	}
+}
+
+// Enabled returns true if export is enabled
+func (e *Exporter) Enabled() bool {
+	return e != nil && e.config.Enabled && len(e.config.Path) > 0
+}
+
+// Export appends event to export file, rotating it if it's over max size
+func (e *Exporter) Export(ev *event.Event, silenced bool) {
+	if !e.Enabled() {
+		return
+	}
+
+	if err := e.write(NewRecord(ev, silenced)); err != nil {
+		logrus.WithField("path", e.config.Path).
+			WithError(err).
+			Error("failed to export event")
+	}
+}
+
+// write appends record to export file in configured format
+func (e *Exporter) write(record *Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	size := int64(0)
+	if info, err := os.Stat(e.config.Path); err == nil {
+		size = info.Size()
+	}
+
+	maxSize := int64(e.config.MaxSize) * 1024 * 1024
+	if maxSize > 0 && size >= maxSize {
+		if err := e.rotate(); err != nil {
+			return err
+		}
+		size = 0
+	}
+
+	data, err := e.encode(record, size == 0)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(
+		e.config.Path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+// encode returns record as a json or csv line, csv header is added to new
+// files
+func (e *Exporter) encode(record *Record, newFile bool) ([]byte, error) {
+	if e.config.Format != FormatCSV {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if newFile {
+		w.Write(csvHeader)
+	}
+	w.Write([]string{
+		record.Time.Format(time.RFC3339),
+		record.Cluster,
+		record.Namespace,
+		record.Pod,
+		record.Container,
+		record.Reason,
+		strconv.FormatBool(record.Silenced),
+		record.Summary,
+	})
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// rotate shifts rotated files e.g. export.jsonl.1 to export.jsonl.2, moves
+// export file to export.jsonl.1 and removes files over max files
+func (e *Exporter) rotate() error {
+	maxFiles := e.config.MaxFiles
+	if maxFiles < 1 {
+		return os.Remove(e.config.Path)
+	}
+
+	os.Remove(rotatedPath(e.config.Path, maxFiles))
+	for i := maxFiles - 1; i > 0; i-- {
+		err := os.Rename(
+			rotatedPath(e.config.Path, i),
+			rotatedPath(e.config.Path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(e.config.Path, rotatedPath(e.config.Path, 1))
+}
+
+// rotatedPath returns path of nth rotated file
+func rotatedPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExporterDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	var e *Exporter
+	assert.False(e.Enabled())
+	e.Export(&event.Event{PodName: "test"}, false)
+
+	assert.False(NewExporter(&config.Export{Enabled: true}).Enabled())
+}
+
+func TestExportJSONL(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "export.jsonl")
+	e := NewExporter(&config.Export{
+		Enabled: true,
+		Path:    path,
+		Format:  FormatJSONL,
+	})
+
+	e.Export(&event.Event{
+		Namespace:     "default",
+		PodName:       "api-0",
+		ContainerName: "api",
+		Reason:        "OOMKilled",
+		Logs:          "out of memory",
+	}, false)
+	e.Export(&event.Event{PodName: "db-0", Reason: "Error"}, true)
+
+	data, err := os.ReadFile(path)
+	assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(lines, 2)
+
+	var record Record
+	assert.Nil(json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal("api-0", record.Pod)
+	assert.False(record.Silenced)
+
+	ev := record.Event()
+	assert.Equal("api", ev.ContainerName)
+	assert.Equal("OOMKilled", ev.Reason)
+	assert.Equal("out of memory", ev.Logs)
+
+	assert.Nil(json.Unmarshal([]byte(lines[1]), &record))
+	assert.True(record.Silenced)
+}
+
+func TestExportCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "export.csv")
+	e := NewExporter(&config.Export{
+		Enabled: true,
+		Path:    path,
+		Format:  FormatCSV,
+	})
+
+	e.Export(&event.Event{PodName: "api-0", Summary: "a, b"}, false)
+	e.Export(&event.Event{PodName: "db-0"}, true)
+
+	data, err := os.ReadFile(path)
+	assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(lines, 3)
+	assert.Equal(strings.Join(csvHeader, ","), lines[0])
+	assert.Contains(lines[1], ",api-0,")
+	assert.Contains(lines[1], `"a, b"`)
+	assert.Contains(lines[2], ",true,")
+}
+
+func TestRotate(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "export.csv")
+	e := NewExporter(&config.Export{
+		Enabled:  true,
+		Path:     path,
+		Format:   FormatCSV,
+		MaxSize:  1,
+		MaxFiles: 2,
+	})
+
+	// fill export file up to max size so each export rotates it
+	fill := strings.Repeat("x", 1024*1024)
+	for i := 0; i < 3; i++ {
+		assert.Nil(os.WriteFile(path, []byte(fill), 0644))
+		e.Export(&event.Event{PodName: "api-0"}, false)
+	}
+
+	data, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(string(data), "time,"))
+
+	_, err = os.Stat(path + ".1")
+	assert.Nil(err)
+	_, err = os.Stat(path + ".2")
+	assert.Nil(err)
+	_, err = os.Stat(path + ".3")
+	assert.True(os.IsNotExist(err))
+}
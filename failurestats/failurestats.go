@@ -0,0 +1,151 @@
+package failurestats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// Tracker counts failures of workloads by reason, and remembers when they
+// were first seen and last alerted
+type Tracker struct {
+	config *config.FailureStats
+
+	mu sync.Mutex
+
+	// stats are failure stats by workload and reason
+	stats map[string]*Stats
+
+	// lastPrune is time stale failures were last removed
+	lastPrune time.Time
+
+	// now returns current time, it's replaced in tests
+	now func() time.Time
+}
+
+// Stats are stats of failure of a workload with a reason
+type Stats struct {
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	Occurrences int
+
+	// LastAlerted is time failure was last alerted, zero if it wasn't
+	LastAlerted time.Time
+}
+
+// NewTracker returns new instance of failure stats tracker
+func NewTracker(config *config.FailureStats) *Tracker {
+	return &Tracker{
+		config:    config,
+		stats:     make(map[string]*Stats),
+		lastPrune: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Enabled returns true if failure stats are enabled
+func (t *Tracker) Enabled() bool {
+	return t != nil && t.config.Enabled
+}
+
+// Observe counts failure with key e.g. default/Deployment/api/Error
+func (t *Tracker) Observe(key string) {
+	if !t.Enabled() {
+		return
+	}
+
+	now := t.now()
+	retention := time.Duration(t.config.Retention) * time.Hour
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastPrune) > time.Hour {
+		t.prune(now.Add(-retention))
+		t.lastPrune = now
+	}
+
+	stats, ok := t.stats[key]
+	if !ok || now.Sub(stats.LastSeen) > retention {
+		stats = &Stats{FirstSeen: now}
+		t.stats[key] = stats
+	}
+	stats.LastSeen = now
+	stats.Occurrences++
+}
+
+// Alerted records alert of failure with key
+func (t *Tracker) Alerted(key string) {
+	if !t.Enabled() {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if stats, ok := t.stats[key]; ok {
+		stats.LastAlerted = t.now()
+	}
+}
+
+// Describe returns stats of failure with key e.g. first seen 3d ago,
+// 27 occurrences, last alerted 2h ago, or empty string if it wasn't seen
+func (t *Tracker) Describe(key string) string {
+	if !t.Enabled() {
+		return ""
+	}
+
+	t.mu.Lock()
+	stats, ok := t.stats[key]
+	var s Stats
+	if ok {
+		s = *stats
+	}
+	t.mu.Unlock()
+
+	if !ok {
+		return ""
+	}
+	return s.describe(t.now())
+}
+
+// describe returns stats relative to now
+func (s *Stats) describe(now time.Time) string {
+	description := fmt.Sprintf(
+		"first seen %s, %d occurrence",
+		formatAge(now.Sub(s.FirstSeen)),
+		s.Occurrences)
+	if s.Occurrences != 1 {
+		description += "s"
+	}
+
+	if !s.LastAlerted.IsZero() {
+		description += ", last alerted " + formatAge(now.Sub(s.LastAlerted))
+	}
+	return description
+}
+
+// prune removes failures not seen since given time
+func (t *Tracker) prune(since time.Time) {
+	for key, stats := range t.stats {
+		if stats.LastSeen.Before(since) {
+			delete(t.stats, key)
+		}
+	}
+}
+
+// formatAge returns age in largest whole unit e.g. 3d ago
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,70 @@
+package failurestats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	tracker := NewTracker(&config.FailureStats{Enabled: true, Retention: 168})
+	tracker.now = func() time.Time { return now }
+
+	key := "default/Deployment/api/Error"
+	assert.Empty(tracker.Describe(key))
+
+	tracker.Observe(key)
+	assert.Equal("first seen just now, 1 occurrence", tracker.Describe(key))
+	tracker.Alerted(key)
+
+	for i := 0; i < 26; i++ {
+		now = now.Add(time.Hour)
+		tracker.Observe(key)
+	}
+	now = now.Add(46 * time.Hour)
+	tracker.Observe(key)
+	assert.Equal(
+		"first seen 3d ago, 28 occurrences, last alerted 3d ago",
+		tracker.Describe(key))
+
+	tracker.Alerted(key)
+	now = now.Add(2 * time.Hour)
+	tracker.Observe(key)
+	assert.Equal(
+		"first seen 3d ago, 29 occurrences, last alerted 2h ago",
+		tracker.Describe(key))
+
+	// failure is forgotten once it's not seen for retention
+	now = now.Add(169 * time.Hour)
+	tracker.Observe(key)
+	assert.Equal("first seen just now, 1 occurrence", tracker.Describe(key))
+	assert.Len(tracker.stats, 1)
+}
+
+func TestDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	tracker := NewTracker(&config.FailureStats{Retention: 168})
+	tracker.Observe("default/Deployment/api/Error")
+	tracker.Alerted("default/Deployment/api/Error")
+	assert.Empty(tracker.Describe("default/Deployment/api/Error"))
+	assert.Empty(tracker.stats)
+
+	var nilTracker *Tracker
+	nilTracker.Observe("default/Deployment/api/Error")
+	assert.Empty(nilTracker.Describe("default/Deployment/api/Error"))
+}
+
+func TestFormatAge(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("just now", formatAge(30*time.Second))
+	assert.Equal("45m ago", formatAge(45*time.Minute))
+	assert.Equal("2h ago", formatAge(150*time.Minute))
+	assert.Equal("3d ago", formatAge(80*time.Hour))
+}
@@ -0,0 +1,44 @@
+package filter
+
+import (
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type ContainerExitCodeFilter struct{}
+
+func (f ContainerExitCodeFilter) Execute(ctx *Context) bool {
+	if len(ctx.Config.IgnoreExitCodes) == 0 {
+		return false
+	}
+
+	terminated := getTerminatedState(ctx)
+	if terminated == nil ||
+		!slices.Contains(ctx.Config.IgnoreExitCodes, terminated.ExitCode) {
+		return false
+	}
+
+	ctx.Logger().
+		WithField("exitCode", terminated.ExitCode).
+		Info("skipping container as its exit code is ignored")
+	return true
+}
+
+// getTerminatedState returns termination state of container the reported
+// reason is taken from, or nil if container is waiting without a crash
+func getTerminatedState(ctx *Context) *corev1.ContainerStateTerminated {
+	container := ctx.Container.Container
+
+	var terminated *corev1.ContainerStateTerminated
+	if container.State.Waiting == nil {
+		terminated = container.State.Terminated
+	}
+
+	crashLooping := container.State.Waiting != nil &&
+		container.State.Waiting.Reason == "CrashLoopBackOff"
+	if (crashLooping || ctx.Container.HasRestarts) &&
+		container.LastTerminationState.Terminated != nil {
+		terminated = container.LastTerminationState.Terminated
+	}
+	return terminated
+}
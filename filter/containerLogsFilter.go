@@ -1,9 +1,17 @@
 package filter
 
 import (
+	"strings"
+
 	"github.com/abahmed/kwatch/util"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
 )
 
+// filteredLogsTailFactor is used to fetch more log lines when log filters are
+// configured, so dropped lines don't crowd out the useful ones
+const filteredLogsTailFactor = 10
+
 type ContainerLogsFilter struct{}
 
 func (f ContainerLogsFilter) Execute(ctx *Context) bool {
@@ -13,8 +21,103 @@ func (f ContainerLogsFilter) Execute(ctx *Context) bool {
 		return false
 	}
 
+	logFilters := &ctx.Config.LogFilters
+	hasLogFilters := len(logFilters.IncludePatterns) > 0 ||
+		len(logFilters.ExcludePatterns) > 0
+
+	fetchLogLines := ctx.Config.MaxRecentLogLines
+	if hasLogFilters {
+		fetchLogLines *= filteredLogsTailFactor
+	}
+
+	// attached logs need a larger capture than inline ones
+	maxAttachedLogLines := ctx.Config.MaxAttachedLogLines
+	if fetchLogLines != 0 && maxAttachedLogLines > fetchLogLines {
+		fetchLogLines = maxAttachedLogLines
+	}
+
+	logs := getContainerLogs(ctx, container, fetchLogLines)
+
+	// crashes are fingerprinted from logs of container only
+	ctx.Container.RawLogs = logs
+
+	if maxAttachedLogLines > 0 {
+		ctx.Container.FullLogs = util.TailLogLines(logs, maxAttachedLogLines)
+	}
+
+	if hasLogFilters {
+		logs = util.FilterLogLines(
+			logs,
+			logFilters.IncludePatterns,
+			logFilters.ExcludePatterns)
+	}
+
+	logs = util.TailLogLines(logs, ctx.Config.MaxRecentLogLines)
+
+	// add labelled logs sections of other containers in the pod, as the
+	// root cause of failure may live in a sidecar
+	if ctx.Config.MultiContainerLogs.Enabled {
+		logs = logsSection(container.Name, logs) +
+			getOtherContainersLogs(ctx, hasLogFilters)
+	}
+
+	ctx.Container.Logs = util.TruncateLogs(logs, ctx.Config.MaxRecentLogBytes)
+	return false
+}
+
+// getOtherContainersLogs returns labelled log sections of pod containers
+// other than the failing one
+func getOtherContainersLogs(ctx *Context, hasLogFilters bool) string {
+	cfg := &ctx.Config.MultiContainerLogs
+	maxLogLines := cfg.MaxLogLines
+	if maxLogLines == 0 {
+		maxLogLines = ctx.Config.MaxRecentLogLines
+	}
+
+	fetchLogLines := maxLogLines
+	if hasLogFilters {
+		fetchLogLines *= filteredLogsTailFactor
+	}
+
+	result := ""
+	for i := range ctx.Pod.Status.ContainerStatuses {
+		status := &ctx.Pod.Status.ContainerStatuses[i]
+		if status.Name == ctx.Container.Container.Name {
+			continue
+		}
+
+		if len(cfg.Containers) > 0 &&
+			!slices.Contains(cfg.Containers, status.Name) {
+			continue
+		}
+
+		logs := getContainerLogs(ctx, status, fetchLogLines)
+		if hasLogFilters {
+			logs = util.FilterLogLines(
+				logs,
+				ctx.Config.LogFilters.IncludePatterns,
+				ctx.Config.LogFilters.ExcludePatterns)
+		}
+
+		result += logsSection(
+			status.Name,
+			util.TailLogLines(logs, maxLogLines))
+	}
+
+	return result
+}
+
+// getContainerLogs returns logs of container with secrets masked, if
+// container has restarted, it gets logs of the crashed instance as the
+// current one's logs are often empty or unrelated to the crash
+func getContainerLogs(
+	ctx *Context,
+	container *corev1.ContainerStatus,
+	maxLogLines int64) string {
 	previousLogs := false
-	if ctx.Container.HasRestarts && container.State.Running != nil {
+	if container.RestartCount > 0 &&
+		container.State.Terminated == nil &&
+		container.LastTerminationState.Terminated != nil {
 		previousLogs = true
 	}
 
@@ -24,8 +127,30 @@ func (f ContainerLogsFilter) Execute(ctx *Context) bool {
 		container.Name,
 		ctx.Pod.Namespace,
 		previousLogs,
-		ctx.Config.MaxRecentLogLines)
+		maxLogLines)
 
-	ctx.Container.Logs = logs
-	return false
+	// fallback to current logs if previous instance has no logs
+	if previousLogs && len(strings.TrimSpace(logs)) == 0 {
+		logs = util.GetPodContainerLogs(
+			ctx.Client,
+			ctx.Pod.Name,
+			container.Name,
+			ctx.Pod.Namespace,
+			false,
+			maxLogLines)
+	}
+
+	// mask secrets of whole capture before it's filtered or tailed, so
+	// multi-line secrets e.g. private keys aren't cut before being matched
+	redactPatterns := ctx.Config.Redaction.CompiledPatterns
+	if len(redactPatterns) > 0 {
+		logs = util.RedactLogs(logs, redactPatterns)
+	}
+
+	return logs
+}
+
+// logsSection labels logs with container name
+func logsSection(containerName, logs string) string {
+	return "==> " + containerName + " <==\n" + strings.TrimSpace(logs) + "\n\n"
 }
@@ -1,7 +1,6 @@
 package filter
 
 import (
-	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/slices"
 )
 
@@ -11,11 +10,18 @@ func (f ContainerNameFilter) Execute(ctx *Context) bool {
 	container := ctx.Container.Container
 	if len(ctx.Config.IgnoreContainerNames) > 0 &&
 		slices.Contains(ctx.Config.IgnoreContainerNames, container.Name) {
-		logrus.Infof(
-			"skipping container %s as it is in the container ignore list",
-			container.Name)
+		ctx.Logger().Info(
+			"skipping container as it is in the container ignore list")
 		return true
 	}
 
+	for _, pattern := range ctx.Config.IgnoreContainerNamePatterns {
+		if pattern.MatchString(container.Name) {
+			ctx.Logger().Info(
+				"skipping container as it matches the container ignore list")
+			return true
+		}
+	}
+
 	return false
 }
@@ -1,7 +1,6 @@
 package filter
 
 import (
-	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/slices"
 )
 
@@ -35,17 +34,17 @@ func (f ContainerReasonsFilter) Execute(ctx *Context) bool {
 
 	if len(ctx.Config.AllowedReasons) > 0 &&
 		!slices.Contains(ctx.Config.AllowedReasons, ctx.Container.Reason) {
-		logrus.Infof(
-			"skipping reason %s as it is not in the reason allow list",
-			ctx.Container.Reason)
+		ctx.Logger().
+			WithField("reason", ctx.Container.Reason).
+			Info("skipping reason as it is not in the reason allow list")
 		return true
 	}
 
 	if len(ctx.Config.ForbiddenReasons) > 0 &&
 		slices.Contains(ctx.Config.ForbiddenReasons, ctx.Container.Reason) {
-		logrus.Infof(
-			"skipping reason %s as it is in the reason forbid list",
-			ctx.Container.Reason)
+		ctx.Logger().
+			WithField("reason", ctx.Container.Reason).
+			Info("skipping reason as it is in the reason forbid list")
 		return true
 	}
 
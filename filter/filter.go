@@ -4,7 +4,10 @@ import (
 	"time"
 
 	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/informer"
 	"github.com/abahmed/kwatch/storage"
+	"github.com/abahmed/kwatch/util"
+	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	apiv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -19,9 +22,10 @@ type FilterResult struct {
 }
 
 type Context struct {
-	Client kubernetes.Interface
-	Config *config.Config
-	Memory storage.Storage
+	Client   kubernetes.Interface
+	Informer *informer.Informer
+	Config   *config.Config
+	Memory   storage.Storage
 
 	Pod    *corev1.Pod
 	EvType string
@@ -40,12 +44,51 @@ type Context struct {
 
 type ContainerContext struct {
 	Container        *corev1.ContainerStatus
+	Init             bool
 	Reason           string
 	Msg              string
 	ExitCode         int32
 	Logs             string
+	FullLogs         string
+	RawLogs          string
 	HasRestarts      bool
 	LastTerminatedOn time.Time
 	State            string
 	Status           string
 }
+
+// Logger returns logger with structured fields of pod and container in context
+func (ctx *Context) Logger() *logrus.Entry {
+	fields := logrus.Fields{}
+	if ctx.Pod != nil {
+		fields["namespace"] = ctx.Pod.Namespace
+		fields["pod"] = ctx.Pod.Name
+	}
+
+	if ctx.Container != nil && ctx.Container.Container != nil {
+		fields["container"] = ctx.Container.Container.Name
+	}
+
+	return logrus.WithFields(fields)
+}
+
+// GetPodEvents returns events of pod in context from informer cache if
+// available, otherwise from API server
+func (ctx *Context) GetPodEvents() *[]corev1.Event {
+	if ctx.Informer != nil {
+		events, err := ctx.Informer.GetPodEvents(
+			ctx.Pod.Namespace,
+			ctx.Pod.Name)
+		if err != nil {
+			ctx.Logger().WithError(err).Warn("failed to get pod events")
+		}
+		return &events
+	}
+
+	events, err := util.GetPodEvents(ctx.Client, ctx.Pod.Name, ctx.Pod.Namespace)
+	if err != nil {
+		ctx.Logger().WithError(err).Warn("failed to get pod events")
+		return &[]corev1.Event{}
+	}
+	return &events.Items
+}
@@ -0,0 +1,26 @@
+package filter
+
+import "path/filepath"
+
+type InitContainerFilter struct{}
+
+func (f InitContainerFilter) Execute(ctx *Context) bool {
+	if !ctx.Container.Init {
+		return false
+	}
+
+	if ctx.Config.IgnoreInitContainers {
+		ctx.Logger().Info("skipping init container as init containers are ignored")
+		return true
+	}
+
+	for _, pattern := range ctx.Config.IgnoreInitContainersNamespaces {
+		if ok, _ := filepath.Match(pattern, ctx.Pod.Namespace); ok {
+			ctx.Logger().Info(
+				"skipping init container as they are ignored in namespace")
+			return true
+		}
+	}
+
+	return false
+}
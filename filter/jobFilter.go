@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"context"
+	"path/filepath"
+
+	apiv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type JobFilter struct{}
+
+func (f JobFilter) Execute(ctx *Context) bool {
+	ignoreJobs := &ctx.Config.IgnoreJobs
+	if !ignoreJobs.Enabled() {
+		return false
+	}
+
+	owner := apiv1.GetControllerOf(ctx.Pod)
+	if owner == nil || owner.Kind != "Job" {
+		return false
+	}
+
+	// pods have labels of job template, they're used if job is gone
+	names := []string{owner.Name}
+	jobLabels := labels.Set(ctx.Pod.Labels)
+
+	job, err := ctx.Client.BatchV1().Jobs(ctx.Pod.Namespace).Get(
+		context.TODO(),
+		owner.Name,
+		apiv1.GetOptions{})
+	if err != nil {
+		ctx.Logger().WithError(err).Debug("failed to get job of pod")
+	} else {
+		jobLabels = labels.Set(job.Labels)
+		if cronJob := apiv1.GetControllerOf(job); cronJob != nil &&
+			cronJob.Kind == "CronJob" {
+			names = append(names, cronJob.Name)
+		}
+	}
+
+	for _, pattern := range ignoreJobs.Names {
+		for _, name := range names {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				ctx.Logger().Info("skipping pod as its job is ignored")
+				return true
+			}
+		}
+	}
+
+	if ignoreJobs.LabelSelector != nil &&
+		ignoreJobs.LabelSelector.Matches(jobLabels) {
+		ctx.Logger().Info("skipping pod as its job matches ignored selector")
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,8 @@
+package filter
+
+type NamespaceShardFilter struct{}
+
+func (f NamespaceShardFilter) Execute(ctx *Context) bool {
+	// namespaces of other shards are handled by other replicas
+	return !ctx.Config.Sharding.Owns(ctx.Pod.Namespace)
+}
@@ -0,0 +1,19 @@
+package filter
+
+import "k8s.io/apimachinery/pkg/labels"
+
+type PodSelectorFilter struct{}
+
+func (f PodSelectorFilter) Execute(ctx *Context) bool {
+	selector := ctx.Config.PodLabelSelector
+	if selector == nil || selector.Empty() {
+		return false
+	}
+
+	if !selector.Matches(labels.Set(ctx.Pod.Labels)) {
+		ctx.Logger().Debug("skipping pod as it doesn't match pod selector")
+		return true
+	}
+
+	return false
+}
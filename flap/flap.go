@@ -0,0 +1,90 @@
+package flap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// Suppressor suppresses failures until they occur configured number of
+// times within window
+type Suppressor struct {
+	config *config.FlapSuppression
+
+	mu sync.Mutex
+
+	// occurrences are times of recent failures by fingerprint
+	occurrences map[string][]time.Time
+
+	// lastPrune is time stale fingerprints were last removed
+	lastPrune time.Time
+
+	// now returns current time, it's replaced in tests
+	now func() time.Time
+}
+
+// NewSuppressor returns new instance of flap suppressor
+func NewSuppressor(config *config.FlapSuppression) *Suppressor {
+	return &Suppressor{
+		config:      config,
+		occurrences: make(map[string][]time.Time),
+		lastPrune:   time.Now(),
+		now:         time.Now,
+	}
+}
+
+// Enabled returns true if flap suppression is enabled
+func (s *Suppressor) Enabled() bool {
+	return s != nil && s.config.Enabled
+}
+
+// Allow records failure of fingerprint, and returns true if it occurred
+// enough times within window to be reported
+func (s *Suppressor) Allow(fingerprint string) bool {
+	if !s.Enabled() {
+		return true
+	}
+
+	now := s.now()
+	since := now.Add(-time.Duration(s.config.Window) * time.Minute)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	times := recent(s.occurrences[fingerprint], since)
+	times = append(times, now)
+
+	// only last occurrences are needed to reach threshold
+	if len(times) > s.config.Occurrences {
+		times = times[len(times)-s.config.Occurrences:]
+	}
+	s.occurrences[fingerprint] = times
+
+	if now.Sub(s.lastPrune) > time.Duration(s.config.Window)*time.Minute {
+		s.prune(since)
+		s.lastPrune = now
+	}
+
+	return len(times) >= s.config.Occurrences
+}
+
+// prune removes fingerprints without failures since given time
+func (s *Suppressor) prune(since time.Time) {
+	for fingerprint, times := range s.occurrences {
+		if len(recent(times, since)) == 0 {
+			delete(s.occurrences, fingerprint)
+		}
+	}
+}
+
+// recent returns times after since
+func recent(times []time.Time, since time.Time) []time.Time {
+	result := make([]time.Time, 0, len(times)+1)
+	for _, t := range times {
+		if t.After(since) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package flap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllow(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	s := NewSuppressor(&config.FlapSuppression{
+		Enabled:     true,
+		Occurrences: 3,
+		Window:      10,
+	})
+	s.now = func() time.Time { return now }
+
+	assert.False(s.Allow("default/Deployment/api/Error"))
+	now = now.Add(4 * time.Minute)
+	assert.False(s.Allow("default/Deployment/api/Error"))
+	assert.False(s.Allow("default/Deployment/db/Error"))
+	now = now.Add(4 * time.Minute)
+	assert.True(s.Allow("default/Deployment/api/Error"))
+	now = now.Add(time.Minute)
+	assert.True(s.Allow("default/Deployment/api/Error"))
+
+	// earlier occurrences are out of window
+	now = now.Add(11 * time.Minute)
+	assert.False(s.Allow("default/Deployment/api/Error"))
+	assert.Len(s.occurrences, 1)
+	assert.Len(s.occurrences["default/Deployment/api/Error"], 1)
+}
+
+func TestAllowDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSuppressor(&config.FlapSuppression{Occurrences: 3, Window: 10})
+	assert.True(s.Allow("default/Deployment/api/Error"))
+
+	var nilSuppressor *Suppressor
+	assert.True(nilSuppressor.Allow("default/Deployment/api/Error"))
+}
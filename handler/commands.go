@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getKubectlCommands returns ready-to-copy kubectl commands to troubleshoot
+// the failing pod, if it's enabled in config
+func (h *handler) getKubectlCommands(
+	pod *corev1.Pod,
+	container *corev1.ContainerStatus) []string {
+	if !h.config.IncludeKubectlCommands {
+		return nil
+	}
+
+	commands := []string{
+		fmt.Sprintf("kubectl describe pod %s -n %s", pod.Name, pod.Namespace),
+		fmt.Sprintf(
+			"kubectl get events -n %s --field-selector involvedObject.name=%s",
+			pod.Namespace,
+			pod.Name),
+	}
+
+	if container == nil {
+		return commands
+	}
+
+	logsCommand := fmt.Sprintf(
+		"kubectl logs %s -n %s -c %s",
+		pod.Name,
+		pod.Namespace,
+		container.Name)
+	if container.RestartCount > 0 {
+		commands = append(commands, logsCommand+" -p")
+	}
+
+	return append(
+		commands,
+		logsCommand,
+		fmt.Sprintf(
+			"kubectl exec -it %s -n %s -c %s -- sh",
+			pod.Name,
+			pod.Namespace,
+			container.Name))
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/filter"
+	"github.com/abahmed/kwatch/silence"
+	"github.com/abahmed/kwatch/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getEventDetails returns extra context about the failure to be shown in
+// messages
+func (h *handler) getEventDetails(ctx *filter.Context) []event.Field {
+	details := make([]event.Field, 0)
+
+	if workload := h.getWorkload(ctx); len(workload) > 0 {
+		details = append(details, event.Field{
+			Name:  "Workload",
+			Value: workload,
+		})
+	}
+
+	details = append(details, h.getNodeDetails(ctx)...)
+
+	if ctx.Container != nil && ctx.Container.ExitCode != 0 {
+		details = append(details, event.Field{
+			Name:  "Exit Code",
+			Value: util.DescribeExitCode(ctx.Container.ExitCode),
+		})
+	}
+
+	if ctx.Container != nil {
+		details = append(details, h.getContainerResources(ctx)...)
+	}
+
+	if ctx.Container != nil {
+		details = append(details, h.getCrashDetails(ctx)...)
+	}
+
+	if stats := h.getFailureStats(ctx); len(stats) > 0 {
+		details = append(details, event.Field{
+			Name:  "Failure Stats",
+			Value: stats,
+		})
+	}
+
+	details = append(details, h.getCustomFields()...)
+
+	return details
+}
+
+// getFailureStats returns when failure in context was first seen, how many
+// times it occurred and when it was last alerted
+func (h *handler) getFailureStats(ctx *filter.Context) string {
+	reason := ctx.PodReason
+	if ctx.Container != nil {
+		reason = ctx.Container.Reason
+	}
+	return h.failureStats.Describe(getFlapFingerprint(ctx, reason))
+}
+
+// getCrashDetails returns log fingerprint of crashed container, and how
+// many times it was seen if it recurred
+func (h *handler) getCrashDetails(ctx *filter.Context) []event.Field {
+	if !h.crashGrouper.Enabled() {
+		return nil
+	}
+
+	fingerprint, key := getCrashKey(ctx)
+	if len(fingerprint) == 0 {
+		return nil
+	}
+
+	value := fingerprint
+	if seen := h.crashGrouper.Seen(key); seen.Occurrences > 1 {
+		value += " (" + seen.String() + ")"
+	}
+	return []event.Field{{Name: "Crash Fingerprint", Value: value}}
+}
+
+// getCustomFields returns configured static fields sorted by name
+func (h *handler) getCustomFields() []event.Field {
+	names := make([]string, 0, len(h.config.CustomFields))
+	for name := range h.config.CustomFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]event.Field, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, event.Field{
+			Name:  name,
+			Value: h.config.CustomFields[name],
+		})
+	}
+
+	return fields
+}
+
+// getNodeDetails returns node name, topology and conditions of the node the
+// pod is scheduled on, so failures correlated with a bad node stand out
+func (h *handler) getNodeDetails(ctx *filter.Context) []event.Field {
+	nodeName := ctx.Pod.Spec.NodeName
+	if len(nodeName) == 0 {
+		return nil
+	}
+
+	node, err := ctx.Client.CoreV1().
+		Nodes().
+		Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		ctx.Logger().
+			WithField("node", nodeName).
+			WithError(err).
+			Warn("failed to get node")
+		return []event.Field{{Name: "Node", Value: nodeName}}
+	}
+
+	topology := make([]string, 0, 2)
+	if zone := util.GetNodeZone(node); len(zone) > 0 {
+		topology = append(topology, "zone: "+zone)
+	}
+	if region := util.GetNodeRegion(node); len(region) > 0 {
+		topology = append(topology, "region: "+region)
+	}
+
+	nodeValue := nodeName
+	if len(topology) > 0 {
+		nodeValue += " (" + strings.Join(topology, ", ") + ")"
+	}
+
+	fields := []event.Field{{Name: "Node", Value: nodeValue}}
+	conditions := util.FormatNodeConditions(node.Status.Conditions)
+	if len(conditions) > 0 {
+		fields = append(fields, event.Field{
+			Name:  "Node Conditions",
+			Value: conditions,
+		})
+	}
+
+	return fields
+}
+
+// getWorkloadKey returns owning workload of the pod e.g. Deployment/api, or
+// the pod itself if it has no owner
+func getWorkloadKey(ctx *filter.Context) string {
+	if ctx.Owner == nil {
+		return "Pod/" + ctx.Pod.Name
+	}
+	return ctx.Owner.Kind + "/" + ctx.Owner.Name
+}
+
+// getSilenceTarget returns failing pod or container in context with given
+// reason to be checked against silences
+func getSilenceTarget(ctx *filter.Context, reason string) *silence.Target {
+	return &silence.Target{
+		Namespace: ctx.Pod.Namespace,
+		Workload:  getWorkloadKey(ctx),
+		Reason:    reason,
+		Labels:    ctx.Pod.Labels,
+	}
+}
+
+// getWorkload returns owning workload of the pod with how many of its
+// replicas are ready e.g. Deployment/api (2/3 ready)
+func (h *handler) getWorkload(ctx *filter.Context) string {
+	if ctx.Owner == nil {
+		return ""
+	}
+
+	workload := getWorkloadKey(ctx)
+	ready, desired, err := util.GetWorkloadReplicas(
+		ctx.Client,
+		ctx.Pod.Namespace,
+		ctx.Owner.Kind,
+		ctx.Owner.Name)
+	if err != nil {
+		return workload
+	}
+
+	return fmt.Sprintf("%s (%d/%d ready)", workload, ready, desired)
+}
+
+// getContainerResources returns cpu/memory requests and limits of the
+// failing container along with its current usage from metrics API
+func (h *handler) getContainerResources(ctx *filter.Context) []event.Field {
+	containerName := ctx.Container.Container.Name
+	fields := make([]event.Field, 0, 3)
+
+	containers := ctx.Pod.Spec.Containers
+	if ctx.Container.Init {
+		containers = ctx.Pod.Spec.InitContainers
+	}
+
+	for _, container := range containers {
+		if container.Name != containerName {
+			continue
+		}
+
+		requests := util.FormatResources(container.Resources.Requests)
+		if len(requests) > 0 {
+			fields = append(fields, event.Field{
+				Name:  "Requests",
+				Value: requests,
+			})
+		}
+
+		limits := util.FormatResources(container.Resources.Limits)
+		if len(limits) > 0 {
+			fields = append(fields, event.Field{
+				Name:  "Limits",
+				Value: limits,
+			})
+		}
+		break
+	}
+
+	metrics, err := util.GetPodMetrics(
+		ctx.Client,
+		ctx.Pod.Name,
+		ctx.Pod.Namespace)
+	if err != nil {
+		ctx.Logger().WithError(err).Debug("failed to get metrics of pod")
+		return fields
+	}
+
+	for _, container := range metrics.Containers {
+		if container.Name != containerName {
+			continue
+		}
+
+		usage := util.FormatResources(container.Usage)
+		if len(usage) > 0 {
+			fields = append(fields, event.Field{
+				Name:  "Usage",
+				Value: usage,
+			})
+		}
+		break
+	}
+
+	return fields
+}
@@ -8,66 +8,120 @@ import (
 	"github.com/abahmed/kwatch/storage"
 	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func (h *handler) executeContainersFilters(ctx *filter.Context) {
+	for cIdx := range ctx.Pod.Status.InitContainerStatuses {
+		h.executeContainerFilters(
+			ctx,
+			&ctx.Pod.Status.InitContainerStatuses[cIdx],
+			true)
+	}
+
 	for cIdx := range ctx.Pod.Status.ContainerStatuses {
-		ctx.Container = &filter.ContainerContext{
-			Container:        &ctx.Pod.Status.ContainerStatuses[cIdx],
-			HasRestarts:      false,
-			LastTerminatedOn: time.Time{},
-		}
+		h.executeContainerFilters(
+			ctx,
+			&ctx.Pod.Status.ContainerStatuses[cIdx],
+			false)
+	}
+}
+
+// executeContainerFilters executes container filters on status of container,
+// init is true if it's an init container
+func (h *handler) executeContainerFilters(
+	ctx *filter.Context,
+	status *corev1.ContainerStatus,
+	init bool) {
+	ctx.Container = &filter.ContainerContext{
+		Container:        status,
+		Init:             init,
+		HasRestarts:      false,
+		LastTerminatedOn: time.Time{},
+	}
 
-		isContainerOk := false
-		for i := range h.containerFilters {
-			if shouldStop := h.containerFilters[i].Execute(ctx); shouldStop {
-				isContainerOk = true
-				break
-			}
+	isContainerOk := false
+	for i := range h.containerFilters {
+		if shouldStop := h.containerFilters[i].Execute(ctx); shouldStop {
+			recordFilterDrop(h.containerFilters[i])
+			isContainerOk = true
+			break
 		}
+	}
 
-		ctx.Memory.AddPodContainer(
-			ctx.Pod.Namespace,
-			ctx.Pod.Name,
-			ctx.Container.Container.Name,
-			&storage.ContainerState{
-				RestartCount:     ctx.Container.Container.RestartCount,
-				LastTerminatedOn: ctx.Container.LastTerminatedOn,
-				Reason:           ctx.Container.Reason,
-				Msg:              ctx.Container.Msg,
-				ExitCode:         ctx.Container.ExitCode,
-				Status:           ctx.Container.Status,
-			})
+	ctx.Memory.AddPodContainer(
+		ctx.Pod.Namespace,
+		ctx.Pod.Name,
+		ctx.Container.Container.Name,
+		&storage.ContainerState{
+			PodUID:           string(ctx.Pod.UID),
+			RestartCount:     ctx.Container.Container.RestartCount,
+			LastTerminatedOn: ctx.Container.LastTerminatedOn,
+			Reason:           ctx.Container.Reason,
+			Msg:              ctx.Container.Msg,
+			ExitCode:         ctx.Container.ExitCode,
+			Status:           ctx.Container.Status,
+		})
 
-		if !isContainerOk {
-			ownerName := ""
-			if ctx.Owner != nil {
-				ownerName = ctx.Owner.Name
-			}
+	if !isContainerOk {
+		h.failureStats.Observe(getFlapFingerprint(ctx, ctx.Container.Reason))
+	}
+
+	if !isContainerOk &&
+		(h.isSuppressed(ctx, ctx.Container.Reason) ||
+			h.isDuplicateCrash(ctx)) {
+		h.observeFailure(&event.Event{
+			Cluster:       h.config.App.ClusterName,
+			PodName:       ctx.Pod.Name,
+			ContainerName: ctx.Container.Container.Name,
+			Namespace:     ctx.Pod.Namespace,
+			Reason:        ctx.Container.Reason,
+			Logs:          ctx.Container.Logs,
+			Labels:        ctx.Pod.Labels,
+		}, true)
+		return
+	}
+
+	if !isContainerOk {
+		ownerName := ""
+		if ctx.Owner != nil {
+			ownerName = ctx.Owner.Name
+		}
+
+		if ctx.Events == nil {
+			ctx.Events = ctx.GetPodEvents()
+		}
 
-			if ctx.Events == nil {
-				events, _ := util.GetPodEvents(ctx.Client, ctx.Pod.Name, ctx.Pod.Namespace)
-				ctx.Events = &events.Items
-			}
+		ctx.Logger().WithFields(logrus.Fields{
+			"owner":    ownerName,
+			"reason":   ctx.Container.Reason,
+			"message":  ctx.Container.Msg,
+			"exitCode": ctx.Container.ExitCode,
+		}).Info("container only issue")
 
-			logrus.Printf(
-				"container only issue %s %s %s %s %s %d",
-				ctx.Container.Container.Name,
-				ctx.Pod.Name,
-				ownerName,
-				ctx.Container.Reason,
-				ctx.Container.Msg,
-				ctx.Container.ExitCode)
+		containerName := ctx.Container.Container.Name
+		events := util.GetRecentPodEventsTable(
+			ctx.Events,
+			h.config.MaxRecentEvents)
 
-			h.alertManager.NotifyEvent(event.Event{
-				PodName:       ctx.Pod.Name,
-				ContainerName: ctx.Container.Container.Name,
-				Namespace:     ctx.Pod.Namespace,
-				Reason:        ctx.Container.Reason,
-				Events:        util.GetPodEventsStr(ctx.Events),
-				Logs:          ctx.Container.Logs,
-				Labels:        ctx.Pod.Labels,
-			})
+		ev := event.Event{
+			Cluster:       h.config.App.ClusterName,
+			PodName:       ctx.Pod.Name,
+			ContainerName: containerName,
+			Namespace:     ctx.Pod.Namespace,
+			Reason:        ctx.Container.Reason,
+			Events:        events,
+			Logs:          ctx.Container.Logs,
+			FullLogs:      ctx.Container.FullLogs,
+			Labels:        ctx.Pod.Labels,
+			Details:       h.getEventDetails(ctx),
+			PodMetadata:   h.getPodMetadata(ctx.Pod),
+			Links:         h.getLinks(ctx, containerName, ctx.Container.Reason),
+			Commands: h.getKubectlCommands(
+				ctx.Pod,
+				ctx.Container.Container),
 		}
+
+		h.notify(ctx, &ev, ctx.Container.Reason)
 	}
 }
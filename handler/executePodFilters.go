@@ -12,6 +12,7 @@ func (h *handler) executePodFilters(ctx *filter.Context) {
 	isPodOk := false
 	for i := range h.podFilters {
 		if shouldStop := h.podFilters[i].Execute(ctx); shouldStop {
+			recordFilterDrop(h.podFilters[i])
 			isPodOk = true
 			break
 		}
@@ -33,21 +34,48 @@ func (h *handler) executePodFilters(ctx *filter.Context) {
 		ctx.Pod.Name,
 		".",
 		&storage.ContainerState{
+			PodUID: string(ctx.Pod.UID),
 			Reason: ctx.PodReason,
 			Msg:    ctx.PodMsg,
 			Status: "",
 		},
 	)
 
-	logrus.Printf("pod only issue %s %s %s %s", ctx.Pod.Name, ownerName, ctx.PodReason, ctx.PodMsg)
+	h.failureStats.Observe(getFlapFingerprint(ctx, ctx.PodReason))
 
-	h.alertManager.NotifyEvent(event.Event{
+	if h.isSuppressed(ctx, ctx.PodReason) {
+		h.observeFailure(&event.Event{
+			Cluster:   h.config.App.ClusterName,
+			PodName:   ctx.Pod.Name,
+			Namespace: ctx.Pod.Namespace,
+			Reason:    ctx.PodReason,
+			Labels:    ctx.Pod.Labels,
+		}, true)
+		return
+	}
+
+	ctx.Logger().WithFields(logrus.Fields{
+		"owner":   ownerName,
+		"reason":  ctx.PodReason,
+		"message": ctx.PodMsg,
+	}).Info("pod only issue")
+
+	events := util.GetRecentPodEventsTable(ctx.Events, h.config.MaxRecentEvents)
+
+	ev := event.Event{
+		Cluster:       h.config.App.ClusterName,
 		PodName:       ctx.Pod.Name,
 		ContainerName: "",
 		Namespace:     ctx.Pod.Namespace,
 		Reason:        ctx.PodReason,
-		Events:        util.GetPodEventsStr(ctx.Events),
+		Events:        events,
 		Logs:          "",
 		Labels:        ctx.Pod.Labels,
-	})
+		Details:       h.getEventDetails(ctx),
+		PodMetadata:   h.getPodMetadata(ctx.Pod),
+		Links:         h.getLinks(ctx, "", ctx.PodReason),
+		Commands:      h.getKubectlCommands(ctx.Pod, nil),
+	}
+
+	h.notify(ctx, &ev, ctx.PodReason)
 }
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/abahmed/kwatch/filter"
+	"github.com/abahmed/kwatch/reminder"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getRestartCount returns restart count of failing container in context
+func getRestartCount(ctx *filter.Context) int32 {
+	if ctx.Container == nil {
+		return 0
+	}
+	return ctx.Container.Container.RestartCount
+}
+
+// getResolvedCheck returns function reporting whether failure with reason
+// in context is resolved
+func (h *handler) getResolvedCheck(
+	ctx *filter.Context,
+	reason string) func() bool {
+	status := h.getFailureStatus(ctx, reason)
+	return func() bool {
+		return status().Resolved
+	}
+}
+
+// getFailureStatus returns function reporting current status of failure
+// with reason in context, it's resolved if it's silenced, pod is gone or
+// replaced, or pod is ready and failing container didn't restart since
+func (h *handler) getFailureStatus(
+	ctx *filter.Context,
+	reason string) func() *reminder.Status {
+	target := getSilenceTarget(ctx, reason)
+	namespace := ctx.Pod.Namespace
+	name := ctx.Pod.Name
+	uid := ctx.Pod.UID
+	restartCount := getRestartCount(ctx)
+
+	containerName := ""
+	if ctx.Container != nil {
+		containerName = ctx.Container.Container.Name
+	}
+
+	return func() *reminder.Status {
+		if h.silencer.IsTargetSilenced(target) {
+			return &reminder.Status{Resolved: true}
+		}
+
+		pod, err := h.kclient.CoreV1().
+			Pods(namespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return &reminder.Status{Resolved: true}
+		} else if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"namespace": namespace,
+				"pod":       name,
+			}).WithError(err).Warn("failed to get pod of alert")
+			return &reminder.Status{Restarts: restartCount}
+		}
+
+		if pod.UID != uid {
+			return &reminder.Status{Resolved: true}
+		}
+
+		status := &reminder.Status{Restarts: restartCount}
+		for _, statuses := range [][]corev1.ContainerStatus{
+			pod.Status.InitContainerStatuses,
+			pod.Status.ContainerStatuses,
+		} {
+			for _, s := range statuses {
+				if s.Name == containerName {
+					status.Restarts = s.RestartCount
+				}
+			}
+		}
+
+		status.Resolved = status.Restarts <= restartCount && isPodReady(pod)
+		return status
+	}
+}
+
+// isPodReady returns true if pod has ready condition
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -2,9 +2,22 @@ package handler
 
 import (
 	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/archive"
 	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/correlation"
+	"github.com/abahmed/kwatch/crashgroup"
+	"github.com/abahmed/kwatch/digest"
+	"github.com/abahmed/kwatch/escalation"
+	"github.com/abahmed/kwatch/export"
+	"github.com/abahmed/kwatch/failurestats"
 	"github.com/abahmed/kwatch/filter"
+	"github.com/abahmed/kwatch/flap"
+	"github.com/abahmed/kwatch/history"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/abahmed/kwatch/reminder"
+	"github.com/abahmed/kwatch/silence"
 	"github.com/abahmed/kwatch/storage"
+	"github.com/abahmed/kwatch/summarizer"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -15,44 +28,86 @@ type Handler interface {
 
 type handler struct {
 	kclient          kubernetes.Interface
+	informer         *informer.Informer
 	config           *config.Config
 	memory           storage.Storage
 	podFilters       []filter.Filter
 	containerFilters []filter.Filter
 	alertManager     *alertmanager.AlertManager
+	summarizer       *summarizer.Summarizer
+	silencer         *silence.Silencer
+	history          *history.History
+	exporter         *export.Exporter
+	archiver         *archive.Archiver
+	digest           *digest.Digest
+	escalator        *escalation.Escalator
+	reminder         *reminder.Reminder
+	flapSuppressor   *flap.Suppressor
+	crashGrouper     *crashgroup.Grouper
+	correlator       *correlation.Correlator
+	failureStats     *failurestats.Tracker
 }
 
 func NewHandler(
 	cli kubernetes.Interface,
+	inf *informer.Informer,
 	cfg *config.Config,
 	mem storage.Storage,
-	alertManager *alertmanager.AlertManager) Handler {
+	alertManager *alertmanager.AlertManager,
+	silencer *silence.Silencer,
+	alertHistory *history.History,
+	exporter *export.Exporter,
+	archiver *archive.Archiver,
+	digest *digest.Digest,
+	escalator *escalation.Escalator,
+	reminder *reminder.Reminder) Handler {
 	// Order is important
 	podFilters := []filter.Filter{
+		filter.NamespaceShardFilter{},
 		filter.NamespaceFilter{},
+		filter.PodSelectorFilter{},
 		filter.PodNameFilter{},
+		filter.JobFilter{},
 		filter.PodStatusFilter{},
 		filter.PodEventsFilter{},
 		filter.PodOwnersFilter{},
 	}
 
 	containersFilters := []filter.Filter{
+		filter.NamespaceShardFilter{},
 		filter.NamespaceFilter{},
+		filter.PodSelectorFilter{},
+		filter.InitContainerFilter{},
 		filter.ContainerNameFilter{},
+		filter.JobFilter{},
 		filter.ContainerRestartsFilter{},
 		filter.ContainerStateFilter{},
 		filter.ContainerKillingFilter{},
 		filter.ContainerReasonsFilter{},
+		filter.ContainerExitCodeFilter{},
 		filter.ContainerLogsFilter{},
 		filter.PodOwnersFilter{},
 	}
 
 	return &handler{
 		kclient:          cli,
+		informer:         inf,
 		config:           cfg,
 		podFilters:       podFilters,
 		containerFilters: containersFilters,
 		memory:           mem,
 		alertManager:     alertManager,
+		summarizer:       summarizer.NewSummarizer(&cfg.Summarizer),
+		silencer:         silencer,
+		history:          alertHistory,
+		exporter:         exporter,
+		archiver:         archiver,
+		digest:           digest,
+		escalator:        escalator,
+		reminder:         reminder,
+		flapSuppressor:   flap.NewSuppressor(&cfg.FlapSuppression),
+		crashGrouper:     crashgroup.NewGrouper(&cfg.CrashGrouping),
+		correlator:       correlation.NewCorrelator(&cfg.ReplicaCorrelation),
+		failureStats:     failurestats.NewTracker(&cfg.FailureStats),
 	}
 }
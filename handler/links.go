@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/filter"
+)
+
+// linkData holds variables available in link templates
+type linkData struct {
+	Cluster   string
+	Namespace string
+	Pod       string
+	Container string
+	Node      string
+	Reason    string
+}
+
+// getLinks renders configured link templates for the failing pod, links
+// failed to render are skipped
+func (h *handler) getLinks(
+	ctx *filter.Context,
+	containerName string,
+	reason string) []event.Field {
+	data := linkData{
+		Cluster:   h.config.App.ClusterName,
+		Namespace: ctx.Pod.Namespace,
+		Pod:       ctx.Pod.Name,
+		Container: containerName,
+		Node:      ctx.Pod.Spec.NodeName,
+		Reason:    reason,
+	}
+
+	links := make([]event.Field, 0, len(h.config.Links))
+	for _, link := range h.config.Links {
+		if link.Template == nil {
+			continue
+		}
+
+		var url strings.Builder
+		if err := link.Template.Execute(&url, data); err != nil {
+			ctx.Logger().
+				WithField("link", link.Name).
+				WithError(err).
+				Error("failed to render link")
+			continue
+		}
+
+		links = append(links, event.Field{Name: link.Name, Value: url.String()})
+	}
+
+	silenceLink := h.silencer.Link(ctx.Pod.Namespace, getWorkloadKey(ctx))
+	if len(silenceLink) > 0 {
+		links = append(links, event.Field{Name: "Silence", Value: silenceLink})
+	}
+
+	return links
+}
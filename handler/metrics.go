@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/filter"
+	"github.com/abahmed/kwatch/metrics"
+)
+
+// recordFilterDrop records that pod or container was dropped by filter
+func recordFilterDrop(f filter.Filter) {
+	metrics.FilterDrops.WithLabelValues(getFilterName(f)).Inc()
+}
+
+// observeFailure exports failure event and counts it in metrics, silenced
+// failures are observed as well but only alerted ones are archived and
+// counted in digest
+func (h *handler) observeFailure(ev *event.Event, silenced bool) {
+	h.exporter.Export(ev, silenced)
+	if !silenced {
+		h.archiver.Add(ev)
+		h.digest.Add(ev)
+	}
+	metrics.RecordFailure(ev.Namespace, ev.Reason)
+}
+
+// getFilterName returns type name of filter without package prefix
+func getFilterName(f filter.Filter) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", f), "filter.")
+}
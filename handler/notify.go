@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/filter"
+)
+
+// notify sends alert of failure with reason in context, and schedules its
+// escalation and reminders. Failures of replicas of the same workload are
+// sent as one alert if replica correlation is enabled. Summary is generated
+// right before sending, off the watch path
+func (h *handler) notify(ctx *filter.Context, ev *event.Event, reason string) {
+	isResolved := h.getResolvedCheck(ctx, reason)
+	status := h.getFailureStatus(ctx, reason)
+	restartCount := getRestartCount(ctx)
+	key := getFlapFingerprint(ctx, reason)
+
+	send := func(ev *event.Event) {
+		ev.Summary = h.summarizer.Summarize(ev)
+		h.alertManager.NotifyEvent(*ev)
+		h.escalator.Add(ev, isResolved)
+		h.reminder.Add(ev, restartCount, status)
+		h.history.Add(ev)
+		h.failureStats.Alerted(key)
+	}
+
+	// correlated failures are sent later from their own goroutine
+	if ctx.Owner != nil && h.correlator.Add(key, ev, send) {
+		h.observeFailure(ev, false)
+		return
+	}
+
+	if !h.summarizer.Enabled() {
+		send(ev)
+		h.observeFailure(ev, false)
+		return
+	}
+
+	// summarizer waits on a third-party endpoint, so event processing
+	// doesn't wait for it
+	go func() {
+		send(ev)
+		h.observeFailure(ev, false)
+	}()
+}
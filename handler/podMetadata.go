@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"github.com/abahmed/kwatch/event"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getPodMetadata returns configured pod labels and annotations to be shown
+// in messages, keys not found in pod are skipped
+func (h *handler) getPodMetadata(pod *corev1.Pod) []event.Field {
+	fields := make([]event.Field, 0)
+	for _, key := range h.config.IncludeLabels {
+		if value, ok := pod.Labels[key]; ok {
+			fields = append(fields, event.Field{Name: key, Value: value})
+		}
+	}
+
+	for _, key := range h.config.IncludeAnnotations {
+		if value, ok := pod.Annotations[key]; ok {
+			fields = append(fields, event.Field{Name: key, Value: value})
+		}
+	}
+
+	return fields
+}
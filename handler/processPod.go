@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/abahmed/kwatch/filter"
+	"github.com/abahmed/kwatch/metrics"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -10,19 +11,53 @@ func (h *handler) ProcessPod(eventType string, pod *corev1.Pod) {
 		return
 	}
 
+	metrics.EventsObserved.WithLabelValues(eventType).Inc()
+
 	if eventType == "DELETED" {
 		h.memory.DelPod(pod.Namespace, pod.Name)
 		return
 	}
 
+	// state of old pod with same name is dropped, e.g. pods of statefulsets
+	if h.isRecreatedPod(pod) {
+		h.memory.DelPod(pod.Namespace, pod.Name)
+	}
+
 	ctx := filter.Context{
-		Client: h.kclient,
-		Config: h.config,
-		Memory: h.memory,
-		Pod:    pod,
-		EvType: eventType,
+		Client:   h.kclient,
+		Informer: h.informer,
+		Config:   h.config,
+		Memory:   h.memory,
+		Pod:      pod,
+		EvType:   eventType,
 	}
 
 	h.executePodFilters(&ctx)
 	h.executeContainersFilters(&ctx)
 }
+
+// isRecreatedPod returns true if stored state of pod belongs to an older pod
+// with same name
+func (h *handler) isRecreatedPod(pod *corev1.Pod) bool {
+	containerKeys := []string{"."}
+	for _, status := range pod.Status.InitContainerStatuses {
+		containerKeys = append(containerKeys, status.Name)
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		containerKeys = append(containerKeys, status.Name)
+	}
+
+	for _, containerKey := range containerKeys {
+		state := h.memory.GetPodContainer(
+			pod.Namespace,
+			pod.Name,
+			containerKey)
+		if state != nil &&
+			len(state.PodUID) > 0 &&
+			state.PodUID != string(pod.UID) {
+			return true
+		}
+	}
+
+	return false
+}
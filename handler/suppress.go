@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"github.com/abahmed/kwatch/crashgroup"
+	"github.com/abahmed/kwatch/filter"
+)
+
+// isSuppressed returns true if issue with reason is silenced, or it hasn't
+// repeated enough times yet to be reported
+func (h *handler) isSuppressed(ctx *filter.Context, reason string) bool {
+	if h.silencer.IsTargetSilenced(getSilenceTarget(ctx, reason)) {
+		ctx.Logger().Info("skipping silenced issue")
+		return true
+	}
+
+	if !h.flapSuppressor.Allow(getFlapFingerprint(ctx, reason)) {
+		ctx.Logger().
+			WithField("reason", reason).
+			Info("skipping issue until it repeats")
+		return true
+	}
+
+	return false
+}
+
+// isDuplicateCrash returns true if crash of workload in context with the
+// same log fingerprint as container in context was already reported within
+// window
+func (h *handler) isDuplicateCrash(ctx *filter.Context) bool {
+	fingerprint, key := getCrashKey(ctx)
+	pod := ctx.Pod.Namespace + "/" + ctx.Pod.Name
+	if !h.crashGrouper.Allow(key, pod) {
+		ctx.Logger().
+			WithField("fingerprint", fingerprint).
+			Info("skipping duplicate crash")
+		return true
+	}
+
+	return false
+}
+
+// getCrashKey returns log fingerprint of crashed container in context, and
+// key crashes are grouped by, so crashes of different workloads or
+// namespaces aren't grouped. Both are empty if logs have no stack trace
+func getCrashKey(ctx *filter.Context) (string, string) {
+	fingerprint := crashgroup.Fingerprint(ctx.Container.RawLogs)
+	if len(fingerprint) == 0 {
+		return "", ""
+	}
+	return fingerprint,
+		getFlapFingerprint(ctx, ctx.Container.Reason) + "/" + fingerprint
+}
+
+// getFlapFingerprint returns fingerprint failures with reason of workload
+// in context are counted by
+func getFlapFingerprint(ctx *filter.Context, reason string) string {
+	return ctx.Pod.Namespace + "/" + getWorkloadKey(ctx) + "/" + reason
+}
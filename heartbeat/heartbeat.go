@@ -0,0 +1,105 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+)
+
+type Heartbeat struct {
+	config *config.Heartbeat
+	client *http.Client
+
+	// healthy returns true if watches of kwatch are healthy
+	healthy func() bool
+}
+
+// NewHeartbeat returns new instance of heartbeat pinging configured URLs
+// while healthy returns true
+func NewHeartbeat(config *config.Heartbeat, healthy func() bool) *Heartbeat {
+	return &Heartbeat{
+		config: config,
+		client: &http.Client{
+			Timeout: time.Duration(config.Timeout) * time.Second,
+		},
+		healthy: healthy,
+	}
+}
+
+// Enabled returns true if heartbeat is enabled
+func (h *Heartbeat) Enabled() bool {
+	return h.config.Enabled && len(h.config.URLs) > 0
+}
+
+// Start pings configured URLs on configured interval until stopCh is closed
+func (h *Heartbeat) Start(stopCh <-chan struct{}) {
+	if !h.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(h.config.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.Beat()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// Beat pings configured URLs if watches are healthy, so external monitors
+// alert once pings stop
+func (h *Heartbeat) Beat() {
+	if !h.healthy() {
+		logrus.Warn("skipping heartbeat as watches aren't healthy")
+		return
+	}
+
+	for _, url := range h.config.URLs {
+		if err := h.ping(url); err != nil {
+			logrus.WithField("url", url).
+				WithError(err).
+				Error("failed to send heartbeat")
+		}
+	}
+}
+
+// ping sends request of configured method to url
+func (h *Heartbeat) ping(url string) error {
+	method := strings.ToUpper(h.config.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(
+		context.TODO(),
+		method,
+		url,
+		nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf(
+			"call to heartbeat URL returned status code %d",
+			response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package heartbeat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeat(t *testing.T) {
+	assert := assert.New(t)
+
+	methods := make([]string, 0)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+		}))
+	defer srv.Close()
+
+	healthy := true
+	h := NewHeartbeat(
+		&config.Heartbeat{
+			Enabled: true,
+			URLs:    []string{srv.URL, srv.URL + "/second"},
+			Method:  "post",
+			Timeout: 5,
+		},
+		func() bool { return healthy })
+	assert.True(h.Enabled())
+
+	h.Beat()
+	assert.Equal([]string{http.MethodPost, http.MethodPost}, methods)
+
+	// pings stop once watches aren't healthy
+	healthy = false
+	h.Beat()
+	assert.Len(methods, 2)
+}
+
+func TestPingFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer srv.Close()
+
+	h := NewHeartbeat(&config.Heartbeat{}, func() bool { return true })
+	assert.False(h.Enabled())
+	assert.NotNil(h.ping(srv.URL))
+}
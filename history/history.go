@@ -0,0 +1,218 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a sent alert kept in history
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container,omitempty"`
+	Reason    string    `json:"reason"`
+	Summary   string    `json:"summary,omitempty"`
+}
+
+// Query filters history entries, zero values match all entries
+type Query struct {
+	Namespace string
+	Reason    string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+const (
+	BackendMemory   = "memory"
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// pruneInterval is interval of removing entries older than retention
+const pruneInterval = time.Hour
+
+type History struct {
+	config *config.History
+	store  Store
+}
+
+// NewHistory returns new instance of history using configured backend, it
+// fails if database backend isn't built in or its database can't be opened
+func NewHistory(config *config.History) (*History, error) {
+	h := &History{config: config}
+	if !config.Enabled {
+		h.store = &memoryStore{config: config, entries: make([]Entry, 0)}
+		return h, nil
+	}
+
+	switch config.Backend {
+	case BackendSQLite, BackendPostgres:
+		store, err := newSQLStore(config.Backend, config.DSN)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to open %s alert history: %w",
+				config.Backend,
+				err)
+		}
+		h.store = store
+		return h, nil
+	}
+
+	store, err := newMemoryStore(config)
+	if err != nil {
+		logrus.WithField("path", config.Path).
+			WithError(err).
+			Warn("failed to load alert history")
+	}
+	h.store = store
+	return h, nil
+}
+
+// Enabled returns true if alert history is enabled
+func (h *History) Enabled() bool {
+	return h.config.Enabled
+}
+
+// Add records event in history
+func (h *History) Add(ev *event.Event) {
+	if !h.config.Enabled {
+		return
+	}
+
+	err := h.store.Add(&Entry{
+		Time:      time.Now(),
+		Namespace: ev.Namespace,
+		Pod:       ev.PodName,
+		Container: ev.ContainerName,
+		Reason:    ev.Reason,
+		Summary:   ev.Summary,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("failed to persist alert history")
+	}
+}
+
+// List returns entries matching query, newest first
+func (h *History) List(q Query) []Entry {
+	entries, err := h.store.List(q)
+	if err != nil {
+		logrus.WithError(err).Error("failed to list alert history")
+		return make([]Entry, 0)
+	}
+	return entries
+}
+
+// Start removes entries older than configured retention periodically until
+// stopCh is closed, then store is closed
+func (h *History) Start(stopCh <-chan struct{}) {
+	if !h.config.Enabled {
+		return
+	}
+	defer h.store.Close()
+
+	if h.config.Retention <= 0 {
+		<-stopCh
+		return
+	}
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		h.prune()
+
+		select {
+		case <-ticker.C:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// prune removes entries older than configured retention
+func (h *History) prune() {
+	retention := time.Duration(h.config.Retention) * 24 * time.Hour
+	if err := h.store.Prune(time.Now().Add(-retention)); err != nil {
+		logrus.WithError(err).Error("failed to prune alert history")
+	}
+}
+
+// RegisterHandlers registers alert history API handlers
+func (h *History) RegisterHandlers(
+	handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle("/api/v1/alerts", h.handleList)
+}
+
+// handleList returns entries matching query params as json
+func (h *History) handleList(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.List(q))
+}
+
+// matches returns true if entry matches query
+func (q *Query) matches(entry *Entry) bool {
+	if len(q.Namespace) > 0 && entry.Namespace != q.Namespace {
+		return false
+	}
+
+	if len(q.Reason) > 0 && entry.Reason != q.Reason {
+		return false
+	}
+
+	if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+		return false
+	}
+
+	if !q.Until.IsZero() && entry.Time.After(q.Until) {
+		return false
+	}
+
+	return true
+}
+
+// parseQuery parses namespace, reason, since, until (RFC3339) and limit
+// query params of request
+func parseQuery(r *http.Request) (Query, error) {
+	params := r.URL.Query()
+	q := Query{
+		Namespace: params.Get("namespace"),
+		Reason:    params.Get("reason"),
+	}
+
+	var err error
+	if since := params.Get("since"); len(since) > 0 {
+		if q.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return q, errors.New("invalid since, expected RFC3339 time")
+		}
+	}
+
+	if until := params.Get("until"); len(until) > 0 {
+		if q.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return q, errors.New("invalid until, expected RFC3339 time")
+		}
+	}
+
+	if limit := params.Get("limit"); len(limit) > 0 {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit < 0 {
+			return q, errors.New("invalid limit")
+		}
+	}
+
+	return q, nil
+}
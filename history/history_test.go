@@ -0,0 +1,159 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	h, err := NewHistory(&config.History{})
+	assert.Nil(err)
+	h.Add(&event.Event{Namespace: "default", PodName: "api"})
+
+	assert.False(h.Enabled())
+	assert.Len(h.List(Query{}), 0)
+}
+
+func TestHistoryList(t *testing.T) {
+	assert := assert.New(t)
+
+	h, err := NewHistory(&config.History{Enabled: true, MaxEntries: 2})
+	assert.Nil(err)
+	h.Add(&event.Event{Namespace: "default", PodName: "a", Reason: "Error"})
+	h.Add(&event.Event{Namespace: "kube", PodName: "b", Reason: "OOMKilled"})
+	h.Add(&event.Event{Namespace: "default", PodName: "c", Reason: "Error"})
+
+	entries := h.List(Query{})
+	assert.Len(entries, 2)
+	assert.Equal("c", entries[0].Pod)
+	assert.Equal("b", entries[1].Pod)
+
+	entries = h.List(Query{Namespace: "default"})
+	assert.Len(entries, 1)
+	assert.Equal("c", entries[0].Pod)
+
+	entries = h.List(Query{Reason: "OOMKilled"})
+	assert.Len(entries, 1)
+	assert.Equal("b", entries[0].Pod)
+
+	assert.Len(h.List(Query{Since: time.Now().Add(time.Hour)}), 0)
+	assert.Len(h.List(Query{Until: time.Now().Add(-time.Hour)}), 0)
+	assert.Len(h.List(Query{Limit: 1}), 1)
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &config.History{
+		Enabled:    true,
+		MaxEntries: 10,
+		Path:       filepath.Join(t.TempDir(), "history.json"),
+	}
+
+	h, err := NewHistory(cfg)
+	assert.Nil(err)
+	h.Add(&event.Event{Namespace: "default", PodName: "api"})
+
+	h, err = NewHistory(cfg)
+	assert.Nil(err)
+	entries := h.List(Query{})
+	assert.Len(entries, 1)
+	assert.Equal("api", entries[0].Pod)
+}
+
+func TestListHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	h, err := NewHistory(&config.History{Enabled: true, MaxEntries: 10})
+	assert.Nil(err)
+	h.Add(&event.Event{Namespace: "default", PodName: "a", Reason: "Error"})
+	h.Add(&event.Event{Namespace: "kube", PodName: "b", Reason: "Error"})
+
+	rr := httptest.NewRecorder()
+	h.handleList(rr, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/alerts?namespace=kube&reason=Error",
+		nil))
+
+	var entries []Entry
+	assert.Nil(json.Unmarshal(rr.Body.Bytes(), &entries))
+	assert.Len(entries, 1)
+	assert.Equal("b", entries[0].Pod)
+
+	rr = httptest.NewRecorder()
+	h.handleList(rr, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/alerts?since=yesterday",
+		nil))
+	assert.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func TestHistoryRetention(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &config.History{Enabled: true, MaxEntries: 10, Retention: 1}
+	h, err := NewHistory(cfg)
+	assert.Nil(err)
+	h.store.Add(&Entry{Time: time.Now().Add(-48 * time.Hour), Pod: "old"})
+	h.Add(&event.Event{Namespace: "default", PodName: "new"})
+
+	h.prune()
+
+	entries := h.List(Query{})
+	assert.Len(entries, 1)
+	assert.Equal("new", entries[0].Pod)
+}
+
+func TestHistoryBackendUnavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	if slices.Contains(sql.Drivers(), dialects[BackendPostgres].driver) {
+		t.Skip("postgres driver is built in")
+	}
+
+	// history isn't silently kept in memory if database can't be used
+	_, err := NewHistory(&config.History{
+		Enabled:    true,
+		MaxEntries: 10,
+		Backend:    BackendPostgres,
+		DSN:        "postgres://localhost:5432/kwatch",
+	})
+	assert.ErrorContains(err, "build kwatch with postgres tag")
+}
+
+func TestListQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	since := time.Unix(100, 0)
+	query, args := dialects[BackendPostgres].listQuery(Query{
+		Namespace: "default",
+		Reason:    "OOMKilled",
+		Since:     since,
+		Limit:     5,
+	})
+	assert.Equal(
+		"SELECT alerted_at, namespace, pod, container, reason, summary "+
+			"FROM kwatch_alerts WHERE namespace = $1 AND reason = $2 AND "+
+			"alerted_at >= $3 ORDER BY alerted_at DESC LIMIT 5",
+		query)
+	assert.Equal([]interface{}{"default", "OOMKilled", since.UnixNano()}, args)
+
+	query, args = dialects[BackendSQLite].listQuery(Query{Reason: "Error"})
+	assert.Equal(
+		"SELECT alerted_at, namespace, pod, container, reason, summary "+
+			"FROM kwatch_alerts WHERE reason = ? ORDER BY alerted_at DESC",
+		query)
+	assert.Equal([]interface{}{"Error"}, args)
+}
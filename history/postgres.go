@@ -0,0 +1,7 @@
+//go:build postgres
+
+package history
+
+// Postgres driver is built in only with postgres build tag, so default
+// builds don't carry a database driver most setups don't use
+import _ "github.com/jackc/pgx/v5/stdlib"
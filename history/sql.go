@@ -0,0 +1,227 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dialect describes differences of supported databases
+type dialect struct {
+	// driver is name of registered database/sql driver
+	driver string
+
+	// placeholder returns placeholder of nth (starting from 1) query arg
+	placeholder func(n int) string
+}
+
+// dialects of supported backends
+var dialects = map[string]*dialect{
+	BackendSQLite: {
+		driver:      "sqlite",
+		placeholder: func(int) string { return "?" },
+	},
+	BackendPostgres: {
+		driver:      "pgx",
+		placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	},
+}
+
+// migrations are applied in order, applied ones are recorded in
+// kwatch_schema_migrations table. Existing migrations must not be changed,
+// new ones are appended
+var migrations = []string{
+	`CREATE TABLE kwatch_alerts (
+		alerted_at BIGINT NOT NULL,
+		namespace TEXT NOT NULL,
+		pod TEXT NOT NULL,
+		container TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		summary TEXT NOT NULL
+	)`,
+	`CREATE INDEX kwatch_alerts_alerted_at ON kwatch_alerts (alerted_at)`,
+}
+
+// sqlStore keeps entries in SQLite or Postgres database
+type sqlStore struct {
+	db      *sql.DB
+	dialect *dialect
+}
+
+// newSQLStore opens database of backend using dsn and migrates its schema
+func newSQLStore(backend, dsn string) (*sqlStore, error) {
+	d, ok := dialects[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown history backend %s", backend)
+	}
+
+	if !slices.Contains(sql.Drivers(), d.driver) {
+		return nil, fmt.Errorf(
+			"%s driver isn't built in, build kwatch with %s tag",
+			backend,
+			backend)
+	}
+
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sqlStore{db: db, dialect: d}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// migrate applies migrations which weren't applied yet
+func (s *sqlStore) migrate() error {
+	_, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS kwatch_schema_migrations (
+		version INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	var version int
+	err = s.db.QueryRow(
+		"SELECT COALESCE(MAX(version), 0) FROM kwatch_schema_migrations").
+		Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+
+		_, err = tx.Exec(
+			"INSERT INTO kwatch_schema_migrations (version) VALUES ("+
+				s.dialect.placeholder(1)+")",
+			i+1)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		logrus.WithField("version", i+1).Info("applied history migration")
+	}
+
+	return nil
+}
+
+// Add stores entry
+func (s *sqlStore) Add(entry *Entry) error {
+	p := s.dialect.placeholder
+	_, err := s.db.Exec(
+		"INSERT INTO kwatch_alerts "+
+			"(alerted_at, namespace, pod, container, reason, summary) "+
+			"VALUES ("+p(1)+", "+p(2)+", "+p(3)+", "+p(4)+", "+p(5)+", "+
+			p(6)+")",
+		entry.Time.UnixNano(),
+		entry.Namespace,
+		entry.Pod,
+		entry.Container,
+		entry.Reason,
+		entry.Summary)
+	return err
+}
+
+// List returns entries matching query, newest first
+func (s *sqlStore) List(q Query) ([]Entry, error) {
+	query, args := s.dialect.listQuery(q)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]Entry, 0)
+	for rows.Next() {
+		var entry Entry
+		var alertedAt int64
+		err := rows.Scan(
+			&alertedAt,
+			&entry.Namespace,
+			&entry.Pod,
+			&entry.Container,
+			&entry.Reason,
+			&entry.Summary)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Time = time.Unix(0, alertedAt)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// Prune removes entries older than given time
+func (s *sqlStore) Prune(before time.Time) error {
+	_, err := s.db.Exec(
+		"DELETE FROM kwatch_alerts WHERE alerted_at < "+
+			s.dialect.placeholder(1),
+		before.UnixNano())
+	return err
+}
+
+// Close closes database
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// listQuery returns select statement and its args of query
+func (d *dialect) listQuery(q Query) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(
+			conditions,
+			condition+" "+d.placeholder(len(args)))
+	}
+
+	if len(q.Namespace) > 0 {
+		add("namespace =", q.Namespace)
+	}
+	if len(q.Reason) > 0 {
+		add("reason =", q.Reason)
+	}
+	if !q.Since.IsZero() {
+		add("alerted_at >=", q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		add("alerted_at <=", q.Until.UnixNano())
+	}
+
+	query := "SELECT alerted_at, namespace, pod, container, reason, summary " +
+		"FROM kwatch_alerts"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY alerted_at DESC"
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+
+	return query, args
+}
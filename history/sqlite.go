@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package history
+
+// SQLite driver is built in only with sqlite build tag, so default builds
+// don't carry a database driver most setups don't use
+import _ "modernc.org/sqlite"
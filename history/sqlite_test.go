@@ -0,0 +1,42 @@
+//go:build sqlite
+
+package history
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &config.History{
+		Enabled:    true,
+		MaxEntries: 10,
+		Backend:    BackendSQLite,
+		DSN:        filepath.Join(t.TempDir(), "kwatch.db"),
+	}
+
+	h, err := NewHistory(cfg)
+	assert.Nil(err)
+	h.Add(&event.Event{Namespace: "default", PodName: "api", Reason: "Error"})
+	h.Add(&event.Event{Namespace: "kube", PodName: "dns", Reason: "OOMKilled"})
+	assert.Nil(h.store.Close())
+
+	// alerts survive restarts, and migrations aren't applied twice
+	h, err = NewHistory(cfg)
+	assert.Nil(err)
+	defer h.store.Close()
+
+	entries := h.List(Query{})
+	assert.Len(entries, 2)
+	assert.Equal("dns", entries[0].Pod)
+
+	entries = h.List(Query{Reason: "Error"})
+	assert.Len(entries, 1)
+	assert.Equal("api", entries[0].Pod)
+}
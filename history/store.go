@@ -0,0 +1,138 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// Store persists history entries
+type Store interface {
+	// Add stores entry
+	Add(entry *Entry) error
+
+	// List returns entries matching query, newest first
+	List(q Query) ([]Entry, error)
+
+	// Prune removes entries older than given time
+	Prune(before time.Time) error
+
+	// Close releases resources of store
+	Close() error
+}
+
+// memoryStore keeps latest entries in memory, optionally persisting them to
+// a file
+type memoryStore struct {
+	config *config.History
+
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// newMemoryStore returns new instance of memory store, loading persisted
+// entries if a path is configured
+func newMemoryStore(config *config.History) (*memoryStore, error) {
+	s := &memoryStore{
+		config:  config,
+		entries: make([]Entry, 0),
+	}
+
+	if len(config.Path) > 0 {
+		if err := s.load(); err != nil {
+			return s, err
+		}
+	}
+
+	return s, nil
+}
+
+// Add stores entry dropping oldest entries over the limit
+func (s *memoryStore) Add(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, *entry)
+	if s.config.MaxEntries > 0 && len(s.entries) > s.config.MaxEntries {
+		s.entries = s.entries[len(s.entries)-s.config.MaxEntries:]
+	}
+
+	return s.save()
+}
+
+// List returns entries matching query, newest first
+func (s *memoryStore) List(q Query) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]Entry, 0)
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if !q.matches(&entry) {
+			continue
+		}
+
+		entries = append(entries, entry)
+		if q.Limit > 0 && len(entries) == q.Limit {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// Prune removes entries older than given time
+func (s *memoryStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := 0
+	for idx < len(s.entries) && s.entries[idx].Time.Before(before) {
+		idx++
+	}
+	if idx == 0 {
+		return nil
+	}
+
+	s.entries = s.entries[idx:]
+	return s.save()
+}
+
+// Close does nothing as entries are saved once they're changed
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+// load reads persisted entries from configured path
+func (s *memoryStore) load() error {
+	data, err := os.ReadFile(s.config.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, &s.entries)
+}
+
+// save writes entries to configured path if it's set
+func (s *memoryStore) save() error {
+	if len(s.config.Path) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.config.Path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.config.Path)
+}
@@ -0,0 +1,146 @@
+package informer
+
+import (
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/util"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// eventsByPodIndex indexes events by namespace and name of involved pod
+const eventsByPodIndex = "byPod"
+
+// Informer holds shared informers of pods, events and persistent volume
+// claims, so lookups are served from local cache instead of API server
+type Informer struct {
+	client  kubernetes.Interface
+	factory informers.SharedInformerFactory
+
+	pods   cache.SharedIndexInformer
+	events cache.SharedIndexInformer
+	pvcs   cache.SharedIndexInformer
+
+	podLister corelisters.PodLister
+	pvcLister corelisters.PersistentVolumeClaimLister
+}
+
+// NewInformer returns new instance of shared informers, they are scoped to
+// allowed namespace if only one is configured
+func NewInformer(client kubernetes.Interface, config *config.Config) *Informer {
+	namespace := metav1.NamespaceAll
+	if len(config.AllowedNamespaces) == 1 {
+		namespace = config.AllowedNamespaces[0]
+	}
+
+	// bookmarks keep resource version of watches up to date, so reconnects
+	// resume from it instead of relisting everything
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		client,
+		0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.AllowWatchBookmarks = true
+		}))
+
+	podInformer := factory.Core().V1().Pods()
+	eventInformer := factory.Core().V1().Events()
+	pvcInformer := factory.Core().V1().PersistentVolumeClaims()
+
+	err := eventInformer.Informer().AddIndexers(cache.Indexers{
+		eventsByPodIndex: indexEventsByPod,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("failed to add events index")
+	}
+
+	return &Informer{
+		client:    client,
+		factory:   factory,
+		pods:      podInformer.Informer(),
+		events:    eventInformer.Informer(),
+		pvcs:      pvcInformer.Informer(),
+		podLister: podInformer.Lister(),
+		pvcLister: pvcInformer.Lister(),
+	}
+}
+
+// Pods returns shared informer of pods, handlers have to be added before
+// informers are started
+func (i *Informer) Pods() cache.SharedIndexInformer {
+	return i.pods
+}
+
+// Events returns shared informer of events, handlers have to be added
+// before informers are started
+func (i *Informer) Events() cache.SharedIndexInformer {
+	return i.events
+}
+
+// Start starts informers and waits for their caches to sync, it returns
+// false if caches failed to sync
+func (i *Informer) Start(stopCh <-chan struct{}) bool {
+	i.factory.Start(stopCh)
+
+	return cache.WaitForCacheSync(
+		stopCh,
+		i.pods.HasSynced,
+		i.events.HasSynced,
+		i.pvcs.HasSynced)
+}
+
+// ListPods returns cached pods in namespace, or in all namespaces if it's
+// empty
+func (i *Informer) ListPods(namespace string) ([]*corev1.Pod, error) {
+	if len(namespace) == 0 {
+		return i.podLister.List(labels.Everything())
+	}
+	return i.podLister.Pods(namespace).List(labels.Everything())
+}
+
+// GetPodEvents returns cached events of pod
+func (i *Informer) GetPodEvents(namespace, name string) ([]corev1.Event, error) {
+	objs, err := i.events.GetIndexer().ByIndex(
+		eventsByPodIndex,
+		namespace+"/"+name)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]corev1.Event, 0, len(objs))
+	for _, obj := range objs {
+		if ev, ok := obj.(*corev1.Event); ok {
+			events = append(events, *ev)
+		}
+	}
+	return events, nil
+}
+
+// GetPVNameFromPVC returns the name of persistent volume of claim, it falls
+// back to API server if claim isn't cached e.g. outside watched namespace
+func (i *Informer) GetPVNameFromPVC(namespace, pvcName string) (string, error) {
+	pvc, err := i.pvcLister.PersistentVolumeClaims(namespace).Get(pvcName)
+	if errors.IsNotFound(err) {
+		return util.GetPVNameFromPVC(i.client, namespace, pvcName)
+	} else if err != nil {
+		return "", err
+	}
+
+	return pvc.Spec.VolumeName, nil
+}
+
+// indexEventsByPod returns index key of pod event is involved with
+func indexEventsByPod(obj interface{}) ([]string, error) {
+	ev, ok := obj.(*corev1.Event)
+	if !ok || ev.InvolvedObject.Kind != "Pod" {
+		return nil, nil
+	}
+
+	return []string{ev.Namespace + "/" + ev.InvolvedObject.Name}, nil
+}
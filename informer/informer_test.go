@@ -0,0 +1,62 @@
+package informer
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInformer(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "e1", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "Pod",
+				Name: "api",
+			},
+			Reason: "BackOff",
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "e2", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "Pod",
+				Name: "web",
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+		},
+	)
+
+	inf := NewInformer(cli, config.DefaultConfig())
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	assert.True(inf.Start(stopCh))
+
+	pods, err := inf.ListPods("")
+	assert.Nil(err)
+	assert.Len(pods, 1)
+
+	events, err := inf.GetPodEvents("default", "api")
+	assert.Nil(err)
+	assert.Len(events, 1)
+	assert.Equal("BackOff", events[0].Reason)
+
+	pvName, err := inf.GetPVNameFromPVC("default", "data")
+	assert.Nil(err)
+	assert.Equal("pv-1", pvName)
+
+	_, err = inf.GetPVNameFromPVC("default", "missing")
+	assert.NotNil(err)
+}
@@ -0,0 +1,118 @@
+package informer
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchResources watches custom resources in namespace, or all namespaces
+// if it's empty, and calls onChange with all of them once synced and on
+// every later change until stopCh is closed. It returns false if resources
+// can't be synced
+func WatchResources(
+	client dynamic.Interface,
+	gvr schema.GroupVersionResource,
+	namespace string,
+	stopCh <-chan struct{},
+	onChange func([]*unstructured.Unstructured)) bool {
+	informer := newResourceInformer(client, gvr, namespace)
+
+	// objects added during initial sync are passed at once after it
+	var synced atomic.Bool
+	notify := func() {
+		items := informer.GetStore().List()
+		objects := make([]*unstructured.Unstructured, 0, len(items))
+		for _, item := range items {
+			if obj, ok := item.(*unstructured.Unstructured); ok {
+				objects = append(objects, obj)
+			}
+		}
+		onChange(objects)
+	}
+	onEvent := func() {
+		if synced.Load() {
+			notify()
+		}
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { onEvent() },
+		UpdateFunc: func(oldObj, newObj interface{}) { onEvent() },
+		DeleteFunc: func(obj interface{}) { onEvent() },
+	})
+
+	go informer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		return false
+	}
+
+	synced.Store(true)
+	notify()
+	return true
+}
+
+// WatchResourceChanges watches custom resources in namespace, or all
+// namespaces if it's empty, and calls onUpdate with key and object of each
+// added or changed object and onDelete with key of each deleted object
+// until stopCh is closed. Keys are namespace and name of objects. It
+// returns false if resources can't be synced
+func WatchResourceChanges(
+	client dynamic.Interface,
+	gvr schema.GroupVersionResource,
+	namespace string,
+	stopCh <-chan struct{},
+	onUpdate func(string, *unstructured.Unstructured),
+	onDelete func(string)) bool {
+	informer := newResourceInformer(client, gvr, namespace)
+
+	update := func(item interface{}) {
+		obj, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			return
+		}
+
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			logrus.WithError(err).Warn("failed to get key of resource")
+			return
+		}
+		onUpdate(key, obj)
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    update,
+		UpdateFunc: func(oldObj, newObj interface{}) { update(newObj) },
+		DeleteFunc: func(obj interface{}) {
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				logrus.WithError(err).Warn("failed to get key of resource")
+				return
+			}
+			onDelete(key)
+		},
+	})
+
+	go informer.Run(stopCh)
+	return cache.WaitForCacheSync(stopCh, informer.HasSynced)
+}
+
+// newResourceInformer returns informer of custom resources in namespace, or
+// all namespaces if it's empty
+func newResourceInformer(
+	client dynamic.Interface,
+	gvr schema.GroupVersionResource,
+	namespace string) cache.SharedIndexInformer {
+	return dynamicinformer.NewFilteredDynamicInformer(
+		client,
+		gvr,
+		namespace,
+		0,
+		cache.Indexers{},
+		nil).Informer()
+}
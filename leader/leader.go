@@ -0,0 +1,130 @@
+package leader
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/metrics"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// serviceAccountNamespaceFile holds namespace of pod when running in cluster
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/" +
+	"serviceaccount/namespace"
+
+type Elector struct {
+	client   kubernetes.Interface
+	config   *config.LeaderElection
+	identity string
+	isLeader atomic.Bool
+}
+
+// NewElector returns new instance of lease based leader elector
+func NewElector(
+	client kubernetes.Interface,
+	config *config.LeaderElection) *Elector {
+	identity := os.Getenv("POD_NAME")
+	if len(identity) == 0 {
+		identity, _ = os.Hostname()
+	}
+
+	return &Elector{
+		client:   client,
+		config:   config,
+		identity: identity,
+	}
+}
+
+// IsLeader returns true if this instance holds the lease or leader election
+// is disabled
+func (e *Elector) IsLeader() bool {
+	return !e.config.Enabled || e.isLeader.Load()
+}
+
+// Run takes part in leader election until context is cancelled, it
+// campaigns again whenever leadership is lost
+func (e *Elector) Run(ctx context.Context) {
+	if !e.config.Enabled {
+		return
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      e.config.LeaseName,
+			Namespace: getNamespace(e.config.Namespace),
+		},
+		Client: e.client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: e.identity,
+		},
+	}
+
+	electionConfig := leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   time.Duration(e.config.LeaseDuration) * time.Second,
+		RenewDeadline:   time.Duration(e.config.RenewDeadline) * time.Second,
+		RetryPeriod:     time.Duration(e.config.RetryPeriod) * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				logrus.WithField("identity", e.identity).
+					Info("started leading, alerts are sent by this instance")
+				e.setLeader(true)
+			},
+			OnStoppedLeading: func() {
+				logrus.WithField("identity", e.identity).Info("stopped leading")
+				e.setLeader(false)
+			},
+			OnNewLeader: func(identity string) {
+				logrus.WithField("identity", identity).Info("new leader elected")
+			},
+		},
+	}
+
+	for ctx.Err() == nil {
+		elector, err := leaderelection.NewLeaderElector(electionConfig)
+		if err != nil {
+			logrus.WithError(err).Error("failed to start leader election")
+			return
+		}
+		elector.Run(ctx)
+	}
+}
+
+func (e *Elector) setLeader(isLeader bool) {
+	e.isLeader.Store(isLeader)
+
+	value := 0.0
+	if isLeader {
+		value = 1
+	}
+	metrics.IsLeader.Set(value)
+}
+
+// getNamespace returns configured namespace, namespace of running pod or
+// default namespace
+func getNamespace(namespace string) string {
+	if len(namespace) > 0 {
+		return namespace
+	}
+
+	if namespace := os.Getenv("POD_NAMESPACE"); len(namespace) > 0 {
+		return namespace
+	}
+
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if namespace := strings.TrimSpace(string(data)); len(namespace) > 0 {
+			return namespace
+		}
+	}
+
+	return metav1.NamespaceDefault
+}
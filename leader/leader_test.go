@@ -0,0 +1,49 @@
+package leader
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestElectorDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewElector(fake.NewSimpleClientset(), &config.LeaderElection{})
+	assert.True(e.IsLeader())
+
+	// returns immediately when disabled
+	e.Run(context.Background())
+}
+
+func TestElectorRun(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.DefaultConfig().LeaderElection
+	cfg.Enabled = true
+	cfg.Namespace = "kwatch"
+
+	e := NewElector(fake.NewSimpleClientset(), &cfg)
+	assert.False(e.IsLeader())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx)
+
+	assert.Eventually(e.IsLeader, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestGetNamespace(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("kwatch", getNamespace("kwatch"))
+
+	os.Setenv("POD_NAMESPACE", "monitoring")
+	defer os.Unsetenv("POD_NAMESPACE")
+	assert.Equal("monitoring", getNamespace(""))
+}
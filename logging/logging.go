@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Setup configures level, format and caller reporting of logs
+func Setup(cfg *config.Logging) {
+	switch cfg.Format {
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}
+
+	logrus.SetReportCaller(cfg.Caller)
+
+	level := logrus.InfoLevel
+	if len(cfg.Level) > 0 {
+		parsed, err := logrus.ParseLevel(cfg.Level)
+		if err != nil {
+			logrus.Warnf(
+				"invalid log level %s, using %s",
+				cfg.Level,
+				level.String())
+		} else {
+			level = parsed
+		}
+	}
+	logrus.SetLevel(level)
+}
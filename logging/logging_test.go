@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	assert := assert.New(t)
+
+	defer Setup(&config.Logging{})
+
+	Setup(&config.Logging{Level: "debug", Format: "json", Caller: true})
+	assert.Equal(logrus.DebugLevel, logrus.GetLevel())
+	assert.IsType(&logrus.JSONFormatter{}, logrus.StandardLogger().Formatter)
+	assert.True(logrus.StandardLogger().ReportCaller)
+
+	Setup(&config.Logging{Level: "invalid"})
+	assert.Equal(logrus.InfoLevel, logrus.GetLevel())
+	assert.IsType(&logrus.TextFormatter{}, logrus.StandardLogger().Formatter)
+	assert.False(logrus.StandardLogger().ReportCaller)
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// OtherLabel is label value of namespaces and reasons over cardinality
+// limits
+const OtherLabel = "other"
+
+// failures counts failures by namespace and reason when it's enabled
+var failures struct {
+	mu         sync.RWMutex
+	enabled    bool
+	namespaces *LabelLimiter
+	reasons    *LabelLimiter
+}
+
+// ConfigureFailures enables counting failures by namespace and reason with
+// configured cardinality limits
+func ConfigureFailures(cfg *config.Metrics) {
+	failures.mu.Lock()
+	defer failures.mu.Unlock()
+
+	failures.enabled = cfg.PerNamespace
+	failures.namespaces = NewLabelLimiter(cfg.Namespaces, cfg.MaxNamespaces)
+	failures.reasons = NewLabelLimiter(cfg.Reasons, cfg.MaxReasons)
+}
+
+// RecordFailure counts failure of namespace with reason if it's enabled
+func RecordFailure(namespace, reason string) {
+	failures.mu.RLock()
+	defer failures.mu.RUnlock()
+
+	if !failures.enabled {
+		return
+	}
+
+	Failures.WithLabelValues(
+		failures.namespaces.Value(namespace),
+		failures.reasons.Value(reason)).Inc()
+}
+
+// LabelLimiter caps number of distinct values of a label, values not in
+// allow list or seen after the cap is reached are replaced with OtherLabel
+type LabelLimiter struct {
+	allowed map[string]bool
+	max     int
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewLabelLimiter returns limiter allowing given values only if allowed is
+// not empty, otherwise first max distinct values. Zero max means no cap
+func NewLabelLimiter(allowed []string, max int) *LabelLimiter {
+	l := &LabelLimiter{
+		max:  max,
+		seen: make(map[string]bool),
+	}
+
+	if len(allowed) > 0 {
+		l.allowed = make(map[string]bool, len(allowed))
+		for _, value := range allowed {
+			l.allowed[value] = true
+		}
+	}
+
+	return l
+}
+
+// Value returns value to be used as label
+func (l *LabelLimiter) Value(value string) string {
+	if l.allowed != nil {
+		if l.allowed[value] {
+			return value
+		}
+		return OtherLabel
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.seen[value] {
+		return value
+	}
+
+	if l.max > 0 && len(l.seen) >= l.max {
+		return OtherLabel
+	}
+
+	l.seen[value] = true
+	return value
+}
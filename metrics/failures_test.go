@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewLabelLimiter(nil, 2)
+	assert.Equal("a", l.Value("a"))
+	assert.Equal("b", l.Value("b"))
+	assert.Equal(OtherLabel, l.Value("c"))
+	assert.Equal("a", l.Value("a"))
+
+	l = NewLabelLimiter([]string{"prod"}, 1)
+	assert.Equal("prod", l.Value("prod"))
+	assert.Equal(OtherLabel, l.Value("dev"))
+
+	l = NewLabelLimiter(nil, 0)
+	for _, value := range []string{"a", "b", "c"} {
+		assert.Equal(value, l.Value(value))
+	}
+}
+
+func TestRecordFailure(t *testing.T) {
+	assert := assert.New(t)
+	Failures.Reset()
+
+	ConfigureFailures(&config.Metrics{})
+	RecordFailure("default", "OOMKilled")
+	assert.Equal(0, testutil.CollectAndCount(Failures))
+
+	ConfigureFailures(&config.Metrics{
+		PerNamespace:  true,
+		MaxNamespaces: 1,
+		Reasons:       []string{"OOMKilled"},
+	})
+	RecordFailure("default", "OOMKilled")
+	RecordFailure("default", "Error")
+	RecordFailure("prod", "OOMKilled")
+
+	assert.Equal(1.0, testutil.ToFloat64(
+		Failures.WithLabelValues("default", "OOMKilled")))
+	assert.Equal(1.0, testutil.ToFloat64(
+		Failures.WithLabelValues("default", OtherLabel)))
+	assert.Equal(1.0, testutil.ToFloat64(
+		Failures.WithLabelValues(OtherLabel, "OOMKilled")))
+
+	ConfigureFailures(&config.Metrics{})
+}
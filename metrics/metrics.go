@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "kwatch"
+
+var (
+	// BuildInfo exposes version, commit and config hash of running kwatch
+	BuildInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "build_info",
+			Help:      "Build info of kwatch, value is always 1.",
+		},
+		[]string{"version", "commit", "build_date", "config_hash"},
+	)
+
+	// IsLeader is 1 if instance is the elected leader sending alerts
+	IsLeader = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "is_leader",
+			Help:      "Whether this instance is the elected leader.",
+		},
+	)
+
+	// EventsObserved counts pod events received from kubernetes by type
+	EventsObserved = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_observed_total",
+			Help:      "Number of pod events observed by type.",
+		},
+		[]string{"type"},
+	)
+
+	// AlertsSent counts alerts successfully sent by provider
+	AlertsSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "alerts_sent_total",
+			Help:      "Number of alerts successfully sent by provider.",
+		},
+		[]string{"provider"},
+	)
+
+	// AlertSendFailures counts alerts that providers failed to send
+	AlertSendFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "alert_send_failures_total",
+			Help:      "Number of alerts that failed to be sent by provider.",
+		},
+		[]string{"provider"},
+	)
+
+	// AlertSendDuration observes time taken by providers to send alerts
+	AlertSendDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "alert_send_duration_seconds",
+			Help:      "Time taken to send alerts by provider.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"provider"},
+	)
+
+	// ProviderConsecutiveFailures tracks consecutive failed sends by
+	// provider
+	ProviderConsecutiveFailures = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "provider_consecutive_failures",
+			Help:      "Number of consecutive failed sends by provider.",
+		},
+		[]string{"provider"},
+	)
+
+	// DispatchQueueLength tracks pending notifications by provider
+	DispatchQueueLength = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "dispatch_queue_length",
+			Help:      "Number of pending notifications by provider.",
+		},
+		[]string{"provider"},
+	)
+
+	// DispatchDropped counts notifications dropped as queue was full
+	DispatchDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dispatch_dropped_total",
+			Help:      "Number of notifications dropped as queue was full.",
+		},
+		[]string{"provider"},
+	)
+
+	// PodStateSize tracks number of pods kept in pod state cache
+	PodStateSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pod_state_size",
+			Help:      "Number of pods kept in pod state cache.",
+		},
+	)
+
+	// FilterDrops counts pods and containers dropped by filters
+	FilterDrops = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "filter_drops_total",
+			Help:      "Number of pods and containers dropped by filter.",
+		},
+		[]string{"filter"},
+	)
+
+	// Failures counts detected failures by namespace and reason, it's only
+	// used if per namespace metrics are enabled
+	Failures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "failures_total",
+			Help:      "Number of detected failures by namespace and reason.",
+		},
+		[]string{"namespace", "reason"},
+	)
+
+	// WatchRestarts counts restarts of the pod watch
+	WatchRestarts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "watch_restarts_total",
+			Help:      "Number of times the pod watch was restarted.",
+		},
+	)
+
+	// PvcChecks counts checks of persistent volume claims usage
+	PvcChecks = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pvc_checks_total",
+			Help:      "Number of persistent volume claims usage checks.",
+		},
+	)
+
+	// PvcCheckDuration observes time taken to check persistent volume claims
+	// usage
+	PvcCheckDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "pvc_check_duration_seconds",
+			Help:      "Time taken to check persistent volume claims usage.",
+			Buckets:   prometheus.DefBuckets,
+		},
+	)
+
+	// VersionSkew tracks minor version skew between Kubernetes API server of
+	// cluster and client-go kwatch is built with
+	VersionSkew = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "kubernetes_version_skew",
+			Help: "Minor version skew between Kubernetes API server " +
+				"and kwatch client by cluster.",
+		},
+		[]string{"cluster"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		BuildInfo,
+		IsLeader,
+		EventsObserved,
+		AlertsSent,
+		AlertSendFailures,
+		AlertSendDuration,
+		ProviderConsecutiveFailures,
+		DispatchQueueLength,
+		DispatchDropped,
+		PodStateSize,
+		FilterDrops,
+		Failures,
+		WatchRestarts,
+		PvcChecks,
+		PvcCheckDuration,
+		VersionSkew,
+	)
+}
+
+// Handler returns HTTP handler that exposes metrics in prometheus format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,161 @@
+package namespacemonitor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// blockingConditions are namespace conditions telling why deletion of a
+// namespace is blocked
+var blockingConditions = []corev1.NamespaceConditionType{
+	corev1.NamespaceDeletionDiscoveryFailure,
+	corev1.NamespaceDeletionContentFailure,
+	corev1.NamespaceDeletionGVParsingFailure,
+	corev1.NamespaceContentRemaining,
+	corev1.NamespaceFinalizersRemaining,
+}
+
+type NamespaceMonitor struct {
+	client       kubernetes.Interface
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+
+	// notified are uids of notified namespaces, so a stuck namespace is
+	// notified once
+	notified map[types.UID]bool
+}
+
+// NewNamespaceMonitor returns new instance of terminating namespace monitor
+// of cluster
+func NewNamespaceMonitor(
+	client kubernetes.Interface,
+	config *config.Config,
+	alertManager *alertmanager.AlertManager) *NamespaceMonitor {
+	return &NamespaceMonitor{
+		client:       client,
+		config:       config,
+		alertManager: alertManager,
+		notified:     make(map[types.UID]bool),
+	}
+}
+
+// Start checks namespaces at startup and on configured interval
+func (m *NamespaceMonitor) Start() {
+	if !m.config.TerminatingNamespaces.Enabled {
+		return
+	}
+
+	// check at startup
+	m.Check(time.Now())
+
+	interval := m.config.TerminatingNamespaces.Interval
+	if interval <= 0 {
+		interval = 5
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		m.Check(now)
+	}
+}
+
+// Check notifies namespaces terminating for longer than threshold with
+// finalizers and resources blocking their deletion
+func (m *NamespaceMonitor) Check(now time.Time) {
+	namespaces, err := m.client.CoreV1().
+		Namespaces().
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logrus.WithField("cluster", m.config.App.ClusterName).
+			WithError(err).
+			Warn("failed to list namespaces")
+		return
+	}
+
+	threshold :=
+		time.Duration(m.config.TerminatingNamespaces.Threshold) * time.Minute
+	if threshold <= 0 {
+		threshold = 30 * time.Minute
+	}
+
+	terminating := make(map[types.UID]bool)
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if ns.DeletionTimestamp == nil || !m.config.IsNamespaceWatched(ns.Name) {
+			continue
+		}
+		terminating[ns.UID] = true
+
+		age := now.Sub(ns.DeletionTimestamp.Time)
+		if age < threshold || m.notified[ns.UID] {
+			continue
+		}
+		m.notified[ns.UID] = true
+
+		logrus.WithFields(logrus.Fields{
+			"cluster":   m.config.App.ClusterName,
+			"namespace": ns.Name,
+		}).Warn("namespace is stuck in terminating")
+
+		m.alertManager.Notify(m.message(ns, age))
+	}
+
+	// deleted namespaces are forgotten
+	for uid := range m.notified {
+		if !terminating[uid] {
+			delete(m.notified, uid)
+		}
+	}
+}
+
+// message returns notification of stuck namespace listing its finalizers
+// and conditions blocking deletion
+func (m *NamespaceMonitor) message(
+	ns *corev1.Namespace,
+	age time.Duration) string {
+	name := ns.Name
+	if len(m.config.App.ClusterName) > 0 {
+		name += " of cluster " + m.config.App.ClusterName
+	}
+
+	lines := []string{fmt.Sprintf(
+		constant.NamespaceTerminatingMsg,
+		name,
+		age.Truncate(time.Minute))}
+
+	finalizers := make([]string, 0)
+	for _, finalizer := range ns.Spec.Finalizers {
+		finalizers = append(finalizers, string(finalizer))
+	}
+	finalizers = append(finalizers, ns.Finalizers...)
+	if len(finalizers) > 0 {
+		lines = append(lines,
+			"Finalizers: "+strings.Join(finalizers, ", "))
+	}
+
+	for _, condition := range ns.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue ||
+			!slices.Contains(blockingConditions, condition.Type) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s",
+			condition.Type,
+			condition.Message))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,113 @@
+package namespacemonitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	deletedAt := metav1.NewTime(now.Add(-10 * time.Minute))
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "ci",
+				UID:               "ci-1",
+				DeletionTimestamp: &deletedAt,
+				Finalizers:        []string{"example.com/cleanup"},
+			},
+			Spec: corev1.NamespaceSpec{
+				Finalizers: []corev1.FinalizerName{
+					corev1.FinalizerKubernetes,
+				},
+			},
+			Status: corev1.NamespaceStatus{
+				Phase: corev1.NamespaceTerminating,
+				Conditions: []corev1.NamespaceCondition{
+					{
+						Type:   corev1.NamespaceDeletionDiscoveryFailure,
+						Status: corev1.ConditionFalse,
+					},
+					{
+						Type:   corev1.NamespaceContentRemaining,
+						Status: corev1.ConditionTrue,
+						Message: "Some resources are remaining: " +
+							"widgets.example.com has 1 resource instances",
+					},
+				},
+			},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "kube-system",
+				UID:               "kube-system-1",
+				DeletionTimestamp: &deletedAt,
+			},
+		},
+	)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := &config.Config{
+		ForbiddenNamespaces: []string{"kube-system"},
+		TerminatingNamespaces: config.TerminatingNamespaces{
+			Enabled:   true,
+			Threshold: 30,
+		},
+	}
+	cfg.App.ClusterName = "prod"
+
+	m := NewNamespaceMonitor(client, cfg, alertManager)
+	m.Check(now)
+	assert.Len(prv.messages, 0)
+
+	// stuck namespace is notified once
+	m.Check(now.Add(25 * time.Minute))
+	m.Check(now.Add(30 * time.Minute))
+	assert.Len(prv.messages, 1)
+	assert.Equal(
+		":warning: Namespace ci of cluster prod has been stuck in "+
+			"Terminating for 35m0s.\n"+
+			"Finalizers: kubernetes, example.com/cleanup\n"+
+			"NamespaceContentRemaining: Some resources are remaining: "+
+			"widgets.example.com has 1 resource instances",
+		prv.messages[0])
+
+	// deleted namespaces are forgotten
+	client.CoreV1().Namespaces().Delete(
+		context.TODO(),
+		"ci",
+		metav1.DeleteOptions{})
+	m.Check(now.Add(35 * time.Minute))
+	assert.Empty(m.notified)
+}
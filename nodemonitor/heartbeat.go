@@ -0,0 +1,159 @@
+package nodemonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeLeaseNamespace is namespace of leases kubelets renew as heartbeats
+const nodeLeaseNamespace = "kube-node-lease"
+
+type HeartbeatMonitor struct {
+	client       kubernetes.Interface
+	config       *config.NodeHeartbeat
+	clusterName  string
+	alertManager *alertmanager.AlertManager
+
+	// stale are names of nodes notified of stale heartbeats, so staleness
+	// is notified once until heartbeats resume
+	stale map[string]bool
+}
+
+// NewHeartbeatMonitor returns new instance of node heartbeat monitor of
+// cluster
+func NewHeartbeatMonitor(
+	client kubernetes.Interface,
+	config *config.NodeHeartbeat,
+	clusterName string,
+	alertManager *alertmanager.AlertManager) *HeartbeatMonitor {
+	return &HeartbeatMonitor{
+		client:       client,
+		config:       config,
+		clusterName:  clusterName,
+		alertManager: alertManager,
+		stale:        make(map[string]bool),
+	}
+}
+
+// Start checks heartbeats of nodes on configured interval
+func (m *HeartbeatMonitor) Start() {
+	if !m.config.Enabled {
+		return
+	}
+
+	interval := m.config.Interval
+	if interval <= 0 {
+		interval = 5
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		m.Check(now)
+	}
+}
+
+// Check notifies nodes whose last heartbeat is older than threshold,
+// heartbeats are renewals of node leases, or heartbeats of node conditions
+// if leases can't be listed
+func (m *HeartbeatMonitor) Check(now time.Time) {
+	// lists are served from cache of API server
+	opts := metav1.ListOptions{ResourceVersion: "0"}
+	nodes, err := m.client.CoreV1().Nodes().List(context.TODO(), opts)
+	if err != nil {
+		logrus.WithField("cluster", m.clusterName).
+			WithError(err).
+			Warn("failed to list nodes")
+		return
+	}
+
+	renewals := make(map[string]time.Time)
+	leases, err := m.client.CoordinationV1().
+		Leases(nodeLeaseNamespace).
+		List(context.TODO(), opts)
+	if err != nil {
+		logrus.WithField("cluster", m.clusterName).
+			WithError(err).
+			Debug("failed to list node leases")
+	} else {
+		for _, lease := range leases.Items {
+			if lease.Spec.RenewTime != nil {
+				renewals[lease.Name] = lease.Spec.RenewTime.Time
+			}
+		}
+	}
+
+	threshold := time.Duration(m.config.Threshold) * time.Second
+	if threshold <= 0 {
+		threshold = 25 * time.Second
+	}
+
+	seen := make(map[string]bool, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		seen[node.Name] = true
+
+		last := getLastHeartbeat(node, renewals[node.Name])
+		if last.IsZero() {
+			continue
+		}
+
+		age := now.Sub(last)
+		if age < threshold {
+			if m.stale[node.Name] {
+				delete(m.stale, node.Name)
+				logrus.WithFields(logrus.Fields{
+					"cluster": m.clusterName,
+					"node":    node.Name,
+				}).Info("node heartbeats resumed")
+			}
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"cluster": m.clusterName,
+			"node":    node.Name,
+			"last":    last,
+		}).Warn("node heartbeat is stale")
+
+		if m.stale[node.Name] {
+			continue
+		}
+		m.stale[node.Name] = true
+
+		m.alertManager.Notify(fmt.Sprintf(
+			constant.NodeHeartbeatStaleMsg,
+			describeNode(node.Name, m.clusterName),
+			age.Truncate(time.Second),
+			last.UTC().Format(time.RFC3339)))
+	}
+
+	// deleted nodes are forgotten
+	for name := range m.stale {
+		if !seen[name] {
+			delete(m.stale, name)
+		}
+	}
+}
+
+// getLastHeartbeat returns latest of lease renewal and heartbeats of
+// conditions of node
+func getLastHeartbeat(node *corev1.Node, renewal time.Time) time.Time {
+	last := renewal
+	for _, condition := range node.Status.Conditions {
+		if condition.LastHeartbeatTime.After(last) {
+			last = condition.LastHeartbeatTime.Time
+		}
+	}
+	return last
+}
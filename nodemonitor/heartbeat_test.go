@@ -0,0 +1,89 @@
+package nodemonitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHeartbeatCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	renewTime := metav1.NewMicroTime(now.Add(-5 * time.Second))
+	client := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{
+					Type:              corev1.NodeReady,
+					Status:            corev1.ConditionTrue,
+					LastHeartbeatTime: metav1.NewTime(now.Add(-time.Minute)),
+				}},
+			},
+		},
+		&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "node-1",
+				Namespace: nodeLeaseNamespace,
+			},
+			Spec: coordinationv1.LeaseSpec{RenewTime: &renewTime},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+	)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	m := NewHeartbeatMonitor(
+		client,
+		&config.NodeHeartbeat{Enabled: true, Threshold: 25},
+		"prod",
+		alertManager)
+
+	// lease is renewed and node without heartbeats is skipped
+	m.Check(now)
+	assert.Len(prv.messages, 0)
+
+	// stale heartbeat is notified once
+	m.Check(now.Add(30 * time.Second))
+	m.Check(now.Add(40 * time.Second))
+	assert.Len(prv.messages, 1)
+	assert.Contains(prv.messages[0], "Node node-1 of cluster prod")
+	assert.Contains(prv.messages[0], "for 35s")
+
+	// resumed heartbeats are notified again once stale
+	m.Check(now)
+	assert.Empty(m.stale)
+	m.Check(now.Add(time.Minute))
+	assert.Len(prv.messages, 2)
+}
+
+func TestGetLastHeartbeat(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now().Truncate(time.Second)
+	node := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{LastHeartbeatTime: metav1.NewTime(now.Add(-time.Minute))},
+				{LastHeartbeatTime: metav1.NewTime(now)},
+			},
+		},
+	}
+
+	assert.Equal(now, getLastHeartbeat(node, time.Time{}))
+	assert.Equal(
+		now.Add(time.Second),
+		getLastHeartbeat(node, now.Add(time.Second)))
+	assert.True(getLastHeartbeat(&corev1.Node{}, time.Time{}).IsZero())
+}
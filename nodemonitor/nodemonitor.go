@@ -0,0 +1,218 @@
+package nodemonitor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type NodeMonitor struct {
+	client       kubernetes.Interface
+	config       *config.NodeMonitor
+	clusterName  string
+	alertManager *alertmanager.AlertManager
+
+	// baselines are highest allocatable quantities of watched resources
+	// seen by node and resource name
+	baselines map[string]map[corev1.ResourceName]resource.Quantity
+
+	// notified are allocatable quantities of last notification by node and
+	// resource name, so a degradation is notified once until it recovers
+	notified map[string]map[corev1.ResourceName]string
+}
+
+// NewNodeMonitor returns new instance of node monitor of cluster
+func NewNodeMonitor(
+	client kubernetes.Interface,
+	config *config.NodeMonitor,
+	clusterName string,
+	alertManager *alertmanager.AlertManager) *NodeMonitor {
+	return &NodeMonitor{
+		client:       client,
+		config:       config,
+		clusterName:  clusterName,
+		alertManager: alertManager,
+		baselines: make(
+			map[string]map[corev1.ResourceName]resource.Quantity),
+		notified: make(map[string]map[corev1.ResourceName]string),
+	}
+}
+
+// Start checks nodes at startup and on configured interval
+func (m *NodeMonitor) Start() {
+	if !m.config.Enabled {
+		return
+	}
+
+	// check at startup
+	m.Check()
+
+	interval := m.config.Interval
+	if interval <= 0 {
+		interval = 5
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.Check()
+	}
+}
+
+// Check compares allocatable resources of nodes with highest ones seen and
+// their capacity, and notifies of degraded resources
+func (m *NodeMonitor) Check() {
+	nodes, err := m.client.CoreV1().
+		Nodes().
+		List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		logrus.WithField("cluster", m.clusterName).
+			WithError(err).
+			Warn("failed to list nodes")
+		return
+	}
+
+	seen := make(map[string]bool, len(nodes.Items))
+	for i := range nodes.Items {
+		seen[nodes.Items[i].Name] = true
+		m.checkNode(&nodes.Items[i])
+	}
+
+	// deleted nodes are forgotten
+	for name := range m.baselines {
+		if !seen[name] {
+			delete(m.baselines, name)
+			delete(m.notified, name)
+		}
+	}
+}
+
+// checkNode notifies of watched resources of node whose allocatable
+// quantity dropped below highest one seen, or below capacity of devices
+func (m *NodeMonitor) checkNode(node *corev1.Node) {
+	baseline, ok := m.baselines[node.Name]
+	if !ok {
+		baseline = make(map[corev1.ResourceName]resource.Quantity)
+		m.baselines[node.Name] = baseline
+		m.notified[node.Name] = make(map[corev1.ResourceName]string)
+	}
+
+	// resources missing from allocatable dropped to zero
+	names := make([]corev1.ResourceName, 0)
+	for name := range node.Status.Allocatable {
+		names = append(names, name)
+	}
+	for name := range baseline {
+		if _, ok := node.Status.Allocatable[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !m.isWatched(name) {
+			continue
+		}
+
+		allocatable := node.Status.Allocatable[name]
+		msg := m.degradation(node, name, baseline[name], allocatable)
+
+		last, ok := baseline[name]
+		if !ok || allocatable.Cmp(last) > 0 {
+			baseline[name] = allocatable
+		}
+
+		if len(msg) == 0 {
+			if _, ok := m.notified[node.Name][name]; ok {
+				logrus.WithFields(logrus.Fields{
+					"node":     node.Name,
+					"resource": name,
+				}).Info("node resource recovered")
+				delete(m.notified[node.Name], name)
+			}
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"cluster":     m.clusterName,
+			"node":        node.Name,
+			"resource":    name,
+			"allocatable": allocatable.String(),
+		}).Warn("node resource is degraded")
+
+		if m.notified[node.Name][name] == allocatable.String() {
+			continue
+		}
+		m.notified[node.Name][name] = allocatable.String()
+		m.alertManager.Notify(msg)
+	}
+}
+
+// degradation returns message of degraded resource of node, or empty
+// string if it isn't degraded
+func (m *NodeMonitor) degradation(
+	node *corev1.Node,
+	name corev1.ResourceName,
+	baseline resource.Quantity,
+	allocatable resource.Quantity) string {
+	if allocatable.Cmp(baseline) < 0 {
+		return fmt.Sprintf(
+			constant.NodeCapacityMsg,
+			describeNode(node.Name, m.clusterName),
+			name,
+			baseline.String(),
+			allocatable.String())
+	}
+
+	// device plugins report unhealthy devices as capacity which isn't
+	// allocatable
+	capacity, ok := node.Status.Capacity[name]
+	if ok && isExtendedResource(name) && allocatable.Cmp(capacity) < 0 {
+		return fmt.Sprintf(
+			constant.NodeUnhealthyDevicesMsg,
+			describeNode(node.Name, m.clusterName),
+			name,
+			allocatable.String(),
+			capacity.String())
+	}
+
+	return ""
+}
+
+// describeNode returns name of node and its cluster if it's set
+func describeNode(name string, clusterName string) string {
+	if len(clusterName) > 0 {
+		return name + " of cluster " + clusterName
+	}
+	return name
+}
+
+// isWatched returns true if resource is configured, or it's an extended
+// resource or hugepages if none is configured
+func (m *NodeMonitor) isWatched(name corev1.ResourceName) bool {
+	if len(m.config.Resources) > 0 {
+		return slices.Contains(m.config.Resources, string(name))
+	}
+
+	return isExtendedResource(name) ||
+		strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix)
+}
+
+// isExtendedResource returns true if resource is advertised by a device
+// plugin or an operator e.g. nvidia.com/gpu
+func isExtendedResource(name corev1.ResourceName) bool {
+	return strings.Contains(string(name), "/") &&
+		!strings.HasPrefix(string(name), corev1.ResourceDefaultNamespacePrefix)
+}
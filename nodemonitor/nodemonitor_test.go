@@ -0,0 +1,149 @@
+package nodemonitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func newNode(capacity, allocatable corev1.ResourceList) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-1"},
+		Status: corev1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: allocatable,
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("16"),
+		"nvidia.com/gpu":      resource.MustParse("8"),
+		"hugepages-2Mi":       resource.MustParse("1Gi"),
+		"example.com/storage": resource.MustParse("2"),
+	}
+	client := fake.NewSimpleClientset(newNode(resources, resources))
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	m := NewNodeMonitor(
+		client,
+		&config.NodeMonitor{Enabled: true},
+		"prod",
+		alertManager)
+	m.Check()
+	assert.Len(prv.messages, 0)
+
+	update := func(capacity, allocatable corev1.ResourceList) {
+		client.CoreV1().Nodes().Update(
+			context.TODO(),
+			newNode(capacity, allocatable),
+			metav1.UpdateOptions{})
+	}
+
+	// gpu disappeared and cpu isn't watched by default
+	lost := resources.DeepCopy()
+	lost["nvidia.com/gpu"] = resource.MustParse("7")
+	lost[corev1.ResourceCPU] = resource.MustParse("8")
+	update(lost, lost)
+	m.Check()
+	assert.Len(prv.messages, 1)
+	assert.Contains(prv.messages[0], "gpu-1 of cluster prod")
+	assert.Contains(prv.messages[0], "nvidia.com/gpu")
+	assert.Contains(prv.messages[0], "from 8 to 7")
+
+	// degradation is notified once
+	m.Check()
+	assert.Len(prv.messages, 1)
+
+	// recovered resource is notified again if it degrades
+	update(resources, resources)
+	m.Check()
+	assert.Len(prv.messages, 1)
+
+	// unhealthy devices and missing resources
+	unhealthy := resources.DeepCopy()
+	unhealthy["nvidia.com/gpu"] = resource.MustParse("6")
+	delete(unhealthy, "hugepages-2Mi")
+	update(resources, unhealthy)
+	m.Check()
+	assert.Len(prv.messages, 3)
+	assert.Contains(prv.messages[1], "hugepages-2Mi")
+	assert.Contains(prv.messages[1], "to 0")
+	assert.Contains(prv.messages[2], "nvidia.com/gpu")
+
+	// deleted nodes are forgotten
+	client.CoreV1().Nodes().Delete(
+		context.TODO(),
+		"gpu-1",
+		metav1.DeleteOptions{})
+	m.Check()
+	assert.Empty(m.baselines)
+	assert.Empty(m.notified)
+}
+
+func TestCheckUnhealthyDevices(t *testing.T) {
+	assert := assert.New(t)
+
+	client := fake.NewSimpleClientset(newNode(
+		corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")},
+		corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("6")}))
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	m := NewNodeMonitor(
+		client,
+		&config.NodeMonitor{Enabled: true},
+		"",
+		alertManager)
+	m.Check()
+	assert.Len(prv.messages, 1)
+	assert.Contains(prv.messages[0], "Node gpu-1 has unhealthy nvidia.com/gpu")
+	assert.Contains(prv.messages[0], "6 of 8")
+}
+
+func TestIsWatched(t *testing.T) {
+	assert := assert.New(t)
+
+	m := NewNodeMonitor(nil, &config.NodeMonitor{}, "", nil)
+	assert.True(m.isWatched("nvidia.com/gpu"))
+	assert.True(m.isWatched("hugepages-1Gi"))
+	assert.False(m.isWatched(corev1.ResourceMemory))
+	assert.False(m.isWatched("kubernetes.io/batch-cpu"))
+
+	m.config.Resources = []string{"memory"}
+	assert.True(m.isWatched(corev1.ResourceMemory))
+	assert.False(m.isWatched("nvidia.com/gpu"))
+}
@@ -0,0 +1,116 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+)
+
+// internalSuffixes are suffixes of cluster and link-local host names
+var internalSuffixes = []string{".svc", ".cluster.local", ".local"}
+
+// Guard rejects requests to external hosts, so accidental external calls
+// fail instead of leaving air-gapped clusters
+type Guard struct {
+	next         http.RoundTripper
+	allowedHosts map[string]bool
+
+	// lookupIP resolves host names, it's replaced in tests
+	lookupIP func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewGuard returns new instance of guard sending requests to internal or
+// allowed hosts using next round tripper
+func NewGuard(next http.RoundTripper, allowedHosts []string) *Guard {
+	hosts := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		hosts[strings.ToLower(host)] = true
+	}
+
+	return &Guard{
+		next:         next,
+		allowedHosts: hosts,
+		lookupIP:     net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// enabledCfg is app config of enabled offline mode, nil if it's disabled
+var enabledCfg *config.App
+
+// Enable replaces default transport with a guard if offline mode is enabled,
+// clients without their own transport e.g. providers are guarded
+func Enable(appCfg *config.App) {
+	if !appCfg.Offline {
+		return
+	}
+
+	enabledCfg = appCfg
+	http.DefaultTransport = Wrap(http.DefaultTransport)
+	logrus.Info("offline mode is enabled, external requests are rejected")
+}
+
+// Wrap returns round tripper guarded if offline mode is enabled, so clients
+// with their own transport e.g. per provider proxy are guarded too
+func Wrap(next http.RoundTripper) http.RoundTripper {
+	if enabledCfg == nil {
+		return next
+	}
+	return NewGuard(next, enabledCfg.OfflineAllowedHosts)
+}
+
+// RoundTrip sends request if its host is internal or allowed
+func (g *Guard) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !g.isAllowed(req.Context(), host) {
+		logrus.WithField("host", host).
+			Error("rejected external request in offline mode")
+		return nil, fmt.Errorf("external request to %s in offline mode", host)
+	}
+
+	return g.next.RoundTrip(req)
+}
+
+// isAllowed returns true if host is allowed, an internal name or resolves
+// to private addresses only
+func (g *Guard) isAllowed(ctx context.Context, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if g.allowedHosts[host] {
+		return true
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return isInternalIP(ip)
+	}
+
+	if !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range internalSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	addrs, err := g.lookupIP(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		if !isInternalIP(addr.IP) {
+			return false
+		}
+	}
+	return true
+}
+
+// isInternalIP returns true if ip is private, loopback or link-local
+func isInternalIP(ip net.IP) bool {
+	return ip.IsPrivate() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast()
+}
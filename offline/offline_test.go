@@ -0,0 +1,57 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewGuard(http.DefaultTransport, []string{"Chat.Example.com"})
+	g.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "git.corp.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("10.0.0.5")}}, nil
+		case "api.github.com":
+			return []net.IPAddr{{IP: net.ParseIP("140.82.112.6")}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	ctx := context.Background()
+	assert.True(g.isAllowed(ctx, "chat.example.com"))
+	assert.True(g.isAllowed(ctx, "127.0.0.1"))
+	assert.True(g.isAllowed(ctx, "192.168.1.10"))
+	assert.True(g.isAllowed(ctx, "mattermost"))
+	assert.True(g.isAllowed(ctx, "webhook.monitoring.svc"))
+	assert.True(g.isAllowed(ctx, "webhook.monitoring.svc.cluster.local."))
+	assert.True(g.isAllowed(ctx, "git.corp.example.com"))
+	assert.False(g.isAllowed(ctx, "api.github.com"))
+	assert.False(g.isAllowed(ctx, "8.8.8.8"))
+	assert.False(g.isAllowed(ctx, "unknown.example.com"))
+}
+
+func TestRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewGuard(http.DefaultTransport, nil)}
+
+	resp, err := client.Get(srv.URL)
+	assert.Nil(err)
+	resp.Body.Close()
+
+	_, err = client.Get("https://1.1.1.1")
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "offline mode")
+}
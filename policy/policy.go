@@ -0,0 +1,252 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodLister lists cached pods in namespace, or in all namespaces if it's
+// empty
+type PodLister interface {
+	ListPods(namespace string) ([]*corev1.Pod, error)
+}
+
+// Scanner reports containers of watched pods violating policies e.g.
+// without required resource limits
+type Scanner struct {
+	pods         PodLister
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+}
+
+// violation is a workload whose containers violate policies
+type violation struct {
+	workload string
+
+	// containers are descriptions of violations by container name
+	containers map[string][]string
+}
+
+// NewScanner returns new instance of policy scanner of cluster
+func NewScanner(
+	pods PodLister,
+	config *config.Config,
+	alertManager *alertmanager.AlertManager) *Scanner {
+	return &Scanner{
+		pods:         pods,
+		config:       config,
+		alertManager: alertManager,
+	}
+}
+
+// Start sends report on configured interval until stopCh is closed, first
+// report is sent after an interval so caches of pods are synced
+func (s *Scanner) Start(stopCh <-chan struct{}) {
+	if !s.config.Policy.Enabled {
+		return
+	}
+
+	interval := s.config.Policy.Interval
+	if interval <= 0 {
+		interval = 24
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Report()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// Report sends report of containers violating policies to configured
+// providers, nothing is sent if there are none
+func (s *Scanner) Report() {
+	violations, err := s.scan()
+	if err != nil {
+		logrus.WithError(err).Error("failed to scan pods of policies")
+		return
+	}
+
+	if len(violations) == 0 {
+		logrus.Info("no containers violate policies")
+		return
+	}
+
+	s.alertManager.NotifyProviders(
+		s.config.Policy.Providers,
+		s.message(violations))
+}
+
+// scan returns workloads with containers violating policies sorted by
+// workload
+func (s *Scanner) scan() ([]*violation, error) {
+	pods, err := s.pods.ListPods(metav1.NamespaceAll)
+	if err != nil {
+		return nil, err
+	}
+
+	byWorkload := make(map[string]*violation)
+	for _, pod := range pods {
+		if !s.config.IsNamespaceWatched(pod.Namespace) ||
+			pod.Status.Phase == corev1.PodSucceeded ||
+			pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		workload := getWorkload(pod)
+		for _, container := range pod.Spec.Containers {
+			issues := s.check(&container)
+			if len(issues) == 0 {
+				continue
+			}
+
+			v, ok := byWorkload[workload]
+			if !ok {
+				v = &violation{
+					workload:   workload,
+					containers: make(map[string][]string),
+				}
+				byWorkload[workload] = v
+			}
+			v.containers[container.Name] = issues
+		}
+	}
+
+	violations := make([]*violation, 0, len(byWorkload))
+	for _, v := range byWorkload {
+		violations = append(violations, v)
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].workload < violations[j].workload
+	})
+	return violations, nil
+}
+
+// check returns descriptions of policies container violates
+func (s *Scanner) check(container *corev1.Container) []string {
+	issues := make([]string, 0)
+
+	missing := make([]string, 0)
+	for _, name := range s.config.Policy.RequiredLimits {
+		limit, ok := container.Resources.Limits[corev1.ResourceName(name)]
+		if !ok || limit.IsZero() {
+			missing = append(missing, name)
+		}
+	}
+	switch len(missing) {
+	case 0:
+	case 1:
+		issues = append(issues, "no "+missing[0]+" limit")
+	default:
+		issues = append(issues,
+			"no "+strings.Join(missing, ", ")+" limits")
+	}
+
+	if s.config.Policy.ImageTags &&
+		isMutableImage(container.Image, s.config.Policy.MutableTags) {
+		issues = append(issues, "mutable image "+container.Image)
+	}
+
+	return issues
+}
+
+// message returns report of violations, up to max workloads are listed
+func (s *Scanner) message(violations []*violation) string {
+	containers := 0
+	for _, v := range violations {
+		containers += len(v.containers)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b,
+		":clipboard: kwatch policy report%s: %d containers of %d "+
+			"workloads violate policies\n",
+		s.describeCluster(),
+		containers,
+		len(violations))
+
+	maxWorkloads := s.config.Policy.MaxWorkloads
+	if maxWorkloads <= 0 {
+		maxWorkloads = 20
+	}
+
+	for i, v := range violations {
+		if i == maxWorkloads {
+			fmt.Fprintf(&b,
+				"…and %d more workloads\n",
+				len(violations)-maxWorkloads)
+			break
+		}
+
+		names := make([]string, 0, len(v.containers))
+		for name := range v.containers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		items := make([]string, 0, len(names))
+		for _, name := range names {
+			items = append(items, fmt.Sprintf("%s (%s)",
+				name,
+				strings.Join(v.containers[name], "; ")))
+		}
+		fmt.Fprintf(&b, "• %s: %s\n", v.workload, strings.Join(items, ", "))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// describeCluster returns cluster of report if it's known
+func (s *Scanner) describeCluster() string {
+	if len(s.config.App.ClusterName) == 0 {
+		return ""
+	}
+	return " of cluster " + s.config.App.ClusterName
+}
+
+// isMutableImage returns true if image isn't pinned by digest and it has
+// no tag, which is latest, or a mutable tag
+func isMutableImage(image string, mutableTags []string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+
+	// colon of registry port isn't a tag separator e.g. registry:5000/api
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return true
+	}
+	return slices.Contains(mutableTags, image[i+1:])
+}
+
+// getWorkload returns owning workload of pod e.g. default/Deployment/api,
+// replicasets of deployments are resolved using pod template hash
+func getWorkload(pod *corev1.Pod) string {
+	workload := "Pod/" + pod.Name
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		workload = owner.Kind + "/" + owner.Name
+
+		hash := pod.Labels["pod-template-hash"]
+		if owner.Kind == "ReplicaSet" && len(hash) > 0 &&
+			strings.HasSuffix(owner.Name, "-"+hash) {
+			workload = "Deployment/" +
+				strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return pod.Namespace + "/" + workload
+}
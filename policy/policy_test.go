@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+type podList []*corev1.Pod
+
+func (l podList) ListPods(namespace string) ([]*corev1.Pod, error) {
+	return l, nil
+}
+
+func newPod(
+	namespace string,
+	name string,
+	owner string,
+	containers ...corev1.Container) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{"pod-template-hash": "5d8f"},
+		},
+		Spec: corev1.PodSpec{Containers: containers},
+	}
+	if len(owner) > 0 {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{
+			Kind:       "ReplicaSet",
+			Name:       owner,
+			Controller: &controller,
+		}}
+	}
+	return pod
+}
+
+func newContainer(name string, limits ...string) corev1.Container {
+	container := corev1.Container{
+		Name:  name,
+		Image: "example/" + name + ":1.0",
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{},
+		},
+	}
+	for _, limit := range limits {
+		container.Resources.Limits[corev1.ResourceName(limit)] =
+			resource.MustParse("1")
+	}
+	return container
+}
+
+func TestReport(t *testing.T) {
+	assert := assert.New(t)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := config.DefaultConfig()
+	cfg.Policy.Enabled = true
+	cfg.Policy.MaxWorkloads = 2
+	cfg.ForbiddenNamespaces = []string{"kube-system"}
+	cfg.App.ClusterName = "prod"
+
+	completed := newPod("default", "migrate", "", newContainer("migrate"))
+	completed.Status.Phase = corev1.PodSucceeded
+
+	pods := podList{
+		newPod("default", "api-5d8f-a", "api-5d8f",
+			newContainer("api", "memory"),
+			newContainer("proxy", "memory", "cpu")),
+		newPod("default", "api-5d8f-b", "api-5d8f",
+			newContainer("api", "memory"),
+			newContainer("proxy", "memory", "cpu")),
+		newPod("default", "worker", "", newContainer("worker")),
+		newPod("batch", "cron", "", newContainer("cron", "cpu")),
+		newPod("kube-system", "dns", "", newContainer("dns")),
+		completed,
+	}
+
+	NewScanner(pods, cfg, alertManager).Report()
+	assert.Equal([]string{
+		":clipboard: kwatch policy report of cluster prod: 3 containers " +
+			"of 3 workloads violate policies\n" +
+			"• batch/Pod/cron: cron (no memory limit)\n" +
+			"• default/Deployment/api: api (no cpu limit)\n" +
+			"…and 1 more workloads",
+	}, prv.messages)
+
+	// nothing is sent if all containers have limits
+	prv.messages = nil
+	NewScanner(podList{
+		newPod("default", "api", "", newContainer("api", "memory", "cpu")),
+	}, cfg, alertManager).Report()
+	assert.Empty(prv.messages)
+}
+
+func TestReportImageTags(t *testing.T) {
+	assert := assert.New(t)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := config.DefaultConfig()
+	cfg.Policy.Enabled = true
+	cfg.Policy.ImageTags = true
+
+	latest := newContainer("api", "memory")
+	latest.Image = "example/api:latest"
+	untagged := newContainer("proxy", "memory", "cpu")
+	untagged.Image = "registry:5000/proxy"
+	pinned := newContainer("worker", "memory", "cpu")
+	pinned.Image = "example/worker:latest@sha256:abc"
+
+	NewScanner(podList{
+		newPod("default", "api-5d8f-a", "api-5d8f", latest, untagged),
+		newPod("default", "worker", "", pinned),
+	}, cfg, alertManager).Report()
+	assert.Equal([]string{
+		":clipboard: kwatch policy report: 2 containers of 1 workloads " +
+			"violate policies\n" +
+			"• default/Deployment/api: api (no cpu limit; mutable image " +
+			"example/api:latest), proxy (mutable image registry:5000/proxy)",
+	}, prv.messages)
+}
+
+func TestIsMutableImage(t *testing.T) {
+	assert := assert.New(t)
+
+	mutableTags := []string{"latest", "main"}
+	assert.True(isMutableImage("nginx", mutableTags))
+	assert.True(isMutableImage("nginx:latest", mutableTags))
+	assert.True(isMutableImage("ghcr.io/org/api:main", mutableTags))
+	assert.True(isMutableImage("registry:5000/api", mutableTags))
+	assert.False(isMutableImage("nginx:1.25", mutableTags))
+	assert.False(isMutableImage("registry:5000/api:v2", mutableTags))
+	assert.False(isMutableImage("nginx@sha256:abc", mutableTags))
+}
+
+func TestGetWorkload(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("default/Pod/api", getWorkload(newPod("default", "api", "")))
+	assert.Equal(
+		"default/Deployment/api",
+		getWorkload(newPod("default", "api-5d8f-a", "api-5d8f")))
+	assert.Equal(
+		"default/ReplicaSet/api",
+		getWorkload(newPod("default", "api-a", "api")))
+}
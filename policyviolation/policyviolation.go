@@ -0,0 +1,196 @@
+package policyviolation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Forwarder groups violation events of policy engines by policy and
+// namespace and sends them periodically
+type Forwarder struct {
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+
+	mu     sync.Mutex
+	groups map[groupKey]*group
+}
+
+// groupKey is policy and namespace of violations
+type groupKey struct {
+	policy    string
+	namespace string
+}
+
+// group is violations of a policy in a namespace
+type group struct {
+	count int
+
+	// messages are last messages of violations by resource
+	messages map[string]string
+}
+
+// NewForwarder returns new instance of policy violation forwarder of
+// cluster
+func NewForwarder(
+	config *config.Config,
+	alertManager *alertmanager.AlertManager) *Forwarder {
+	return &Forwarder{
+		config:       config,
+		alertManager: alertManager,
+		groups:       make(map[groupKey]*group),
+	}
+}
+
+// Register adds handler of new violation events to informer, it has to be
+// called before informer is started. Events of initial list were seen
+// before kwatch started, so they aren't forwarded
+func (f *Forwarder) Register(inf *informer.Informer) {
+	if !f.config.PolicyViolations.Enabled {
+		return
+	}
+
+	handler := cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			ev, ok := obj.(*corev1.Event)
+			if !ok || isInInitialList {
+				return
+			}
+			f.Add(ev)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldEv, ok := oldObj.(*corev1.Event)
+			if !ok {
+				return
+			}
+			ev, ok := newObj.(*corev1.Event)
+			if !ok || ev.Count <= oldEv.Count {
+				return
+			}
+			f.Add(ev)
+		},
+	}
+	if _, err := inf.Events().AddEventHandler(handler); err != nil {
+		logrus.WithError(err).Error("failed to add policy event handler")
+	}
+}
+
+// Start sends grouped violations on configured interval until stopCh is
+// closed
+func (f *Forwarder) Start(stopCh <-chan struct{}) {
+	if !f.config.PolicyViolations.Enabled {
+		return
+	}
+
+	interval := f.config.PolicyViolations.Interval
+	if interval <= 0 {
+		interval = 5
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.Send()
+		case <-stopCh:
+			f.Send()
+			return
+		}
+	}
+}
+
+// Add groups event if it's a violation of a watched namespace
+func (f *Forwarder) Add(ev *corev1.Event) {
+	v := parseViolation(ev)
+	if v == nil || !f.config.IsNamespaceWatched(v.namespace) {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := groupKey{policy: v.policy, namespace: v.namespace}
+	g, ok := f.groups[key]
+	if !ok {
+		g = &group{messages: make(map[string]string)}
+		f.groups[key] = g
+	}
+	g.count++
+	g.messages[v.resource] = v.message
+}
+
+// Send sends grouped violations to configured providers, one message per
+// policy and namespace
+func (f *Forwarder) Send() {
+	f.mu.Lock()
+	groups := f.groups
+	f.groups = make(map[groupKey]*group)
+	f.mu.Unlock()
+
+	keys := make([]groupKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].policy != keys[j].policy {
+			return keys[i].policy < keys[j].policy
+		}
+		return keys[i].namespace < keys[j].namespace
+	})
+
+	for _, key := range keys {
+		f.alertManager.NotifyProviders(
+			f.config.PolicyViolations.Providers,
+			f.message(key, groups[key]))
+	}
+}
+
+// message returns violations of group, up to max resources are listed
+func (f *Forwarder) message(key groupKey, g *group) string {
+	namespace := key.namespace
+	if len(f.config.App.ClusterName) > 0 {
+		namespace += " of cluster " + f.config.App.ClusterName
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b,
+		constant.PolicyViolationsMsg,
+		g.count,
+		key.policy,
+		namespace)
+
+	resources := make([]string, 0, len(g.messages))
+	for resource := range g.messages {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	maxResources := f.config.PolicyViolations.MaxResources
+	if maxResources <= 0 {
+		maxResources = 10
+	}
+
+	for i, resource := range resources {
+		if i == maxResources {
+			fmt.Fprintf(&b,
+				"\n…and %d more resources",
+				len(resources)-maxResources)
+			break
+		}
+		fmt.Fprintf(&b, "\n• %s: %s", resource, g.messages[resource])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package policyviolation
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func newKyvernoEvent(kind, namespace, name string) *corev1.Event {
+	return &corev1.Event{
+		Reason: "PolicyViolation",
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+		},
+		Message: "policy require-labels/check-team fail: validation " +
+			"error: label team is required",
+	}
+}
+
+func newGatekeeperEvent(namespace, name string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gatekeeper-system",
+			Annotations: map[string]string{
+				"constraint_kind":    "K8sRequiredLabels",
+				"constraint_name":    "must-have-owner",
+				"resource_kind":      "Deployment",
+				"resource_namespace": namespace,
+				"resource_name":      name,
+			},
+		},
+		Reason:  "FailedAdmission",
+		Message: "Admission webhook denied request",
+	}
+}
+
+func TestSend(t *testing.T) {
+	assert := assert.New(t)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := config.DefaultConfig()
+	cfg.PolicyViolations.Enabled = true
+	cfg.PolicyViolations.MaxResources = 1
+	cfg.ForbiddenNamespaces = []string{"kube-system"}
+	cfg.App.ClusterName = "prod"
+
+	f := NewForwarder(cfg, alertManager)
+	f.Add(newKyvernoEvent("Pod", "default", "api-1"))
+	f.Add(newKyvernoEvent("Pod", "default", "api-1"))
+	f.Add(newKyvernoEvent("Pod", "default", "api-2"))
+	f.Add(newKyvernoEvent("ClusterPolicy", "", "require-labels"))
+	f.Add(newKyvernoEvent("Pod", "kube-system", "dns"))
+	f.Add(newGatekeeperEvent("default", "api"))
+	f.Add(&corev1.Event{Reason: "BackOff"})
+
+	f.Send()
+	assert.Equal([]string{
+		":no_entry: 1 violations of policy " +
+			"K8sRequiredLabels/must-have-owner in namespace default of " +
+			"cluster prod:\n" +
+			"• Deployment/api: Admission webhook denied request",
+		":no_entry: 3 violations of policy require-labels in namespace " +
+			"default of cluster prod:\n" +
+			"• Pod/api-1: policy require-labels/check-team fail: " +
+			"validation error: label team is required\n" +
+			"…and 1 more resources",
+	}, prv.messages)
+
+	// groups are reset once they're sent
+	prv.messages = nil
+	f.Send()
+	assert.Empty(prv.messages)
+}
+
+func TestParseViolation(t *testing.T) {
+	assert := assert.New(t)
+
+	ev := newKyvernoEvent("Deployment", "default", "api")
+	ev.Message = "validation failed"
+	assert.Equal(&violation{
+		policy:    "unknown",
+		namespace: "default",
+		resource:  "Deployment/api",
+		message:   "validation failed",
+	}, parseViolation(ev))
+
+	ev = newGatekeeperEvent("", "api")
+	ev.InvolvedObject.Namespace = "default"
+	delete(ev.Annotations, "constraint_kind")
+	assert.Equal(&violation{
+		policy:    "must-have-owner",
+		namespace: "default",
+		resource:  "Deployment/api",
+		message:   "Admission webhook denied request",
+	}, parseViolation(ev))
+
+	ev = newGatekeeperEvent("default", "api")
+	delete(ev.Annotations, "constraint_name")
+	assert.Nil(parseViolation(ev))
+}
@@ -0,0 +1,91 @@
+package policyviolation
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// gatekeeperReasons are reasons of Gatekeeper admission and audit events
+var gatekeeperReasons = []string{
+	"FailedAdmission",
+	"DryrunViolation",
+	"WarningAdmission",
+	"AuditViolation",
+}
+
+// kyvernoReason is reason of Kyverno violation events
+const kyvernoReason = "PolicyViolation"
+
+// violation is a violation of a policy by a resource
+type violation struct {
+	policy    string
+	namespace string
+	resource  string
+	message   string
+}
+
+// parseViolation returns violation of Gatekeeper or Kyverno event, or nil
+// if event isn't a violation
+func parseViolation(ev *corev1.Event) *violation {
+	if ev.Reason == kyvernoReason {
+		return parseKyvernoViolation(ev)
+	}
+
+	for _, reason := range gatekeeperReasons {
+		if ev.Reason == reason {
+			return parseGatekeeperViolation(ev)
+		}
+	}
+	return nil
+}
+
+// parseKyvernoViolation returns violation of Kyverno event of violating
+// resource, events of policies duplicate them so they're skipped. Messages
+// start with policy and rule e.g. policy require-labels/check-team fail:
+func parseKyvernoViolation(ev *corev1.Event) *violation {
+	kind := ev.InvolvedObject.Kind
+	if kind == "ClusterPolicy" || kind == "Policy" {
+		return nil
+	}
+
+	policy := "unknown"
+	message := ev.Message
+	if rest, ok := strings.CutPrefix(message, "policy "); ok {
+		name, _, _ := strings.Cut(rest, " ")
+		policy, _, _ = strings.Cut(name, "/")
+	}
+
+	return &violation{
+		policy:    policy,
+		namespace: ev.InvolvedObject.Namespace,
+		resource:  kind + "/" + ev.InvolvedObject.Name,
+		message:   message,
+	}
+}
+
+// parseGatekeeperViolation returns violation of Gatekeeper event using its
+// annotations of constraint and resource
+func parseGatekeeperViolation(ev *corev1.Event) *violation {
+	annotations := ev.Annotations
+	constraint := annotations["constraint_name"]
+	if len(constraint) == 0 {
+		return nil
+	}
+	if kind := annotations["constraint_kind"]; len(kind) > 0 {
+		constraint = kind + "/" + constraint
+	}
+
+	namespace := annotations["resource_namespace"]
+	if len(namespace) == 0 {
+		namespace = ev.InvolvedObject.Namespace
+	}
+
+	return &violation{
+		policy:    constraint,
+		namespace: namespace,
+		resource: annotations["resource_kind"] + "/" +
+			annotations["resource_name"],
+		message: ev.Message,
+	}
+}
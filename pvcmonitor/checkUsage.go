@@ -2,7 +2,9 @@ package pvcmonitor
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/abahmed/kwatch/metrics"
 	"github.com/abahmed/kwatch/util"
 	"github.com/sirupsen/logrus"
 )
@@ -16,6 +18,12 @@ type PvcUsage struct {
 }
 
 func (p *PvcMonitor) checkUsage() {
+	metrics.PvcChecks.Inc()
+	start := time.Now()
+	defer func() {
+		metrics.PvcCheckDuration.Observe(time.Since(start).Seconds())
+	}()
+
 	// getting nodes
 	nodes, err := util.GetNodes(p.client)
 	if err != nil {
@@ -35,10 +43,20 @@ func (p *PvcMonitor) checkUsage() {
 		pvcUsages = append(pvcUsages, nodePvcUsage...)
 	}
 
+	p.usagesMu.Lock()
+	p.usages = pvcUsages
+	p.usagesMu.Unlock()
+
 	for _, pvc := range pvcUsages {
+		// pvcs in namespaces of other shards are handled by other replicas
+		if !p.sharding.Owns(pvc.Namespace) {
+			continue
+		}
+
 		if pvc.UsagePercentage >= p.config.Threshold {
-			// ignore notified pv
-			if _, ok := p.notifiedPvc[pvc.PVName]; ok {
+			// ignore notified pv, unless reminder is due
+			notifiedAt, notified := p.notifiedPvc[pvc.PVName]
+			if notified && !p.isReminderDue(notifiedAt) {
 				continue
 			}
 
@@ -51,8 +69,22 @@ func (p *PvcMonitor) checkUsage() {
 				pvc.UsagePercentage,
 				p.config.Threshold,
 			)
+			if notified {
+				msg = "Reminder: " + msg
+			}
 			p.alertManager.Notify(msg)
-			p.notifiedPvc[pvc.PVName] = true
+			p.notifiedPvc[pvc.PVName] = time.Now()
 		}
 	}
 }
+
+// isReminderDue returns true if reminders are enabled and their interval
+// passed since pvc was notified
+func (p *PvcMonitor) isReminderDue(notifiedAt time.Time) bool {
+	if p.reminder == nil || !p.reminder.Enabled {
+		return false
+	}
+
+	interval := time.Duration(p.reminder.Interval) * time.Minute
+	return time.Since(notifiedAt) >= interval
+}
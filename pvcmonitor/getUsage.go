@@ -51,15 +51,14 @@ func (p *PvcMonitor) getNodeUsage(nodeName string) ([]*PvcUsage, error) {
 			}
 
 			pvName, err :=
-				util.GetPVNameFromPVC(
-					p.client,
+				p.informer.GetPVNameFromPVC(
 					pod.PodRef.Namespace,
 					vol.PvcRef.Name)
 			if err != nil {
-				logrus.Errorf(
-					"failed to get pv name for pvc %s: %s",
-					vol.PvcRef.Name,
-					err.Error())
+				logrus.WithFields(logrus.Fields{
+					"namespace": pod.PodRef.Namespace,
+					"pvc":       vol.PvcRef.Name,
+				}).WithError(err).Error("failed to get pv name for pvc")
 				continue
 			}
 
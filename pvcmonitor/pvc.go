@@ -1,33 +1,54 @@
 package pvcmonitor
 
 import (
+	"sync"
 	"time"
 
 	"github.com/abahmed/kwatch/alertmanager"
 	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/informer"
 	"k8s.io/client-go/kubernetes"
 )
 
 type PvcMonitor struct {
 	client       kubernetes.Interface
+	informer     *informer.Informer
 	config       *config.PvcMonitor
+	sharding     *config.Sharding
 	alertManager *alertmanager.AlertManager
-	notifiedPvc  map[string]bool
+	reminder     *config.Reminder
+
+	// notifiedPvc are times pvcs over threshold were last notified by
+	// volume name
+	notifiedPvc map[string]time.Time
+
+	usagesMu sync.RWMutex
+	usages   []*PvcUsage
 }
 
 // NewPvcMonitor returns new instance of pvc monitor
 func NewPvcMonitor(
 	client kubernetes.Interface,
+	inf *informer.Informer,
 	config *config.PvcMonitor,
+	sharding *config.Sharding,
 	alertManager *alertmanager.AlertManager) *PvcMonitor {
 	return &PvcMonitor{
 		client:       client,
+		informer:     inf,
 		config:       config,
+		sharding:     sharding,
 		alertManager: alertManager,
-		notifiedPvc:  make(map[string]bool),
+		notifiedPvc:  make(map[string]time.Time),
 	}
 }
 
+// SetReminder sets reminder config, pvcs still over threshold are notified
+// again on its interval if it's enabled
+func (p *PvcMonitor) SetReminder(reminder *config.Reminder) {
+	p.reminder = reminder
+}
+
 func (p *PvcMonitor) Start() {
 	if !p.config.Enabled {
 		return
@@ -43,3 +64,30 @@ func (p *PvcMonitor) Start() {
 		p.checkUsage()
 	}
 }
+
+// Check checks usage of pvcs once, e.g. by one-shot scan
+func (p *PvcMonitor) Check() {
+	if !p.config.Enabled {
+		return
+	}
+
+	p.checkUsage()
+}
+
+// Enabled returns true if pvc monitor is enabled
+func (p *PvcMonitor) Enabled() bool {
+	return p.config.Enabled
+}
+
+// Threshold returns usage percentage above which pvcs are reported
+func (p *PvcMonitor) Threshold() float64 {
+	return p.config.Threshold
+}
+
+// Usages returns pvc usages of last check
+func (p *PvcMonitor) Usages() []*PvcUsage {
+	p.usagesMu.RLock()
+	defer p.usagesMu.RUnlock()
+
+	return p.usages
+}
@@ -0,0 +1,217 @@
+package receiver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+)
+
+// maxPayloadSize is max size of accepted request bodies
+const maxPayloadSize = 1024 * 1024
+
+// statusResolved is status of resolved Alertmanager alerts
+const statusResolved = "resolved"
+
+// WebhookPayload is payload sent by Alertmanager webhook receivers
+type WebhookPayload struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []WebhookAlert    `json:"alerts"`
+}
+
+// WebhookAlert is an alert of Alertmanager webhook payload
+type WebhookAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+type AlertmanagerReceiver struct {
+	config       *config.AlertmanagerReceiver
+	alertManager *alertmanager.AlertManager
+}
+
+// NewAlertmanagerReceiver returns new instance of Alertmanager webhook
+// receiver
+func NewAlertmanagerReceiver(
+	config *config.AlertmanagerReceiver,
+	alertManager *alertmanager.AlertManager) *AlertmanagerReceiver {
+	return &AlertmanagerReceiver{
+		config:       config,
+		alertManager: alertManager,
+	}
+}
+
+// Enabled returns true if Alertmanager webhook receiver is enabled
+func (r *AlertmanagerReceiver) Enabled() bool {
+	return r.config.Enabled
+}
+
+// RegisterHandlers registers receiver endpoint using given handle function
+func (r *AlertmanagerReceiver) RegisterHandlers(
+	handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle("/api/v1/alertmanager", r.handleWebhook)
+}
+
+// handleWebhook sends alerts of Alertmanager webhook payload to providers
+func (r *AlertmanagerReceiver) handleWebhook(
+	w http.ResponseWriter,
+	req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !isAuthorized(req, r.config.Token) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	payload := &WebhookPayload{}
+	body := http.MaxBytesReader(w, req.Body, maxPayloadSize)
+	if err := json.NewDecoder(body).Decode(payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	sent := 0
+	for i := range payload.Alerts {
+		alert := &payload.Alerts[i]
+		if alert.Status == statusResolved && !r.config.SendResolved {
+			continue
+		}
+
+		r.alertManager.NotifyEvent(*NewAlertmanagerEvent(payload, alert))
+		sent++
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"receiver": payload.Receiver,
+		"alerts":   len(payload.Alerts),
+		"sent":     sent,
+	}).Info("received alertmanager webhook")
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// NewAlertmanagerEvent returns event of Alertmanager alert, pod, container,
+// namespace and cluster are taken from well known labels of the alert
+func NewAlertmanagerEvent(
+	payload *WebhookPayload,
+	alert *WebhookAlert) *event.Event {
+	labels := alert.Labels
+	annotations := alert.Annotations
+
+	reason := labels["alertname"]
+	if alert.Status == statusResolved {
+		reason += " (resolved)"
+	}
+
+	podName := labels["pod"]
+	if len(podName) == 0 {
+		podName = labels["instance"]
+	}
+
+	summary := annotations["summary"]
+	if len(summary) == 0 {
+		summary = annotations["description"]
+	}
+
+	details := make([]event.Field, 0, len(annotations)+2)
+	if severity := labels["severity"]; len(severity) > 0 {
+		details = append(details, event.Field{
+			Name:  "Severity",
+			Value: severity,
+		})
+	}
+	if !alert.StartsAt.IsZero() {
+		details = append(details, event.Field{
+			Name:  "Started At",
+			Value: alert.StartsAt.Format(time.RFC3339),
+		})
+	}
+	for _, name := range sortedKeys(annotations) {
+		if name == "summary" || annotations[name] == summary {
+			continue
+		}
+		details = append(details, event.Field{
+			Name:  titleCase(name),
+			Value: annotations[name],
+		})
+	}
+
+	links := make([]event.Field, 0, 2)
+	if len(alert.GeneratorURL) > 0 {
+		links = append(links, event.Field{
+			Name:  "Source",
+			Value: alert.GeneratorURL,
+		})
+	}
+	if len(payload.ExternalURL) > 0 {
+		links = append(links, event.Field{
+			Name:  "Alertmanager",
+			Value: payload.ExternalURL,
+		})
+	}
+
+	return &event.Event{
+		Cluster:       labels["cluster"],
+		PodName:       podName,
+		ContainerName: labels["container"],
+		Namespace:     labels["namespace"],
+		Reason:        reason,
+		Severity:      labels["severity"],
+		Labels:        labels,
+		Summary:       summary,
+		Details:       details,
+		Links:         links,
+	}
+}
+
+// isAuthorized returns true if token is not set or request has it as bearer
+// token
+func isAuthorized(req *http.Request, token string) bool {
+	if len(token) == 0 {
+		return true
+	}
+
+	given := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// sortedKeys returns keys of m sorted
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// titleCase returns name with its first letter upper cased e.g. runbook_url
+// to Runbook_url
+func titleCase(name string) string {
+	if len(name) == 0 {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,156 @@
+package receiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPayload = `{
+  "version": "4",
+  "status": "firing",
+  "receiver": "kwatch",
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {
+        "alertname": "KubePodCrashLooping",
+        "namespace": "default",
+        "pod": "api-0",
+        "container": "api",
+        "severity": "warning"
+      },
+      "annotations": {
+        "summary": "Pod is crash looping.",
+        "runbook_url": "https://runbooks/crashloop"
+      },
+      "startsAt": "2024-01-01T10:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "KubeNodeNotReady", "instance": "node-1"},
+      "annotations": {"description": "Node is not ready."}
+    }
+  ]
+}`
+
+type testProvider struct {
+	events []*event.Event
+}
+
+func (p *testProvider) Name() string {
+	return "test"
+}
+
+func (p *testProvider) SendEvent(ev *event.Event) error {
+	p.events = append(p.events, ev)
+	return nil
+}
+
+func (p *testProvider) SendMessage(msg string) error {
+	return nil
+}
+
+func newTestReceiver(
+	cfg *config.AlertmanagerReceiver) (*AlertmanagerReceiver, *testProvider) {
+	prv := &testProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, &config.App{})
+	alertManager.AddProvider(prv)
+	return NewAlertmanagerReceiver(cfg, alertManager), prv
+}
+
+func TestHandleWebhook(t *testing.T) {
+	assert := assert.New(t)
+
+	r, prv := newTestReceiver(&config.AlertmanagerReceiver{Enabled: true})
+
+	rec := httptest.NewRecorder()
+	r.handleWebhook(rec, httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/alertmanager",
+		strings.NewReader(testPayload)))
+	assert.Equal(http.StatusAccepted, rec.Code)
+
+	assert.Len(prv.events, 1)
+	ev := prv.events[0]
+	assert.Equal("api-0", ev.PodName)
+	assert.Equal("api", ev.ContainerName)
+	assert.Equal("default", ev.Namespace)
+	assert.Equal("KubePodCrashLooping", ev.Reason)
+	assert.Equal("Pod is crash looping.", ev.Summary)
+	assert.Contains(ev.Details, event.Field{
+		Name:  "Runbook_url",
+		Value: "https://runbooks/crashloop",
+	})
+	assert.Contains(ev.Details, event.Field{Name: "Severity", Value: "warning"})
+	assert.Contains(ev.Links, event.Field{
+		Name:  "Source",
+		Value: "http://prometheus:9090/graph",
+	})
+}
+
+func TestHandleWebhookResolved(t *testing.T) {
+	assert := assert.New(t)
+
+	r, prv := newTestReceiver(&config.AlertmanagerReceiver{
+		Enabled:      true,
+		SendResolved: true,
+	})
+
+	rec := httptest.NewRecorder()
+	r.handleWebhook(rec, httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/alertmanager",
+		strings.NewReader(testPayload)))
+	assert.Equal(http.StatusAccepted, rec.Code)
+
+	assert.Len(prv.events, 2)
+	ev := prv.events[1]
+	assert.Equal("node-1", ev.PodName)
+	assert.Equal("KubeNodeNotReady (resolved)", ev.Reason)
+	assert.Equal("Node is not ready.", ev.Summary)
+	assert.Empty(ev.Details)
+}
+
+func TestHandleWebhookInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	r, prv := newTestReceiver(&config.AlertmanagerReceiver{
+		Enabled: true,
+		Token:   "secret",
+	})
+
+	rec := httptest.NewRecorder()
+	r.handleWebhook(rec, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/alertmanager",
+		nil))
+	assert.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.handleWebhook(rec, httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/alertmanager",
+		strings.NewReader(testPayload)))
+	assert.Equal(http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/alertmanager",
+		strings.NewReader("{"))
+	req.Header.Set("Authorization", "Bearer secret")
+	r.handleWebhook(rec, req)
+	assert.Equal(http.StatusBadRequest, rec.Code)
+
+	assert.Empty(prv.events)
+}
@@ -0,0 +1,147 @@
+package receiver
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// InboundAlert is a custom alert posted by other tools
+type InboundAlert struct {
+	Title     string            `json:"title"`
+	Severity  string            `json:"severity"`
+	Body      string            `json:"body"`
+	Labels    map[string]string `json:"labels"`
+	Source    string            `json:"source"`
+	Namespace string            `json:"namespace"`
+}
+
+type InboundReceiver struct {
+	config       *config.InboundAlerts
+	alertManager *alertmanager.AlertManager
+	limiter      *rate.Limiter
+}
+
+// NewInboundReceiver returns new instance of inbound alerts receiver
+func NewInboundReceiver(
+	config *config.InboundAlerts,
+	alertManager *alertmanager.AlertManager) *InboundReceiver {
+	limit := rate.Inf
+	if config.RateLimit > 0 {
+		limit = rate.Limit(float64(config.RateLimit) / 60)
+	}
+
+	return &InboundReceiver{
+		config:       config,
+		alertManager: alertManager,
+		limiter:      rate.NewLimiter(limit, config.Burst),
+	}
+}
+
+// Enabled returns true if inbound alerts are enabled
+func (r *InboundReceiver) Enabled() bool {
+	return r.config.Enabled
+}
+
+// RegisterHandlers registers inbound alerts endpoint using given handle
+// function
+func (r *InboundReceiver) RegisterHandlers(
+	handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle("/api/v1/alerts/inbound", r.handleAlert)
+}
+
+// handleAlert sends posted alert to providers
+func (r *InboundReceiver) handleAlert(
+	w http.ResponseWriter,
+	req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// token is always required, so alerts can't be sent by anyone reaching
+	// the server
+	if len(r.config.Token) == 0 || !isAuthorized(req, r.config.Token) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	alert := &InboundAlert{}
+	body := http.MaxBytesReader(w, req.Body, maxPayloadSize)
+	if err := json.NewDecoder(body).Decode(alert); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if len(alert.Title) == 0 {
+		http.Error(w, "title is required", http.StatusBadRequest)
+		return
+	}
+
+	reservation := r.limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		retryAfter := int(math.Ceil(delay.Seconds()))
+		if delay == rate.InfDuration {
+			retryAfter = int(time.Minute.Seconds())
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+
+		logrus.WithField("source", alert.Source).
+			Warn("dropping inbound alert as rate limit is exceeded")
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"source":   alert.Source,
+		"title":    alert.Title,
+		"severity": alert.Severity,
+	}).Info("received inbound alert")
+
+	r.alertManager.NotifyEvent(*NewInboundEvent(alert))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// NewInboundEvent returns event of inbound alert, pod, container and
+// cluster are taken from well known labels of the alert
+func NewInboundEvent(alert *InboundAlert) *event.Event {
+	namespace := alert.Namespace
+	if len(namespace) == 0 {
+		namespace = alert.Labels["namespace"]
+	}
+
+	details := make([]event.Field, 0, 2)
+	if len(alert.Severity) > 0 {
+		details = append(details, event.Field{
+			Name:  "Severity",
+			Value: alert.Severity,
+		})
+	}
+	if len(alert.Source) > 0 {
+		details = append(details, event.Field{
+			Name:  "Source",
+			Value: alert.Source,
+		})
+	}
+
+	return &event.Event{
+		Cluster:       alert.Labels["cluster"],
+		PodName:       alert.Labels["pod"],
+		ContainerName: alert.Labels["container"],
+		Namespace:     namespace,
+		Reason:        alert.Title,
+		Severity:      alert.Severity,
+		Labels:        alert.Labels,
+		Summary:       alert.Body,
+		Details:       details,
+	}
+}
@@ -0,0 +1,120 @@
+package receiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestInboundReceiver(
+	cfg *config.InboundAlerts) (*InboundReceiver, *testProvider) {
+	prv := &testProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, &config.App{})
+	alertManager.AddProvider(prv)
+	return NewInboundReceiver(cfg, alertManager), prv
+}
+
+func postAlert(r *InboundReceiver, token, body string) int {
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/alerts/inbound",
+		strings.NewReader(body))
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	r.handleAlert(rec, req)
+	return rec.Code
+}
+
+func TestHandleAlert(t *testing.T) {
+	assert := assert.New(t)
+
+	r, prv := newTestInboundReceiver(&config.InboundAlerts{
+		Enabled:   true,
+		Token:     "secret",
+		RateLimit: 60,
+		Burst:     10,
+	})
+
+	code := postAlert(r, "secret", `{
+		"title": "Backup failed",
+		"severity": "critical",
+		"body": "nightly backup failed",
+		"source": "backup-job",
+		"labels": {"namespace": "db", "team": "storage"}
+	}`)
+	assert.Equal(http.StatusAccepted, code)
+
+	assert.Len(prv.events, 1)
+	ev := prv.events[0]
+	assert.Equal("Backup failed", ev.Reason)
+	assert.Equal("nightly backup failed", ev.Summary)
+	assert.Equal("db", ev.Namespace)
+	assert.Equal("storage", ev.Labels["team"])
+	assert.Equal([]event.Field{
+		{Name: "Severity", Value: "critical"},
+		{Name: "Source", Value: "backup-job"},
+	}, ev.Details)
+}
+
+func TestHandleAlertInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	r, prv := newTestInboundReceiver(&config.InboundAlerts{
+		Enabled:   true,
+		Token:     "secret",
+		RateLimit: 60,
+		Burst:     10,
+	})
+
+	assert.Equal(
+		http.StatusUnauthorized,
+		postAlert(r, "", `{"title": "test"}`))
+	assert.Equal(
+		http.StatusUnauthorized,
+		postAlert(r, "wrong", `{"title": "test"}`))
+	assert.Equal(http.StatusBadRequest, postAlert(r, "secret", `{`))
+	assert.Equal(http.StatusBadRequest, postAlert(r, "secret", `{}`))
+
+	rec := httptest.NewRecorder()
+	r.handleAlert(rec, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/alerts/inbound",
+		nil))
+	assert.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+	// token is required even if it's not configured
+	r, _ = newTestInboundReceiver(&config.InboundAlerts{Enabled: true})
+	assert.Equal(
+		http.StatusUnauthorized,
+		postAlert(r, "", `{"title": "test"}`))
+
+	assert.Empty(prv.events)
+}
+
+func TestHandleAlertRateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	r, prv := newTestInboundReceiver(&config.InboundAlerts{
+		Enabled:   true,
+		Token:     "secret",
+		RateLimit: 1,
+		Burst:     2,
+	})
+
+	assert.Equal(http.StatusAccepted, postAlert(r, "secret", `{"title": "1"}`))
+	assert.Equal(http.StatusAccepted, postAlert(r, "secret", `{"title": "2"}`))
+	assert.Equal(
+		http.StatusTooManyRequests,
+		postAlert(r, "secret", `{"title": "3"}`))
+	assert.Len(prv.events, 2)
+}
@@ -0,0 +1,176 @@
+package reminder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+)
+
+// checkInterval is how often ongoing failures are checked
+const checkInterval = time.Minute
+
+// Status is current status of a failure
+type Status struct {
+	// Resolved is true if failure recovered or it was silenced
+	Resolved bool
+
+	// Restarts is current restart count of failing container
+	Restarts int32
+}
+
+type Reminder struct {
+	config       *config.Reminder
+	alertManager *alertmanager.AlertManager
+
+	mu sync.Mutex
+
+	// ongoing are failures reminders are sent of by failing container
+	ongoing map[string]*failure
+
+	// now returns current time, it's replaced in tests
+	now func() time.Time
+}
+
+// failure is an ongoing failure whose first alert was sent
+type failure struct {
+	event    event.Event
+	since    time.Time
+	lastSent time.Time
+	restarts int32
+
+	// status returns current status of failure
+	status func() *Status
+}
+
+// NewReminder returns new instance of reminder
+func NewReminder(
+	config *config.Reminder,
+	alertManager *alertmanager.AlertManager) *Reminder {
+	return &Reminder{
+		config:       config,
+		alertManager: alertManager,
+		ongoing:      make(map[string]*failure),
+		now:          time.Now,
+	}
+}
+
+// Enabled returns true if reminders are enabled
+func (r *Reminder) Enabled() bool {
+	return r != nil && r.config.Enabled
+}
+
+// Add tracks failure of sent alert until status reports it's resolved,
+// restarts is restart count of failing container when alert was sent.
+// Repeated alerts of the same container keep tracked failure
+func (r *Reminder) Add(
+	ev *event.Event,
+	restarts int32,
+	status func() *Status) {
+	if !r.Enabled() {
+		return
+	}
+
+	key := getKey(ev)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.ongoing[key]; ok {
+		return
+	}
+
+	now := r.now()
+	r.ongoing[key] = &failure{
+		event:    *ev,
+		since:    now,
+		lastSent: now,
+		restarts: restarts,
+		status:   status,
+	}
+}
+
+// Start sends due reminders until stopCh is closed
+func (r *Reminder) Start(stopCh <-chan struct{}) {
+	if !r.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.Check()
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// Check sends reminders of due failures, resolved ones are forgotten
+func (r *Reminder) Check() {
+	now := r.now()
+	interval := time.Duration(r.config.Interval) * time.Minute
+
+	r.mu.Lock()
+	due := make(map[string]*failure)
+	for key, f := range r.ongoing {
+		if now.Sub(f.lastSent) >= interval {
+			due[key] = f
+		}
+	}
+	r.mu.Unlock()
+
+	for key, f := range due {
+		status := f.status()
+		if status == nil || status.Resolved {
+			r.mu.Lock()
+			delete(r.ongoing, key)
+			r.mu.Unlock()
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"namespace": f.event.Namespace,
+			"pod":       f.event.PodName,
+			"container": f.event.ContainerName,
+		}).Info("sending reminder of ongoing failure")
+
+		r.alertManager.NotifyEvent(remind(f, status, now))
+
+		r.mu.Lock()
+		f.lastSent = now
+		r.mu.Unlock()
+	}
+}
+
+// remind returns copy of failure event with its duration and restarts
+// since first alert
+func remind(f *failure, status *Status, now time.Time) event.Event {
+	ev := f.event
+	ev.Details = append(
+		append([]event.Field{}, ev.Details...),
+		event.Field{
+			Name: "Reminder",
+			Value: fmt.Sprintf(
+				"failing for %s, %d restarts since first alert",
+				now.Sub(f.since).Round(time.Minute),
+				status.Restarts-f.restarts),
+		})
+	return ev
+}
+
+// getKey returns key of failing container of event
+func getKey(ev *event.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%s",
+		ev.Cluster,
+		ev.Namespace,
+		ev.PodName,
+		ev.ContainerName)
+}
@@ -0,0 +1,79 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewReminder(&config.Reminder{Interval: 60}, nil)
+	r.Add(&event.Event{PodName: "api"}, 0, nil)
+	assert.Len(r.ongoing, 0)
+
+	r.config.Enabled = true
+	r.Add(&event.Event{PodName: "api"}, 3, nil)
+	r.Add(&event.Event{PodName: "api"}, 5, nil)
+	assert.Len(r.ongoing, 1)
+	assert.Equal(int32(3), r.ongoing["//api/"].restarts)
+
+	var nilReminder *Reminder
+	nilReminder.Add(&event.Event{PodName: "api"}, 0, nil)
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	am := &alertmanager.AlertManager{}
+	am.Init(nil, &config.App{DryRun: true})
+
+	now := time.Now()
+	r := NewReminder(&config.Reminder{Enabled: true, Interval: 60}, am)
+	r.now = func() time.Time { return now }
+
+	resolved := false
+	statusChecks := 0
+	r.Add(&event.Event{PodName: "api"}, 3, func() *Status {
+		statusChecks++
+		return &Status{Resolved: resolved, Restarts: 10}
+	})
+
+	now = now.Add(30 * time.Minute)
+	r.Check()
+	assert.Equal(0, statusChecks)
+
+	now = now.Add(30 * time.Minute)
+	r.Check()
+	assert.Equal(1, statusChecks)
+	assert.Equal(now, r.ongoing["//api/"].lastSent)
+
+	resolved = true
+	now = now.Add(60 * time.Minute)
+	r.Check()
+	assert.Equal(2, statusChecks)
+	assert.Len(r.ongoing, 0)
+}
+
+func TestRemind(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	f := &failure{
+		event:    event.Event{PodName: "api"},
+		since:    now.Add(-90 * time.Minute),
+		restarts: 3,
+	}
+
+	ev := remind(f, &Status{Restarts: 10}, now)
+	assert.Len(ev.Details, 1)
+	assert.Equal(
+		"failing for 1h30m0s, 7 restarts since first alert",
+		ev.Details[0].Value)
+	assert.Len(f.event.Details, 0)
+}
@@ -0,0 +1,169 @@
+package rule
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+// maxCooldowns is number of cooldowns kept before expired ones are removed
+const maxCooldowns = 1000
+
+// Router routes alerts using first matching KwatchRule, rules are ordered by
+// namespace and name
+type Router struct {
+	mu    sync.RWMutex
+	rules []*KwatchRule
+
+	// cooldowns keeps when cooldowns of rules and alerts expire
+	cooldownsMu sync.Mutex
+	cooldowns   map[string]time.Time
+}
+
+// NewRouter returns new instance of router without rules
+func NewRouter() *Router {
+	return &Router{
+		rules:     make([]*KwatchRule, 0),
+		cooldowns: make(map[string]time.Time),
+	}
+}
+
+// SetRules replaces rules of router
+func (r *Router) SetRules(rules []*KwatchRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Namespace != rules[j].Namespace {
+			return rules[i].Namespace < rules[j].Namespace
+		}
+		return rules[i].Name < rules[j].Name
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = rules
+}
+
+// Route returns route of event using first matching rule, nil is returned
+// if no rule matches
+func (r *Router) Route(ev *event.Event) *alertmanager.Route {
+	rule := r.match(ev)
+	if rule == nil {
+		return nil
+	}
+
+	logger := logrus.WithFields(logrus.Fields{
+		"rule":      rule.Namespace + "/" + rule.Name,
+		"namespace": ev.Namespace,
+		"pod":       ev.PodName,
+	})
+
+	if rule.Spec.Drop {
+		logger.Debug("event is dropped by rule")
+		return &alertmanager.Route{Drop: true}
+	}
+
+	if r.inCooldown(rule, ev) {
+		logger.Debug("event is in cooldown of rule")
+		return &alertmanager.Route{Drop: true}
+	}
+
+	return &alertmanager.Route{
+		Providers: rule.Spec.Providers,
+		Sections:  rule.Spec.Sections,
+	}
+}
+
+// match returns first rule matching event
+func (r *Router) match(ev *event.Event) *KwatchRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rule := range r.rules {
+		if rule.Spec.Match.Matches(ev) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// inCooldown returns true if an alert of the same pod, container and reason
+// was sent by rule within its cooldown, otherwise it starts a cooldown
+func (r *Router) inCooldown(rule *KwatchRule, ev *event.Event) bool {
+	if rule.Spec.Cooldown <= 0 {
+		return false
+	}
+
+	key := rule.Namespace + "/" + rule.Name + "/" +
+		ev.Cluster + "/" +
+		ev.Namespace + "/" +
+		ev.PodName + "/" +
+		ev.ContainerName + "/" +
+		ev.Reason
+
+	r.cooldownsMu.Lock()
+	defer r.cooldownsMu.Unlock()
+
+	now := time.Now()
+	if expiry, ok := r.cooldowns[key]; ok && now.Before(expiry) {
+		return true
+	}
+
+	if len(r.cooldowns) >= maxCooldowns {
+		for k, expiry := range r.cooldowns {
+			if !now.Before(expiry) {
+				delete(r.cooldowns, k)
+			}
+		}
+	}
+
+	r.cooldowns[key] =
+		now.Add(time.Duration(rule.Spec.Cooldown) * time.Minute)
+	return false
+}
+
+// Start watches KwatchRule resources in namespace, or all namespaces if
+// it's empty, and applies them on every change until stopCh is closed. It
+// returns false if rules can't be synced
+func (r *Router) Start(
+	client dynamic.Interface,
+	namespace string,
+	stopCh <-chan struct{}) bool {
+	return informer.WatchResources(
+		client,
+		GroupVersionResource,
+		namespace,
+		stopCh,
+		func(objects []*unstructured.Unstructured) {
+			rules := fromObjects(objects)
+			r.SetRules(rules)
+			logrus.WithField("rules", len(rules)).
+				Info("applied routing rules")
+		})
+}
+
+// fromObjects returns rules of unstructured objects, invalid ones are
+// skipped
+func fromObjects(objects []*unstructured.Unstructured) []*KwatchRule {
+	rules := make([]*KwatchRule, 0, len(objects))
+	for _, obj := range objects {
+		rule := &KwatchRule{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+			obj.Object,
+			rule)
+		if err != nil {
+			logrus.WithField("rule", obj.GetNamespace()+"/"+obj.GetName()).
+				WithError(err).
+				Warn("skipping invalid rule")
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
@@ -0,0 +1,107 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newTestRule(name string, spec Spec) *KwatchRule {
+	return &KwatchRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kwatch"},
+		Spec:       spec,
+	}
+}
+
+func TestRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	router := NewRouter()
+	router.SetRules([]*KwatchRule{
+		newTestRule("b-payments", Spec{
+			Match:     Match{Namespaces: []string{"payments"}},
+			Providers: []string{"slack"},
+			Sections:  []string{"logs"},
+		}),
+		newTestRule("a-drop-completed", Spec{
+			Match: Match{Reasons: []string{"Completed"}},
+			Drop:  true,
+		}),
+	})
+
+	assert.Nil(router.Route(&event.Event{Namespace: "default"}))
+
+	assert.Equal(
+		&alertmanager.Route{
+			Providers: []string{"slack"},
+			Sections:  []string{"logs"},
+		},
+		router.Route(&event.Event{Namespace: "payments", Reason: "Error"}))
+
+	// rules are ordered by name, so drop rule matches first
+	assert.Equal(
+		&alertmanager.Route{Drop: true},
+		router.Route(&event.Event{
+			Namespace: "payments",
+			Reason:    "Completed",
+		}))
+}
+
+func TestRouteCooldown(t *testing.T) {
+	assert := assert.New(t)
+
+	router := NewRouter()
+	router.SetRules([]*KwatchRule{
+		newTestRule("cooldown", Spec{Cooldown: 10}),
+	})
+
+	ev := &event.Event{Namespace: "default", PodName: "api", Reason: "Error"}
+	assert.False(router.Route(ev).Drop)
+	assert.True(router.Route(ev).Drop)
+
+	other := &event.Event{Namespace: "default", PodName: "db", Reason: "Error"}
+	assert.False(router.Route(other).Drop)
+}
+
+func TestStart(t *testing.T) {
+	assert := assert.New(t)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": Group + "/" + Version,
+		"kind":       "KwatchRule",
+		"metadata": map[string]interface{}{
+			"name":      "payments",
+			"namespace": "kwatch",
+		},
+		"spec": map[string]interface{}{
+			"match": map[string]interface{}{
+				"namespaces": []interface{}{"payments"},
+			},
+			"providers": []interface{}{"pagerduty"},
+		},
+	}}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			GroupVersionResource: "KwatchRuleList",
+		},
+		obj)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	router := NewRouter()
+	assert.True(router.Start(client, "", stopCh))
+
+	route := router.Route(&event.Event{Namespace: "payments"})
+	assert.NotNil(route)
+	assert.Equal([]string{"pagerduty"}, route.Providers)
+}
@@ -0,0 +1,88 @@
+package rule
+
+import (
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	Group    = "kwatch.dev"
+	Version  = "v1alpha1"
+	Resource = "kwatchrules"
+)
+
+// GroupVersionResource of KwatchRule resources
+var GroupVersionResource = schema.GroupVersionResource{
+	Group:    Group,
+	Version:  Version,
+	Resource: Resource,
+}
+
+// KwatchRule is a custom resource routing and filtering alerts
+type KwatchRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec Spec `json:"spec"`
+}
+
+// Spec of KwatchRule
+type Spec struct {
+	// Match selects alerts the rule applies to
+	Match Match `json:"match"`
+
+	// Drop if set to true, matched alerts aren't sent
+	Drop bool `json:"drop,omitempty"`
+
+	// Providers are names of providers matched alerts are sent to e.g.
+	// slack, if it's empty alerts are sent to all providers
+	Providers []string `json:"providers,omitempty"`
+
+	// Sections overrides message sections of providers for matched alerts
+	Sections []string `json:"sections,omitempty"`
+
+	// Cooldown (in minutes) during which repeated alerts of the same pod,
+	// container and reason aren't sent
+	Cooldown int `json:"cooldown,omitempty"`
+}
+
+// Match is criteria of alerts, all non-empty criteria must match
+type Match struct {
+	// Namespaces of pods, any of them must match
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Labels of pods, all of them must match
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Reasons of failures e.g. OOMKilled, any of them must match
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Severities of alerts received from other tools, any of them must
+	// match
+	Severities []string `json:"severities,omitempty"`
+}
+
+// Matches returns true if event matches criteria
+func (m *Match) Matches(ev *event.Event) bool {
+	if len(m.Namespaces) > 0 && !util.ContainsFold(m.Namespaces, ev.Namespace) {
+		return false
+	}
+
+	for key, value := range m.Labels {
+		if v, ok := ev.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	if len(m.Reasons) > 0 && !util.ContainsFold(m.Reasons, ev.Reason) {
+		return false
+	}
+
+	if len(m.Severities) > 0 && !util.ContainsFold(m.Severities, ev.Severity) {
+		return false
+	}
+
+	return true
+}
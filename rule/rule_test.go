@@ -0,0 +1,39 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	ev := &event.Event{
+		Namespace: "payments",
+		Reason:    "OOMKilled",
+		Labels:    map[string]string{"team": "payments", "tier": "api"},
+	}
+
+	assert.True((&Match{}).Matches(ev))
+	assert.True((&Match{Namespaces: []string{"default", "payments"}}).
+		Matches(ev))
+	assert.False((&Match{Namespaces: []string{"default"}}).Matches(ev))
+	assert.True((&Match{Labels: map[string]string{"team": "payments"}}).
+		Matches(ev))
+	assert.False((&Match{Labels: map[string]string{"team": "search"}}).
+		Matches(ev))
+	assert.False((&Match{Labels: map[string]string{"owner": "x"}}).
+		Matches(ev))
+	assert.True((&Match{Reasons: []string{"oomkilled"}}).Matches(ev))
+	assert.False((&Match{Reasons: []string{"Error"}}).Matches(ev))
+	assert.False((&Match{Severities: []string{"critical"}}).Matches(ev))
+
+	ev.Severity = "Critical"
+	assert.True((&Match{
+		Namespaces: []string{"payments"},
+		Reasons:    []string{"OOMKilled"},
+		Severities: []string{"critical"},
+	}).Matches(ev))
+}
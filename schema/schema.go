@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+)
+
+// Draft is JSON Schema version of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
+
+// Generate returns JSON Schema of kwatch config generated from config
+// structs, default values are taken from default config
+func Generate() *Schema {
+	s := fromType(
+		reflect.TypeOf(config.Config{}),
+		reflect.ValueOf(*config.DefaultConfig()))
+	s.Schema = Draft
+	s.Title = "kwatch config"
+	s.Properties["alert"] = alertSchema()
+	return s
+}
+
+// fromType returns schema of type t, non-zero values of def are set as
+// defaults
+func fromType(t reflect.Type, def reflect.Value) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return fromType(t.Elem(), reflect.Value{})
+	case reflect.Struct:
+		return fromStruct(t, def)
+	case reflect.Slice, reflect.Array:
+		return &Schema{
+			Type:  "array",
+			Items: fromType(t.Elem(), reflect.Value{}),
+		}
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: fromType(t.Elem(), reflect.Value{}),
+		}
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Default: defaultOf(def)}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Default: defaultOf(def)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Default: defaultOf(def)}
+	case reflect.String:
+		return &Schema{Type: "string", Default: defaultOf(def)}
+	}
+
+	// interface values accept anything
+	return &Schema{}
+}
+
+// fromStruct returns schema of struct fields having yaml tags, fields
+// without tags are computed when config is loaded
+func fromStruct(t reflect.Type, def reflect.Value) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if len(name) == 0 || name == "-" {
+			continue
+		}
+
+		var fieldDef reflect.Value
+		if def.IsValid() {
+			fieldDef = def.Field(i)
+		}
+		s.Properties[name] = fromType(field.Type, fieldDef)
+	}
+
+	return s
+}
+
+// defaultOf returns value of def if it's not zero
+func defaultOf(def reflect.Value) interface{} {
+	if !def.IsValid() || def.IsZero() {
+		return nil
+	}
+	return def.Interface()
+}
+
+// alertSchema returns schema of providers config using their options
+func alertSchema() *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	for name := range alertmanager.ProviderOptions {
+		provider := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: false,
+		}
+
+		for _, option := range alertmanager.GetProviderOptions(name) {
+			provider.Properties[option.Name] = &Schema{Type: option.Type}
+			if option.Required {
+				provider.Required = append(provider.Required, option.Name)
+			}
+		}
+		sort.Strings(provider.Required)
+
+		s.Properties[name] = provider
+	}
+
+	return s
+}
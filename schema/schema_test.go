@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	assert := assert.New(t)
+
+	s := Generate()
+	assert.Equal(Draft, s.Schema)
+	assert.Equal("object", s.Type)
+	assert.Equal(false, s.AdditionalProperties)
+
+	// fields without yaml tags aren't part of config file
+	assert.NotContains(s.Properties, "Hash")
+	assert.NotContains(s.Properties, "allowednamespaces")
+
+	app := s.Properties["app"]
+	assert.Equal("object", app.Type)
+	assert.Equal("boolean", app.Properties["disableStartupMessage"].Type)
+
+	namespaces := s.Properties["namespaces"]
+	assert.Equal("array", namespaces.Type)
+	assert.Equal("string", namespaces.Items.Type)
+
+	// defaults are taken from default config
+	assert.Equal(1000, s.Properties["history"].Properties["maxEntries"].Default)
+	assert.Nil(s.Properties["history"].Properties["enabled"].Default)
+
+	slack := s.Properties["alert"].Properties["slack"]
+	assert.Equal([]string{"webhook"}, slack.Required)
+	assert.Equal("string", slack.Properties["channelId"].Type)
+	assert.Equal("array", slack.Properties["sections"].Type)
+}
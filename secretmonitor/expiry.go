@@ -0,0 +1,71 @@
+package secretmonitor
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getExpiry returns subject of expiry e.g. Certificate and expiry time of
+// secret, zero time is returned if it doesn't expire
+func getExpiry(secret *corev1.Secret) (string, time.Time, error) {
+	switch secret.Type {
+	case corev1.SecretTypeTLS:
+		expiry, err := getCertificateExpiry(
+			secret.Data[corev1.TLSCertKey])
+		return "Certificate", expiry, err
+	case corev1.SecretTypeServiceAccountToken:
+		expiry, err := getTokenExpiry(
+			secret.Data[corev1.ServiceAccountTokenKey])
+		return "Service account token", expiry, err
+	}
+	return "", time.Time{}, nil
+}
+
+// getCertificateExpiry returns expiry of first certificate of PEM chain,
+// which is certificate of the server
+func getCertificateExpiry(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, errors.New("no certificate found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// getTokenExpiry returns expiry of JWT token from its exp claim, legacy
+// tokens without it don't expire
+func getTokenExpiry(data []byte) (time.Time, error) {
+	parts := strings.Split(string(data), ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("token isn't a jwt")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(
+		strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, err
+	}
+
+	if claims.Exp == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
@@ -0,0 +1,186 @@
+package secretmonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// checkedTypes are types of checked secrets
+var checkedTypes = []corev1.SecretType{
+	corev1.SecretTypeTLS,
+	corev1.SecretTypeServiceAccountToken,
+}
+
+type SecretMonitor struct {
+	client       kubernetes.Interface
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+
+	// notified are expiry times of last notification by secret uid, so
+	// expiry is notified once when secret approaches it and once when it's
+	// reached, renewed secrets are notified again
+	notified map[string]notification
+}
+
+// notification is last notification of a secret
+type notification struct {
+	expiry  time.Time
+	expired bool
+}
+
+// NewSecretMonitor returns new instance of secret expiry monitor of cluster
+func NewSecretMonitor(
+	client kubernetes.Interface,
+	config *config.Config,
+	alertManager *alertmanager.AlertManager) *SecretMonitor {
+	return &SecretMonitor{
+		client:       client,
+		config:       config,
+		alertManager: alertManager,
+		notified:     make(map[string]notification),
+	}
+}
+
+// Start checks secrets at startup and on configured interval
+func (m *SecretMonitor) Start() {
+	if !m.config.SecretExpiry.Enabled {
+		return
+	}
+
+	// check at startup
+	m.Check(time.Now())
+
+	interval := m.config.SecretExpiry.Interval
+	if interval <= 0 {
+		interval = 6
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Hour)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		m.Check(now)
+	}
+}
+
+// Check notifies secrets whose certificate or token expires within lead
+// time or expired
+func (m *SecretMonitor) Check(now time.Time) {
+	leadTime := time.Duration(m.config.SecretExpiry.LeadTime) * time.Hour
+	if leadTime <= 0 {
+		leadTime = 168 * time.Hour
+	}
+
+	seen := make(map[string]bool)
+	for _, secretType := range checkedTypes {
+		secrets, err := m.client.CoreV1().
+			Secrets(metav1.NamespaceAll).
+			List(context.TODO(), metav1.ListOptions{
+				FieldSelector: "type=" + string(secretType),
+			})
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"cluster": m.config.App.ClusterName,
+				"type":    secretType,
+			}).WithError(err).Warn("failed to list secrets")
+
+			// secrets which can't be listed aren't forgotten
+			for uid := range m.notified {
+				seen[uid] = true
+			}
+			continue
+		}
+
+		for i := range secrets.Items {
+			secret := &secrets.Items[i]
+			if secret.Type != secretType || !m.isWatched(secret) {
+				continue
+			}
+			seen[string(secret.UID)] = true
+			m.checkSecret(secret, now, leadTime)
+		}
+	}
+
+	// deleted secrets are forgotten
+	for uid := range m.notified {
+		if !seen[uid] {
+			delete(m.notified, uid)
+		}
+	}
+}
+
+// checkSecret notifies secret if it expires within lead time, once before
+// and once after expiry
+func (m *SecretMonitor) checkSecret(
+	secret *corev1.Secret,
+	now time.Time,
+	leadTime time.Duration) {
+	subject, expiry, err := getExpiry(secret)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"namespace": secret.Namespace,
+			"secret":    secret.Name,
+		}).WithError(err).Debug("failed to get expiry of secret")
+		return
+	}
+	if expiry.IsZero() || now.Add(leadTime).Before(expiry) {
+		return
+	}
+
+	uid := string(secret.UID)
+	expired := !now.Before(expiry)
+	last, ok := m.notified[uid]
+	if ok && last.expiry.Equal(expiry) && last.expired == expired {
+		return
+	}
+	m.notified[uid] = notification{expiry: expiry, expired: expired}
+
+	name := secret.Name
+	if len(m.config.App.ClusterName) > 0 {
+		name += " of cluster " + m.config.App.ClusterName
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": secret.Namespace,
+		"secret":    secret.Name,
+		"expiry":    expiry,
+	}).Warn("secret approaches expiry")
+
+	if expired {
+		m.alertManager.Notify(fmt.Sprintf(
+			constant.SecretExpiredMsg,
+			subject,
+			name,
+			secret.Namespace,
+			expiry.UTC().Format(time.RFC3339)))
+		return
+	}
+
+	m.alertManager.Notify(fmt.Sprintf(
+		constant.SecretExpiringMsg,
+		subject,
+		name,
+		secret.Namespace,
+		expiry.UTC().Format(time.RFC3339),
+		expiry.Sub(now).Truncate(time.Minute)))
+}
+
+// isWatched returns true if secret isn't excluded and its namespace is
+// watched by this instance
+func (m *SecretMonitor) isWatched(secret *corev1.Secret) bool {
+	annotation := m.config.SecretExpiry.ExcludeAnnotation
+	if len(annotation) > 0 && secret.Annotations[annotation] == "true" {
+		return false
+	}
+
+	return m.config.IsNamespaceWatched(secret.Namespace)
+}
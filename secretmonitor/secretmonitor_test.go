@@ -0,0 +1,165 @@
+package secretmonitor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func newTestCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kwatch-test"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newTestToken(claims string) []byte {
+	encode := base64.RawURLEncoding.EncodeToString
+	return []byte(encode([]byte(`{"alg":"RS256"}`)) + "." +
+		encode([]byte(claims)) + ".c2lnbmF0dXJl")
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now().Truncate(time.Second)
+	newSecret := func(
+		name string,
+		secretType corev1.SecretType,
+		data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID("uid-" + name),
+			},
+			Type: secretType,
+			Data: data,
+		}
+	}
+
+	excluded := newSecret("excluded", corev1.SecretTypeTLS, map[string][]byte{
+		corev1.TLSCertKey: newTestCertificate(t, now.Add(time.Hour)),
+	})
+	excluded.Annotations = map[string]string{
+		"kwatch.dev/ignore-expiry": "true",
+	}
+
+	client := fake.NewSimpleClientset(
+		newSecret("api-tls", corev1.SecretTypeTLS, map[string][]byte{
+			corev1.TLSCertKey: newTestCertificate(t, now.Add(48*time.Hour)),
+		}),
+		newSecret("web-tls", corev1.SecretTypeTLS, map[string][]byte{
+			corev1.TLSCertKey: newTestCertificate(t, now.Add(90*24*time.Hour)),
+		}),
+		newSecret("ci-token", corev1.SecretTypeServiceAccountToken,
+			map[string][]byte{
+				corev1.ServiceAccountTokenKey: newTestToken(fmt.Sprintf(
+					`{"exp":%d}`,
+					now.Add(-time.Hour).Unix())),
+			}),
+		newSecret("legacy-token", corev1.SecretTypeServiceAccountToken,
+			map[string][]byte{
+				corev1.ServiceAccountTokenKey: newTestToken(`{"sub":"x"}`),
+			}),
+		excluded,
+	)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := config.DefaultConfig()
+	cfg.SecretExpiry.Enabled = true
+	cfg.App.ClusterName = "prod"
+
+	m := NewSecretMonitor(client, cfg, alertManager)
+	m.Check(now)
+	assert.Len(prv.messages, 2)
+	assert.Contains(prv.messages, fmt.Sprintf(
+		":warning: Certificate of secret api-tls of cluster prod in "+
+			"namespace default expires at %s (in 48h0m0s).",
+		now.Add(48*time.Hour).UTC().Format(time.RFC3339)))
+	assert.Contains(prv.messages, fmt.Sprintf(
+		":red_circle: Service account token of secret ci-token of "+
+			"cluster prod in namespace default expired at %s.",
+		now.Add(-time.Hour).UTC().Format(time.RFC3339)))
+
+	// expiry is notified once before and once after it's reached
+	m.Check(now.Add(time.Hour))
+	assert.Len(prv.messages, 2)
+
+	m.Check(now.Add(49 * time.Hour))
+	assert.Len(prv.messages, 3)
+	assert.Contains(prv.messages[2], "api-tls")
+	assert.Contains(prv.messages[2], "expired at")
+}
+
+func TestGetTokenExpiry(t *testing.T) {
+	assert := assert.New(t)
+
+	expiry, err := getTokenExpiry(newTestToken(`{"exp":1700000000}`))
+	assert.NoError(err)
+	assert.Equal(time.Unix(1700000000, 0), expiry)
+
+	expiry, err = getTokenExpiry(newTestToken(`{"sub":"x"}`))
+	assert.NoError(err)
+	assert.True(expiry.IsZero())
+
+	_, err = getTokenExpiry([]byte("not-a-token"))
+	assert.Error(err)
+
+	_, err = getCertificateExpiry([]byte("not-a-certificate"))
+	assert.Error(err)
+}
@@ -0,0 +1,142 @@
+package security
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// maxNotified is number of notified workloads kept before they're reset
+const maxNotified = 1000
+
+// Monitor alerts new pods with disallowed security settings, replicas of
+// the same workload are alerted once
+type Monitor struct {
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+
+	mu sync.Mutex
+
+	// notified are keys of workloads and their violations which were
+	// notified
+	notified map[string]bool
+}
+
+// NewMonitor returns new instance of security monitor of cluster
+func NewMonitor(
+	config *config.Config,
+	alertManager *alertmanager.AlertManager) *Monitor {
+	return &Monitor{
+		config:       config,
+		alertManager: alertManager,
+		notified:     make(map[string]bool),
+	}
+}
+
+// Register adds handler of new pods to informer, it has to be called before
+// informer is started. Pods of initial list aren't new, so they aren't
+// checked
+func (m *Monitor) Register(inf *informer.Informer) {
+	if !m.config.Security.Enabled {
+		return
+	}
+
+	handler := cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok || isInInitialList {
+				return
+			}
+			m.Check(pod)
+		},
+	}
+	if _, err := inf.Pods().AddEventHandler(handler); err != nil {
+		logrus.WithError(err).Error("failed to add security event handler")
+	}
+}
+
+// Check notifies pod if it runs with disallowed settings in its namespace
+func (m *Monitor) Check(pod *corev1.Pod) {
+	if !m.isChecked(pod.Namespace) {
+		return
+	}
+
+	violations := getViolations(pod, m.config.Security.Disallowed)
+	if len(violations) == 0 {
+		return
+	}
+
+	workload := getWorkload(pod)
+	key := pod.Namespace + "/" + workload + "/" +
+		strings.Join(violations, ",")
+
+	m.mu.Lock()
+	if m.notified[key] {
+		m.mu.Unlock()
+		return
+	}
+	if len(m.notified) >= maxNotified {
+		m.notified = make(map[string]bool)
+	}
+	m.notified[key] = true
+	m.mu.Unlock()
+
+	logrus.WithFields(logrus.Fields{
+		"namespace":  pod.Namespace,
+		"pod":        pod.Name,
+		"violations": violations,
+	}).Warn("pod runs with disallowed security settings")
+
+	name := pod.Name
+	if workload != "Pod/"+pod.Name {
+		name += " (" + workload + ")"
+	}
+	if len(m.config.App.ClusterName) > 0 {
+		name += " of cluster " + m.config.App.ClusterName
+	}
+
+	m.alertManager.NotifyProviders(
+		m.config.Security.Providers,
+		fmt.Sprintf(
+			constant.SecurityViolationMsg,
+			name,
+			pod.Namespace,
+			strings.Join(violations, ", ")))
+}
+
+// isChecked returns true if settings are disallowed in namespace and it's
+// watched by this instance
+func (m *Monitor) isChecked(namespace string) bool {
+	if !m.config.IsNamespaceWatched(namespace) {
+		return false
+	}
+
+	if len(m.config.Security.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range m.config.Security.Namespaces {
+		if matched, _ := filepath.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// getWorkload returns owning workload of pod e.g. ReplicaSet/api-5d8f, or
+// the pod itself if it has no owner
+func getWorkload(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return owner.Kind + "/" + owner.Name
+	}
+	return "Pod/" + pod.Name
+}
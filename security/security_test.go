@@ -0,0 +1,136 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "security"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func newPod(namespace string, name string, owner string) *corev1.Pod {
+	privileged := true
+	nonRoot := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot: &nonRoot,
+			},
+			Containers: []corev1.Container{{
+				Name: "agent",
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: &privileged,
+				},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "logs",
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{
+						Path: "/var/log",
+					},
+				},
+			}},
+			HostNetwork: true,
+		},
+	}
+	if len(owner) > 0 {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{
+			Kind:       "DaemonSet",
+			Name:       owner,
+			Controller: &controller,
+		}}
+	}
+	return pod
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := config.DefaultConfig()
+	cfg.Security.Enabled = true
+	cfg.Security.Namespaces = []string{"prod-*"}
+	cfg.Security.Providers = []string{"security"}
+	cfg.App.ClusterName = "prod"
+
+	m := NewMonitor(cfg, alertManager)
+
+	// settings are allowed in other namespaces
+	m.Check(newPod("dev", "agent-a", "agent"))
+	assert.Len(prv.messages, 0)
+
+	// replicas of the same workload are notified once
+	m.Check(newPod("prod-eu", "agent-a", "agent"))
+	m.Check(newPod("prod-eu", "agent-b", "agent"))
+	assert.Equal([]string{
+		":shield: Pod agent-a (DaemonSet/agent) of cluster prod in " +
+			"namespace prod-eu runs with disallowed settings: " +
+			"privileged container agent, hostPath volume logs " +
+			"(/var/log), hostNetwork",
+	}, prv.messages)
+}
+
+func TestGetViolations(t *testing.T) {
+	assert := assert.New(t)
+
+	root := int64(0)
+	user := int64(1000)
+	nonRoot := false
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers: []corev1.Container{
+				{
+					Name: "api",
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser: &user,
+					},
+				},
+				{
+					Name: "sidecar",
+					SecurityContext: &corev1.SecurityContext{
+						RunAsUser: &root,
+					},
+				},
+				{
+					Name: "proxy",
+					SecurityContext: &corev1.SecurityContext{
+						RunAsNonRoot: &nonRoot,
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal([]string{
+		"root container init",
+		"root container sidecar",
+		"root container proxy",
+	}, getViolations(pod, config.SecuritySettings))
+	assert.Empty(getViolations(pod, []string{config.SecurityHostNetwork}))
+}
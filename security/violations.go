@@ -0,0 +1,83 @@
+package security
+
+import (
+	"slices"
+
+	"github.com/abahmed/kwatch/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getViolations returns descriptions of disallowed settings pod runs with
+func getViolations(pod *corev1.Pod, disallowed []string) []string {
+	violations := make([]string, 0)
+
+	containers := make([]corev1.Container, 0,
+		len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	if slices.Contains(disallowed, config.SecurityPrivileged) {
+		for _, container := range containers {
+			sc := container.SecurityContext
+			if sc != nil && sc.Privileged != nil && *sc.Privileged {
+				violations = append(violations,
+					"privileged container "+container.Name)
+			}
+		}
+	}
+
+	if slices.Contains(disallowed, config.SecurityRoot) {
+		for _, container := range containers {
+			if mayRunAsRoot(
+				pod.Spec.SecurityContext,
+				container.SecurityContext) {
+				violations = append(violations,
+					"root container "+container.Name)
+			}
+		}
+	}
+
+	if slices.Contains(disallowed, config.SecurityHostPath) {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.HostPath != nil {
+				violations = append(violations,
+					"hostPath volume "+volume.Name+" ("+
+						volume.HostPath.Path+")")
+			}
+		}
+	}
+
+	if slices.Contains(disallowed, config.SecurityHostNetwork) &&
+		pod.Spec.HostNetwork {
+		violations = append(violations, "hostNetwork")
+	}
+
+	return violations
+}
+
+// mayRunAsRoot returns true if container runs as user 0, or it may run as
+// root user of its image since neither user nor runAsNonRoot are set.
+// Settings of container override settings of pod
+func mayRunAsRoot(
+	podContext *corev1.PodSecurityContext,
+	containerContext *corev1.SecurityContext) bool {
+	var runAsUser *int64
+	var runAsNonRoot *bool
+	if podContext != nil {
+		runAsUser = podContext.RunAsUser
+		runAsNonRoot = podContext.RunAsNonRoot
+	}
+	if containerContext != nil {
+		if containerContext.RunAsUser != nil {
+			runAsUser = containerContext.RunAsUser
+		}
+		if containerContext.RunAsNonRoot != nil {
+			runAsNonRoot = containerContext.RunAsNonRoot
+		}
+	}
+
+	if runAsUser != nil {
+		return *runAsUser == 0
+	}
+	return runAsNonRoot == nil || !*runAsNonRoot
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Check returns an error if the checked component isn't ready
+type Check func() error
+
+// AddReadinessCheck registers check that has to pass for server to report
+// ready
+func (s *Server) AddReadinessCheck(name string, check Check) {
+	s.readinessChecks = append(
+		s.readinessChecks,
+		namedCheck{name: name, check: check})
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// handleHealthz reports that process is alive
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// handleReadyz reports whether all readiness checks pass
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	failures := make([]string, 0)
+	for _, c := range s.readinessChecks {
+		if err := c.check(); err != nil {
+			failures = append(failures, c.name+": "+err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, strings.Join(failures, "\n"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
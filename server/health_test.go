@@ -0,0 +1,45 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthz(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer(&config.Server{})
+
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+}
+
+func TestReadyz(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer(&config.Server{})
+
+	ready := false
+	s.AddReadinessCheck("watch", func() error {
+		if !ready {
+			return errors.New("not established")
+		}
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(http.StatusServiceUnavailable, rec.Code)
+	assert.Equal("watch: not established", rec.Body.String())
+
+	ready = true
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+}
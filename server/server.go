@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+)
+
+type Server struct {
+	config          *config.Server
+	mux             *http.ServeMux
+	readinessChecks []namedCheck
+}
+
+// NewServer returns new instance of internal HTTP server
+func NewServer(config *config.Server) *Server {
+	s := &Server{
+		config: config,
+		mux:    http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/healthz", s.handleHealthz)
+	s.mux.HandleFunc("/readyz", s.handleReadyz)
+
+	if config.Pprof {
+		s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+		s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	return s
+}
+
+// Enabled returns true if server is enabled
+func (s *Server) Enabled() bool {
+	return s.config.Enabled
+}
+
+// HandleFunc registers handler function for given pattern
+func (s *Server) HandleFunc(
+	pattern string,
+	handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Handle registers handler for given pattern
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start starts listening on configured port if server is enabled
+func (s *Server) Start() {
+	if !s.config.Enabled {
+		return
+	}
+
+	addr := fmt.Sprintf(":%d", s.config.Port)
+	logrus.Infof("starting server on %s", addr)
+
+	if err := http.ListenAndServe(addr, s.mux); err != nil {
+		logrus.Errorf("failed to start server: %s", err.Error())
+	}
+}
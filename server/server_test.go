@@ -0,0 +1,28 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPprof(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewServer(&config.Server{})
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(
+		rec,
+		httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	assert.Equal(http.StatusNotFound, rec.Code)
+
+	s = NewServer(&config.Server{Pprof: true})
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(
+		rec,
+		httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+}
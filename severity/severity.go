@@ -0,0 +1,62 @@
+package severity
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// Rank returns rank of severity in known levels starting from 1, or 0 if
+// it's unknown
+func Rank(severity string) int {
+	for i, level := range config.SeverityLevels {
+		if strings.EqualFold(level, severity) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+type Mapper struct {
+	config *config.Severity
+}
+
+// NewMapper returns new instance of mapper adjusting severities of alerts
+// by their namespace
+func NewMapper(config *config.Severity) *Mapper {
+	return &Mapper{config: config}
+}
+
+// Apply returns severity of alert in namespace, it's forced or kept
+// between min and max severity of first matching namespace rule. Alerts
+// without severity get default one
+func (m *Mapper) Apply(namespace string, severity string) string {
+	if m == nil {
+		return severity
+	}
+
+	if len(severity) == 0 {
+		severity = m.config.Default
+	}
+
+	for _, rule := range m.config.Namespaces {
+		if matched, _ := filepath.Match(rule.Namespace, namespace); !matched {
+			continue
+		}
+
+		if len(rule.Severity) > 0 {
+			return rule.Severity
+		}
+		if len(rule.Min) > 0 && Rank(severity) < Rank(rule.Min) {
+			severity = rule.Min
+		}
+		if len(rule.Max) > 0 &&
+			(Rank(severity) == 0 || Rank(severity) > Rank(rule.Max)) {
+			severity = rule.Max
+		}
+		return severity
+	}
+
+	return severity
+}
@@ -0,0 +1,45 @@
+package severity
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRank(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(1, Rank("info"))
+	assert.Equal(2, Rank("Warning"))
+	assert.Equal(3, Rank("critical"))
+	assert.Equal(0, Rank(""))
+	assert.Equal(0, Rank("page"))
+}
+
+func TestApply(t *testing.T) {
+	assert := assert.New(t)
+
+	m := NewMapper(&config.Severity{
+		Namespaces: []config.NamespaceSeverity{
+			{Namespace: "payments", Severity: "critical"},
+			{Namespace: "prod-*", Min: "warning"},
+			{Namespace: "dev-*", Max: "info"},
+		},
+	})
+
+	assert.Equal("critical", m.Apply("payments", "info"))
+	assert.Equal("warning", m.Apply("prod-api", ""))
+	assert.Equal("warning", m.Apply("prod-api", "info"))
+	assert.Equal("critical", m.Apply("prod-api", "critical"))
+	assert.Equal("info", m.Apply("dev-api", "critical"))
+	assert.Equal("info", m.Apply("dev-api", ""))
+	assert.Equal("", m.Apply("default", ""))
+
+	m.config.Default = "warning"
+	assert.Equal("warning", m.Apply("default", ""))
+	assert.Equal("info", m.Apply("dev-api", ""))
+
+	var nilMapper *Mapper
+	assert.Equal("info", nilMapper.Apply("prod-api", "info"))
+}
@@ -0,0 +1,180 @@
+package silence
+
+import (
+	"time"
+
+	"github.com/abahmed/kwatch/informer"
+	"github.com/abahmed/kwatch/util"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	Group    = "kwatch.dev"
+	Version  = "v1alpha1"
+	Resource = "kwatchsilences"
+)
+
+// GroupVersionResource of KwatchSilence resources
+var GroupVersionResource = schema.GroupVersionResource{
+	Group:    Group,
+	Version:  Version,
+	Resource: Resource,
+}
+
+// KwatchSilence is a custom resource muting alerts until it expires
+type KwatchSilence struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ResourceSpec `json:"spec"`
+}
+
+// ResourceSpec is spec of KwatchSilence
+type ResourceSpec struct {
+	// Match selects muted alerts
+	Match Match `json:"match"`
+
+	// ExpiresAt is when silence ends
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Comment tells why alerts are muted
+	Comment string `json:"comment,omitempty"`
+}
+
+// Match is criteria of muted alerts, all non-empty criteria must match
+type Match struct {
+	// Namespaces of pods, any of them must match
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Workloads owning pods e.g. Deployment/api, any of them must match
+	Workloads []string `json:"workloads,omitempty"`
+
+	// Labels of pods, all of them must match
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Reasons of failures e.g. OOMKilled, any of them must match
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Target is a failing pod or container checked against silences
+type Target struct {
+	Namespace string
+	Workload  string
+	Reason    string
+	Labels    map[string]string
+}
+
+// Matches returns true if target matches criteria
+func (m *Match) Matches(target *Target) bool {
+	if len(m.Namespaces) > 0 &&
+		!util.ContainsFold(m.Namespaces, target.Namespace) {
+		return false
+	}
+
+	if len(m.Workloads) > 0 && !util.ContainsFold(m.Workloads, target.Workload) {
+		return false
+	}
+
+	for key, value := range m.Labels {
+		if v, ok := target.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	if len(m.Reasons) > 0 && !util.ContainsFold(m.Reasons, target.Reason) {
+		return false
+	}
+
+	return true
+}
+
+// IsTargetSilenced returns true if target is silenced either using silence
+// API or a KwatchSilence resource
+func (s *Silencer) IsTargetSilenced(target *Target) bool {
+	if s.IsSilenced(target.Namespace, target.Workload) {
+		return true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for _, silence := range s.resources {
+		if !now.Before(silence.Spec.ExpiresAt.Time) {
+			continue
+		}
+
+		if silence.Spec.Match.Matches(target) {
+			logrus.WithFields(logrus.Fields{
+				"silence":   silence.Namespace + "/" + silence.Name,
+				"namespace": target.Namespace,
+				"workload":  target.Workload,
+			}).Debug("target is silenced by resource")
+			return true
+		}
+	}
+	return false
+}
+
+// SetResources replaces KwatchSilence resources of silencer, silences
+// without expiry are skipped
+func (s *Silencer) SetResources(silences []*KwatchSilence) {
+	resources := make([]*KwatchSilence, 0, len(silences))
+	for _, silence := range silences {
+		if silence.Spec.ExpiresAt == nil {
+			logrus.WithField("silence", silence.Namespace+"/"+silence.Name).
+				Warn("skipping silence without expiry")
+			continue
+		}
+		resources = append(resources, silence)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources = resources
+}
+
+// Start watches KwatchSilence resources in namespace, or all namespaces if
+// it's empty, and applies them on every change until stopCh is closed. It
+// returns false if silences can't be synced
+func (s *Silencer) Start(
+	client dynamic.Interface,
+	namespace string,
+	stopCh <-chan struct{}) bool {
+	return informer.WatchResources(
+		client,
+		GroupVersionResource,
+		namespace,
+		stopCh,
+		func(objects []*unstructured.Unstructured) {
+			silences := fromObjects(objects)
+			s.SetResources(silences)
+			logrus.WithField("silences", len(silences)).
+				Info("applied silence resources")
+		})
+}
+
+// fromObjects returns silences of unstructured objects, invalid ones are
+// skipped
+func fromObjects(objects []*unstructured.Unstructured) []*KwatchSilence {
+	silences := make([]*KwatchSilence, 0, len(objects))
+	for _, obj := range objects {
+		silence := &KwatchSilence{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+			obj.Object,
+			silence)
+		if err != nil {
+			logrus.WithField("silence", obj.GetNamespace()+"/"+obj.GetName()).
+				WithError(err).
+				Warn("skipping invalid silence")
+			continue
+		}
+		silences = append(silences, silence)
+	}
+	return silences
+}
@@ -0,0 +1,107 @@
+package silence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	target := &Target{
+		Namespace: "default",
+		Workload:  "Deployment/api",
+		Reason:    "OOMKilled",
+		Labels:    map[string]string{"app": "api"},
+	}
+
+	assert.True((&Match{}).Matches(target))
+	assert.True((&Match{Namespaces: []string{"default"}}).Matches(target))
+	assert.False((&Match{Namespaces: []string{"prod"}}).Matches(target))
+	assert.True((&Match{Workloads: []string{"deployment/api"}}).
+		Matches(target))
+	assert.False((&Match{Workloads: []string{"Deployment/web"}}).
+		Matches(target))
+	assert.True((&Match{Labels: map[string]string{"app": "api"}}).
+		Matches(target))
+	assert.False((&Match{Labels: map[string]string{"app": "web"}}).
+		Matches(target))
+	assert.True((&Match{Reasons: []string{"OOMKilled"}}).Matches(target))
+	assert.False((&Match{Reasons: []string{"Error"}}).Matches(target))
+}
+
+func TestIsTargetSilenced(t *testing.T) {
+	assert := assert.New(t)
+
+	// resource silences work without silence API
+	s := NewSilencer(&config.Silence{}, "")
+	target := &Target{Namespace: "default", Workload: "Deployment/api"}
+	assert.False(s.IsTargetSilenced(target))
+
+	expired := metav1.NewTime(time.Now().Add(-time.Minute))
+	active := metav1.NewTime(time.Now().Add(time.Hour))
+	s.SetResources([]*KwatchSilence{
+		{Spec: ResourceSpec{ExpiresAt: &expired}},
+		{Spec: ResourceSpec{}},
+	})
+	assert.False(s.IsTargetSilenced(target))
+
+	s.SetResources([]*KwatchSilence{
+		{Spec: ResourceSpec{
+			Match:     Match{Namespaces: []string{"default"}},
+			ExpiresAt: &active,
+		}},
+	})
+	assert.True(s.IsTargetSilenced(target))
+	assert.False(s.IsTargetSilenced(&Target{Namespace: "prod"}))
+
+	// silences of silence API are checked as well
+	s = newTestSilencer()
+	s.Add("default", "Deployment/api", time.Hour)
+	assert.True(s.IsTargetSilenced(target))
+}
+
+func TestStartResources(t *testing.T) {
+	assert := assert.New(t)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": Group + "/" + Version,
+		"kind":       "KwatchSilence",
+		"metadata": map[string]interface{}{
+			"name":      "maintenance",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"match": map[string]interface{}{
+				"workloads": []interface{}{"StatefulSet/db"},
+			},
+			"expiresAt": time.Now().Add(time.Hour).Format(time.RFC3339),
+			"comment":   "db maintenance",
+		},
+	}}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			GroupVersionResource: "KwatchSilenceList",
+		},
+		obj)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	s := NewSilencer(&config.Silence{}, "")
+	assert.True(s.Start(client, "", stopCh))
+	assert.True(s.IsTargetSilenced(&Target{
+		Namespace: "default",
+		Workload:  "StatefulSet/db",
+	}))
+}
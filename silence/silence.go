@@ -0,0 +1,275 @@
+package silence
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Silence mutes alerts of a workload until a given time
+type Silence struct {
+	Namespace string    `json:"namespace"`
+	Workload  string    `json:"workload"`
+	Until     time.Time `json:"until"`
+}
+
+type Silencer struct {
+	config      *config.Silence
+	externalURL string
+	secret      []byte
+
+	mu       sync.RWMutex
+	silences map[string]Silence
+
+	// resources are silences applied as KwatchSilence resources
+	resources []*KwatchSilence
+}
+
+// NewSilencer returns new instance of silencer
+func NewSilencer(config *config.Silence, externalURL string) *Silencer {
+	secret := []byte(config.Secret)
+	if config.Enabled && len(secret) == 0 {
+		logrus.Warn("silence secret is not set, generating a random one. " +
+			"silence links will be invalid after restart")
+		secret = generateSecret()
+	}
+
+	return &Silencer{
+		config:      config,
+		externalURL: strings.TrimSuffix(externalURL, "/"),
+		secret:      secret,
+		silences:    make(map[string]Silence),
+	}
+}
+
+// generateSecret returns cryptographically random secret, so signatures of
+// silence links can't be forged
+func generateSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		logrus.Fatalf("failed to generate silence secret: %s", err.Error())
+	}
+	return secret
+}
+
+// Enabled returns true if silence API is enabled
+func (s *Silencer) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Add silences workload in namespace for given duration
+func (s *Silencer) Add(
+	namespace, workload string,
+	duration time.Duration) Silence {
+	silence := Silence{
+		Namespace: namespace,
+		Workload:  workload,
+		Until:     time.Now().Add(duration),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.silences[getKey(namespace, workload)] = silence
+
+	return silence
+}
+
+// IsSilenced returns true if workload in namespace is silenced
+func (s *Silencer) IsSilenced(namespace, workload string) bool {
+	if !s.config.Enabled {
+		return false
+	}
+
+	key := getKey(namespace, workload)
+
+	s.mu.RLock()
+	silence, ok := s.silences[key]
+	s.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(silence.Until) {
+		s.mu.Lock()
+		delete(s.silences, key)
+		s.mu.Unlock()
+		return false
+	}
+
+	return true
+}
+
+// List returns active silences sorted by namespace and workload
+func (s *Silencer) List() []Silence {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	silences := make([]Silence, 0, len(s.silences))
+	for _, silence := range s.silences {
+		if now.Before(silence.Until) {
+			silences = append(silences, silence)
+		}
+	}
+
+	sort.Slice(silences, func(i, j int) bool {
+		return getKey(silences[i].Namespace, silences[i].Workload) <
+			getKey(silences[j].Namespace, silences[j].Workload)
+	})
+
+	return silences
+}
+
+// Link returns signed one-click URL that silences workload in namespace for
+// configured duration, it returns empty string if silence API is disabled or
+// server external URL is not set
+func (s *Silencer) Link(namespace, workload string) string {
+	if !s.config.Enabled || len(s.externalURL) == 0 {
+		return ""
+	}
+
+	expires := time.Now().
+		Add(time.Duration(s.config.LinkExpiry) * time.Hour).
+		Unix()
+
+	params := url.Values{}
+	params.Set("namespace", namespace)
+	params.Set("workload", workload)
+	params.Set("duration", strconv.Itoa(s.config.Duration))
+	params.Set("expires", strconv.FormatInt(expires, 10))
+	params.Set("signature", s.sign(params))
+
+	return s.externalURL + "/api/v1/silence?" + params.Encode()
+}
+
+// RegisterHandlers registers silence API handlers
+func (s *Silencer) RegisterHandlers(
+	handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle("/api/v1/silence", s.handleSilence)
+	handle("/api/v1/silences", s.handleList)
+}
+
+// handleSilence shows confirmation page of silence link, and silences
+// workload once it's confirmed. Only POST requests silence workloads, so
+// link previews of chat apps fetching links don't silence them
+func (s *Silencer) handleSilence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	params := r.Form
+
+	signature := params.Get("signature")
+	if !hmac.Equal([]byte(signature), []byte(s.sign(params))) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	expires, err := strconv.ParseInt(params.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		http.Error(w, "link has expired", http.StatusGone)
+		return
+	}
+
+	duration, err := strconv.Atoi(params.Get("duration"))
+	if err != nil || duration <= 0 {
+		http.Error(w, "invalid duration", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := confirmTemplate.Execute(w, map[string]interface{}{
+			"Namespace": params.Get("namespace"),
+			"Workload":  params.Get("workload"),
+			"Duration":  duration,
+			"Params":    params,
+		})
+		if err != nil {
+			logrus.Errorf("failed to render silence page: %s", err.Error())
+		}
+		return
+	}
+
+	silence := s.Add(
+		params.Get("namespace"),
+		params.Get("workload"),
+		time.Duration(duration)*time.Minute)
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": silence.Namespace,
+		"workload":  silence.Workload,
+		"until":     silence.Until.Format(time.RFC3339),
+	}).Info("silenced workload")
+
+	fmt.Fprintf(
+		w,
+		"%s in namespace %s is silenced until %s\n",
+		silence.Workload,
+		silence.Namespace,
+		silence.Until.Format(time.RFC3339))
+}
+
+// confirmTemplate is page confirming silence of workload, it posts params of
+// silence link back
+var confirmTemplate = template.Must(template.New("silence").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>kwatch silence</title>
+</head>
+<body>
+<p>Silence {{.Workload}} in namespace {{.Namespace}}
+for {{.Duration}} minutes?</p>
+<form method="post">
+{{- range $name, $values := .Params}}{{range $values}}
+<input type="hidden" name="{{$name}}" value="{{.}}">
+{{- end}}{{end}}
+<button type="submit">Silence</button>
+</form>
+</body>
+</html>
+`))
+
+// handleList returns active silences as json
+func (s *Silencer) handleList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.List())
+}
+
+// sign returns signature of silence link params
+func (s *Silencer) sign(params url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(
+		mac,
+		"%s\n%s\n%s\n%s",
+		params.Get("namespace"),
+		params.Get("workload"),
+		params.Get("duration"),
+		params.Get("expires"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func getKey(namespace, workload string) string {
+	return namespace + "/" + workload
+}
@@ -0,0 +1,141 @@
+package silence
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSilencer() *Silencer {
+	return NewSilencer(
+		&config.Silence{
+			Enabled:    true,
+			Secret:     "test",
+			Duration:   60,
+			LinkExpiry: 24,
+		},
+		"https://kwatch.example.com/")
+}
+
+func TestSilencerDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSilencer(&config.Silence{}, "https://kwatch.example.com")
+	s.Add("default", "Deployment/api", time.Hour)
+	assert.False(s.IsSilenced("default", "Deployment/api"))
+	assert.Empty(s.Link("default", "Deployment/api"))
+}
+
+func TestGeneratedSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &config.Silence{Enabled: true}
+	s1 := NewSilencer(cfg, "https://kwatch.example.com")
+	s2 := NewSilencer(cfg, "https://kwatch.example.com")
+	assert.Len(s1.secret, 32)
+	assert.NotEqual(s1.secret, s2.secret)
+}
+
+func TestAddAndIsSilenced(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newTestSilencer()
+	assert.False(s.IsSilenced("default", "Deployment/api"))
+
+	s.Add("default", "Deployment/api", time.Hour)
+	assert.True(s.IsSilenced("default", "Deployment/api"))
+	assert.False(s.IsSilenced("other", "Deployment/api"))
+	assert.Len(s.List(), 1)
+
+	s.Add("default", "Deployment/old", -time.Minute)
+	assert.False(s.IsSilenced("default", "Deployment/old"))
+	assert.Len(s.List(), 1)
+}
+
+func TestSilenceLink(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newTestSilencer()
+	link := s.Link("default", "Deployment/api")
+	assert.True(
+		strings.HasPrefix(link, "https://kwatch.example.com/api/v1/silence?"))
+
+	u, err := url.Parse(link)
+	assert.Nil(err)
+
+	// opening valid link shows confirmation page only
+	rr := httptest.NewRecorder()
+	s.handleSilence(rr, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/silence?"+u.RawQuery,
+		nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Contains(rr.Body.String(), `<form method="post">`)
+	assert.Contains(rr.Body.String(), `name="signature"`)
+	assert.False(s.IsSilenced("default", "Deployment/api"))
+
+	// confirming valid link silences workload
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/api/v1/silence",
+		strings.NewReader(u.RawQuery))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr = httptest.NewRecorder()
+	s.handleSilence(rr, req)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.True(s.IsSilenced("default", "Deployment/api"))
+
+	rr = httptest.NewRecorder()
+	s.handleSilence(rr, httptest.NewRequest(
+		http.MethodDelete,
+		"/api/v1/silence?"+u.RawQuery,
+		nil))
+	assert.Equal(http.StatusMethodNotAllowed, rr.Code)
+
+	// tampered link is rejected
+	params := u.Query()
+	params.Set("workload", "Deployment/other")
+	rr = httptest.NewRecorder()
+	s.handleSilence(rr, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/silence?"+params.Encode(),
+		nil))
+	assert.Equal(http.StatusForbidden, rr.Code)
+	assert.False(s.IsSilenced("default", "Deployment/other"))
+
+	// expired link is rejected
+	params = u.Query()
+	params.Set("expires", "1")
+	params.Set("signature", s.sign(params))
+	rr = httptest.NewRecorder()
+	s.handleSilence(rr, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/silence?"+params.Encode(),
+		nil))
+	assert.Equal(http.StatusGone, rr.Code)
+}
+
+func TestListHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newTestSilencer()
+	s.Add("default", "Deployment/api", time.Hour)
+
+	rr := httptest.NewRecorder()
+	s.handleList(rr, httptest.NewRequest(
+		http.MethodGet,
+		"/api/v1/silences",
+		nil))
+
+	var silences []Silence
+	assert.Nil(json.Unmarshal(rr.Body.Bytes(), &silences))
+	assert.Len(silences, 1)
+	assert.Equal("Deployment/api", silences[0].Workload)
+}
@@ -0,0 +1,158 @@
+package skew
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/abahmed/kwatch/metrics"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// clientGoModule is module path of client-go used to find its version
+	clientGoModule = "k8s.io/client-go"
+
+	// defaultClientMinor is minor version of client-go kwatch is built with,
+	// used if build info isn't available
+	defaultClientMinor = 30
+
+	// MaxSkew is max number of minor versions Kubernetes API server may be
+	// older or newer than client-go
+	MaxSkew = 1
+)
+
+type Checker struct {
+	client       kubernetes.Interface
+	config       *config.VersionSkew
+	clusterName  string
+	alertManager *alertmanager.AlertManager
+	clientMinor  int
+
+	// notified is server version of last notification, so unsupported
+	// version is notified once
+	notified string
+}
+
+// NewChecker returns new instance of version skew checker of cluster
+func NewChecker(
+	client kubernetes.Interface,
+	config *config.VersionSkew,
+	clusterName string,
+	alertManager *alertmanager.AlertManager) *Checker {
+	return &Checker{
+		client:       client,
+		config:       config,
+		clusterName:  clusterName,
+		alertManager: alertManager,
+		clientMinor:  getClientMinor(),
+	}
+}
+
+// Start checks version skew at startup and on configured interval
+func (c *Checker) Start() {
+	if !c.config.Enabled {
+		return
+	}
+
+	// check at startup
+	c.Check()
+
+	interval := c.config.Interval
+	if interval <= 0 {
+		interval = 24
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.Check()
+	}
+}
+
+// Check compares Kubernetes version of cluster with client version, and
+// notifies if skew is unsupported
+func (c *Checker) Check() {
+	info, err := c.client.Discovery().ServerVersion()
+	if err != nil {
+		logrus.WithField("cluster", c.clusterName).
+			WithError(err).
+			Warn("failed to get kubernetes version")
+		return
+	}
+
+	serverMinor, err := parseMinor(info.Minor)
+	if err != nil {
+		logrus.WithField("cluster", c.clusterName).
+			WithError(err).
+			Warn("failed to parse kubernetes version")
+		return
+	}
+
+	skew := serverMinor - c.clientMinor
+	clientVersion := fmt.Sprintf("1.%d", c.clientMinor)
+	metrics.VersionSkew.WithLabelValues(c.clusterName).Set(float64(skew))
+
+	if skew >= -MaxSkew && skew <= MaxSkew {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"cluster": c.clusterName,
+		"server":  info.GitVersion,
+		"client":  clientVersion,
+	}).Warn("unsupported kubernetes version skew")
+
+	if c.notified == info.GitVersion {
+		return
+	}
+	c.notified = info.GitVersion
+
+	serverVersion := info.GitVersion
+	if len(c.clusterName) > 0 {
+		serverVersion += " of cluster " + c.clusterName
+	}
+
+	c.alertManager.Notify(fmt.Sprintf(
+		constant.VersionSkewMsg,
+		serverVersion,
+		clientVersion,
+		MaxSkew))
+}
+
+// getClientMinor returns minor version of client-go kwatch is built with,
+// client-go v0.30.x supports Kubernetes 1.30
+func getClientMinor() int {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return defaultClientMinor
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != clientGoModule {
+			continue
+		}
+
+		parts := strings.Split(dep.Version, ".")
+		if len(parts) < 2 {
+			break
+		}
+		if minor, err := strconv.Atoi(parts[1]); err == nil {
+			return minor
+		}
+	}
+	return defaultClientMinor
+}
+
+// parseMinor returns minor version of API server, managed clusters may
+// report it with a suffix e.g. 30+
+func parseMinor(minor string) (int, error) {
+	return strconv.Atoi(strings.TrimRight(minor, "+"))
+}
@@ -0,0 +1,88 @@
+package skew
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/abahmed/kwatch/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	client := fake.NewSimpleClientset()
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	c := NewChecker(
+		client,
+		&config.VersionSkew{Enabled: true},
+		"prod",
+		alertManager)
+	c.clientMinor = 30
+
+	discovery.FakedServerVersion = &version.Info{
+		Major:      "1",
+		Minor:      "31+",
+		GitVersion: "v1.31.2-eks-7f9249a",
+	}
+	c.Check()
+	assert.Len(prv.messages, 0)
+	assert.Equal(
+		1.0,
+		testutil.ToFloat64(metrics.VersionSkew.WithLabelValues("prod")))
+
+	discovery.FakedServerVersion = &version.Info{
+		Major:      "1",
+		Minor:      "27",
+		GitVersion: "v1.27.3",
+	}
+	c.Check()
+	assert.Len(prv.messages, 1)
+	assert.Contains(prv.messages[0], "v1.27.3 of cluster prod")
+	assert.Contains(prv.messages[0], "1.30 ±1")
+	assert.Equal(
+		-3.0,
+		testutil.ToFloat64(metrics.VersionSkew.WithLabelValues("prod")))
+
+	// unsupported version is notified once
+	c.Check()
+	assert.Len(prv.messages, 1)
+}
+
+func TestParseMinor(t *testing.T) {
+	assert := assert.New(t)
+
+	minor, err := parseMinor("30+")
+	assert.Nil(err)
+	assert.Equal(30, minor)
+
+	_, err = parseMinor("")
+	assert.NotNil(err)
+}
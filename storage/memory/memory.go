@@ -1,25 +1,92 @@
 package memory
 
 import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/metrics"
 	storage "github.com/abahmed/kwatch/storage"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// saveInterval is how often changed state is saved when path is
+	// configured
+	saveInterval = time.Minute
+
+	// expireInterval is how often expired pods are removed when ttl is
+	// configured
+	expireInterval = time.Minute
 )
 
 type memory struct {
 	smap sync.Map
+
+	// lastSeen keeps elements of pods in recency list, which is ordered
+	// from most to least recently seen pod, to evict least recently seen
+	// and expired pods
+	mu       sync.Mutex
+	lastSeen map[string]*list.Element
+	recency  *list.List
+	maxPods  int
+	ttl      time.Duration
+
+	// path of file state is saved to, so it survives restarts
+	path    string
+	changed bool
+}
+
+// seenPod is element of recency list
+type seenPod struct {
+	key  string
+	seen time.Time
+}
+
+// podSnapshot is saved state of a pod
+type podSnapshot struct {
+	LastSeen   time.Time                          `json:"lastSeen"`
+	Containers map[string]*storage.ContainerState `json:"containers"`
 }
 
 // NewMemory returns new Memory object
-func NewMemory() storage.Storage {
-	return &memory{
-		smap: sync.Map{},
+func NewMemory(cfg *config.PodState) storage.Storage {
+	m := &memory{
+		smap:    sync.Map{},
+		maxPods: cfg.MaxPods,
+		ttl:     time.Duration(cfg.TTL) * time.Hour,
+		path:    cfg.Path,
+	}
+
+	if len(m.path) > 0 {
+		if err := m.load(); err != nil {
+			logrus.WithError(err).Error("failed to load pod state")
+		}
+		go m.saveEvery(saveInterval)
 	}
+
+	if m.ttl > 0 {
+		go m.expireEvery(expireInterval)
+	}
+
+	return m
 }
 
 // AddPodContainer attaches container to pod to mark it has an error
 func (m *memory) AddPodContainer(namespace, podKey, containerKey string, state *storage.ContainerState) {
 	key := m.getKey(namespace, podKey)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	defer m.evict()
+
+	m.touch(key)
+	m.changed = true
 	if v, ok := m.smap.Load(key); ok {
 		containers := v.(map[string]*storage.ContainerState)
 		containers[containerKey] = state
@@ -33,18 +100,27 @@ func (m *memory) AddPodContainer(namespace, podKey, containerKey string, state *
 // Delete deletes pod with all its containers
 func (m *memory) DelPod(namespace, podKey string) {
 	key := m.getKey(namespace, podKey)
-	m.smap.Delete(key)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.delete(key)
+	metrics.PodStateSize.Set(float64(len(m.lastSeen)))
 }
 
 // DelPodContainer detaches container from pod to mark error is resolved
 func (m *memory) DelPodContainer(namespace, podKey, containerKey string) {
 	key := m.getKey(namespace, podKey)
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	v, ok := m.smap.Load(key)
 	if !ok {
 		return
 	}
 
+	m.changed = true
 	containers := v.(map[string]*storage.ContainerState)
 	delete(containers, containerKey)
 
@@ -53,37 +129,194 @@ func (m *memory) DelPodContainer(namespace, podKey, containerKey string) {
 
 // HasPodContainer checks if container is attached to given pod or not
 func (m *memory) HasPodContainer(namespace, podKey, containerKey string) bool {
+	return m.GetPodContainer(namespace, podKey, containerKey) != nil
+}
+
+func (m *memory) GetPodContainer(namespace, podKey, containerKey string) *storage.ContainerState {
 	key := m.getKey(namespace, podKey)
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	v, ok := m.smap.Load(key)
 	if !ok {
-		return false
+		return nil
 	}
 
+	m.touch(key)
 	containers := v.(map[string]*storage.ContainerState)
-	if _, ok := containers[containerKey]; ok {
+	if val, ok := containers[containerKey]; ok {
+		return val
+	}
+
+	return nil
+}
+
+// Save writes state to configured path if it has changed since last save
+func (m *memory) Save() error {
+	if len(m.path) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	if !m.changed {
+		m.mu.Unlock()
+		return nil
+	}
+
+	snapshot := make(map[string]*podSnapshot)
+	m.smap.Range(func(k, v interface{}) bool {
+		key := k.(string)
+		containers := make(map[string]*storage.ContainerState)
+		for name, state := range v.(map[string]*storage.ContainerState) {
+			containers[name] = state
+		}
+
+		var lastSeen time.Time
+		if elem, ok := m.lastSeen[key]; ok {
+			lastSeen = elem.Value.(*seenPod).seen
+		}
+
+		snapshot[key] = &podSnapshot{
+			LastSeen:   lastSeen,
+			Containers: containers,
+		}
 		return true
+	})
+
+	data, err := json.Marshal(snapshot)
+	m.changed = false
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
 	}
 
-	return false
+	tmpPath := m.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, m.path)
 }
 
-func (m *memory) GetPodContainer(namespace, podKey, containerKey string) *storage.ContainerState {
-	key := m.getKey(namespace, podKey)
+// saveEvery saves state periodically
+func (m *memory) saveEvery(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := m.Save(); err != nil {
+			logrus.WithError(err).Error("failed to save pod state")
+		}
+	}
+}
 
-	v, ok := m.smap.Load(key)
-	if !ok {
+// load restores state saved to configured path
+func (m *memory) load() error {
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, os.ErrNotExist) {
 		return nil
 	}
+	if err != nil {
+		return err
+	}
 
-	containers := v.(map[string]*storage.ContainerState)
-	if val, ok := containers[containerKey]; ok {
-		return val
+	snapshot := make(map[string]*podSnapshot)
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	// pods are added from least to most recently seen one
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return snapshot[keys[i]].LastSeen.Before(snapshot[keys[j]].LastSeen)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		m.smap.Store(key, snapshot[key].Containers)
+		m.touchAt(key, snapshot[key].LastSeen)
 	}
+	m.expire()
+	m.evict()
 
+	logrus.WithField("pods", len(m.lastSeen)).Info("loaded pod state")
 	return nil
 }
 
+// touch marks pod as recently seen, it should be called while holding lock
+func (m *memory) touch(key string) {
+	m.touchAt(key, time.Now())
+}
+
+// touchAt marks pod as seen at given time and moves it to front of recency
+// list, so it should be called in order of time, while holding lock
+func (m *memory) touchAt(key string, seen time.Time) {
+	if m.lastSeen == nil {
+		m.lastSeen = make(map[string]*list.Element)
+		m.recency = list.New()
+	}
+
+	if elem, ok := m.lastSeen[key]; ok {
+		elem.Value.(*seenPod).seen = seen
+		m.recency.MoveToFront(elem)
+	} else {
+		m.lastSeen[key] = m.recency.PushFront(&seenPod{key: key, seen: seen})
+	}
+	metrics.PodStateSize.Set(float64(len(m.lastSeen)))
+}
+
+// delete removes pod from state, it should be called while holding lock
+func (m *memory) delete(key string) {
+	m.smap.Delete(key)
+	if elem, ok := m.lastSeen[key]; ok {
+		m.recency.Remove(elem)
+		delete(m.lastSeen, key)
+	}
+	m.changed = true
+}
+
+// evict removes least recently seen pods exceeding max number of pods, it
+// should be called while holding lock
+func (m *memory) evict() {
+	for m.maxPods > 0 && len(m.lastSeen) > m.maxPods {
+		m.delete(m.recency.Back().Value.(*seenPod).key)
+	}
+
+	metrics.PodStateSize.Set(float64(len(m.lastSeen)))
+}
+
+// expire removes pods which weren't seen within ttl, it should be called
+// while holding lock
+func (m *memory) expire() {
+	if m.ttl <= 0 || m.recency == nil {
+		return
+	}
+
+	expiry := time.Now().Add(-m.ttl)
+	for elem := m.recency.Back(); elem != nil; elem = m.recency.Back() {
+		pod := elem.Value.(*seenPod)
+		if !pod.seen.Before(expiry) {
+			break
+		}
+		m.delete(pod.key)
+	}
+
+	metrics.PodStateSize.Set(float64(len(m.lastSeen)))
+}
+
+// expireEvery removes expired pods periodically
+func (m *memory) expireEvery(interval time.Duration) {
+	for range time.Tick(interval) {
+		m.mu.Lock()
+		m.expire()
+		m.mu.Unlock()
+	}
+}
+
 func (*memory) getKey(namespace, pod string) string {
 	return namespace + "/" + pod
 }
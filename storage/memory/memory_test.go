@@ -1,14 +1,17 @@
 package memory
 
 import (
+	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/abahmed/kwatch/config"
 	storage "github.com/abahmed/kwatch/storage"
 )
 
 func TestMemory(t *testing.T) {
-	m := NewMemory()
+	m := NewMemory(&config.PodState{})
 	_, ok := m.(storage.Storage)
 	if !ok {
 		t.Errorf("expected to return Storage interface")
@@ -125,3 +128,89 @@ func TestDelPod(t *testing.T) {
 		t.Errorf("expected not to find pod test")
 	}
 }
+
+func TestEvictMaxPods(t *testing.T) {
+	mem := &memory{
+		smap:    sync.Map{},
+		maxPods: 2,
+	}
+
+	mem.AddPodContainer("default", "test1", "test", &storage.ContainerState{})
+	mem.AddPodContainer("default", "test2", "test", &storage.ContainerState{})
+
+	// test2 is seen recently, so test1 is the least recently seen one
+	mem.HasPodContainer("default", "test2", "test")
+	mem.AddPodContainer("default", "test3", "test", &storage.ContainerState{})
+
+	if mem.HasPodContainer("default", "test1", "test") {
+		t.Errorf("expected pod test1 to be evicted")
+	}
+
+	if !mem.HasPodContainer("default", "test2", "test") ||
+		!mem.HasPodContainer("default", "test3", "test") {
+		t.Errorf("expected to find pods test2 and test3")
+	}
+
+	if len(mem.lastSeen) != 2 {
+		t.Errorf("expected 2 pods, got %d", len(mem.lastSeen))
+	}
+}
+
+func TestEvictTTL(t *testing.T) {
+	mem := &memory{
+		smap: sync.Map{},
+		ttl:  time.Hour,
+	}
+
+	mem.AddPodContainer("default", "test1", "test", &storage.ContainerState{})
+	mem.lastSeen[mem.getKey("default", "test1")].Value.(*seenPod).seen =
+		time.Now().Add(-2 * time.Hour)
+
+	mem.AddPodContainer("default", "test2", "test", &storage.ContainerState{})
+
+	// expired pods are removed periodically
+	if _, ok := mem.smap.Load(mem.getKey("default", "test1")); !ok {
+		t.Errorf("expected pod test1 to be kept until it's removed")
+	}
+	mem.expire()
+
+	if mem.HasPodContainer("default", "test1", "test") {
+		t.Errorf("expected pod test1 to be expired")
+	}
+
+	if !mem.HasPodContainer("default", "test2", "test") {
+		t.Errorf("expected to find pod test2")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	mem := NewMemory(&config.PodState{Path: path})
+	mem.AddPodContainer("default", "test", "test1", &storage.ContainerState{
+		PodUID:       "uid",
+		RestartCount: 3,
+		Reason:       "CrashLoopBackOff",
+	})
+
+	if err := mem.Save(); err != nil {
+		t.Fatalf("expected to save state: %v", err)
+	}
+
+	restored := NewMemory(&config.PodState{Path: path})
+	state := restored.GetPodContainer("default", "test", "test1")
+	if state == nil {
+		t.Fatalf("expected to restore container test1 in pod test")
+	}
+
+	if state.PodUID != "uid" ||
+		state.RestartCount != 3 ||
+		state.Reason != "CrashLoopBackOff" {
+		t.Errorf("expected restored state to match saved one, got %v", state)
+	}
+
+	// without path state is not saved
+	if err := NewMemory(&config.PodState{}).Save(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
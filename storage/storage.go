@@ -3,6 +3,10 @@ package storage
 import "time"
 
 type ContainerState struct {
+	// PodUID of pod the state belongs to, so state of a pod recreated with
+	// same name isn't mixed up with the old one
+	PodUID string
+
 	RestartCount     int32
 	LastTerminatedOn time.Time
 	Reason           string
@@ -19,4 +23,8 @@ type Storage interface {
 	DelPod(namespace, podKey string)
 	HasPodContainer(namespace, podKey, containerKey string) bool
 	GetPodContainer(namespace, podKey, containerKey string) *ContainerState
+
+	// Save persists state, so already reported failures aren't reported
+	// again after restart
+	Save() error
 }
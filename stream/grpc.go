@@ -0,0 +1,173 @@
+package stream
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+
+	kwatchv1 "github.com/abahmed/kwatch/api/kwatch/v1"
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type GRPCServer struct {
+	kwatchv1.UnimplementedAlertServiceServer
+
+	config *config.GRPC
+	hub    *Hub
+}
+
+// NewGRPCServer returns new instance of gRPC server streaming alerts of hub
+func NewGRPCServer(config *config.GRPC, hub *Hub) *GRPCServer {
+	return &GRPCServer{
+		config: config,
+		hub:    hub,
+	}
+}
+
+// Enabled returns true if gRPC server is enabled
+func (s *GRPCServer) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Start listens on configured port until stopCh is closed
+func (s *GRPCServer) Start(stopCh <-chan struct{}) {
+	if !s.config.Enabled {
+		return
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.Port))
+	if err != nil {
+		logrus.WithError(err).Error("failed to start gRPC server")
+		return
+	}
+
+	srv, err := s.newServer()
+	if err != nil {
+		listener.Close()
+		logrus.WithError(err).Error("failed to start gRPC server")
+		return
+	}
+
+	go func() {
+		<-stopCh
+		// streams last until clients disconnect, so they aren't waited for
+		srv.Stop()
+	}()
+
+	logrus.Infof("starting gRPC server on %s", listener.Addr())
+	if err := srv.Serve(listener); err != nil {
+		logrus.WithError(err).Error("failed to serve gRPC")
+	}
+}
+
+// newServer returns gRPC server with alert service registered
+func (s *GRPCServer) newServer() (*grpc.Server, error) {
+	opts := []grpc.ServerOption{grpc.StreamInterceptor(s.authorize)}
+	if len(s.config.TLSCertFile) > 0 {
+		creds, err := credentials.NewServerTLSFromFile(
+			s.config.TLSCertFile,
+			s.config.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	srv := grpc.NewServer(opts...)
+	kwatchv1.RegisterAlertServiceServer(srv, s)
+	return srv, nil
+}
+
+// StreamAlerts streams alerts of requested namespaces until client
+// disconnects
+func (s *GRPCServer) StreamAlerts(
+	req *kwatchv1.StreamAlertsRequest,
+	stream kwatchv1.AlertService_StreamAlertsServer) error {
+	sub := s.hub.Subscribe(req.GetNamespaces(), s.config.BufferSize)
+	defer s.hub.Unsubscribe(sub)
+
+	logrus.WithField("namespaces", req.GetNamespaces()).
+		Debug("gRPC client subscribed to alerts")
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case alert := <-sub.Alerts():
+			if err := stream.Send(NewProtoAlert(alert)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// authorize rejects streams without configured bearer token in
+// authorization metadata
+func (s *GRPCServer) authorize(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if len(s.config.Token) == 0 {
+		return handler(srv, ss)
+	}
+
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	given := ""
+	if values := md.Get("authorization"); len(values) > 0 {
+		given = strings.TrimPrefix(values[0], "Bearer ")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(given), []byte(s.config.Token)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return handler(srv, ss)
+}
+
+// NewProtoAlert returns protobuf message of alert
+func NewProtoAlert(alert *Alert) *kwatchv1.Alert {
+	ev := alert.Event
+	return &kwatchv1.Alert{
+		Time:      timestamppb.New(alert.Time),
+		Cluster:   ev.Cluster,
+		Namespace: ev.Namespace,
+		Pod:       ev.PodName,
+		Container: ev.ContainerName,
+		Reason:    ev.Reason,
+		Severity:  ev.Severity,
+		Summary:   ev.Summary,
+		Events:    ev.Events,
+		Logs:      ev.Logs,
+		Labels:    ev.Labels,
+	}
+}
+
+// tokenCredentials sends bearer token with every call of gRPC clients
+type tokenCredentials string
+
+// GetRequestMetadata returns authorization metadata of token
+func (t tokenCredentials) GetRequestMetadata(
+	ctx context.Context,
+	uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity returns false, so token can be sent without TLS
+// e.g. within the cluster
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// WithToken returns dial option sending bearer token with every call
+func WithToken(token string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(tokenCredentials(token))
+}
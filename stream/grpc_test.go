@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	kwatchv1 "github.com/abahmed/kwatch/api/kwatch/v1"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial starts gRPC server of s and returns client connected to it
+func dial(
+	t *testing.T,
+	s *GRPCServer,
+	opts ...grpc.DialOption) kwatchv1.AlertServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	srv, err := s.newServer()
+	assert.Nil(t, err)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	opts = append(opts,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(
+			func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}))
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return kwatchv1.NewAlertServiceClient(conn)
+}
+
+func TestStreamAlerts(t *testing.T) {
+	assert := assert.New(t)
+
+	hub := NewHub()
+	client := dial(
+		t,
+		NewGRPCServer(&config.GRPC{Token: "secret", BufferSize: 10}, hub),
+		WithToken("secret"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamAlerts(ctx, &kwatchv1.StreamAlertsRequest{
+		Namespaces: []string{"default"},
+	})
+	assert.Nil(err)
+
+	// wait for subscription before sending alerts
+	for hub.Subscribers() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	hub.SendEvent(&event.Event{Namespace: "kube-system", PodName: "dns"})
+	hub.SendEvent(&event.Event{
+		Namespace:     "default",
+		PodName:       "api-0",
+		ContainerName: "api",
+		Reason:        "OOMKilled",
+		Labels:        map[string]string{"app": "api"},
+	})
+
+	alert, err := stream.Recv()
+	assert.Nil(err)
+	assert.Equal("default", alert.GetNamespace())
+	assert.Equal("api-0", alert.GetPod())
+	assert.Equal("api", alert.GetContainer())
+	assert.Equal("OOMKilled", alert.GetReason())
+	assert.Equal("api", alert.GetLabels()["app"])
+	assert.NotNil(alert.GetTime())
+}
+
+func TestStreamAlertsUnauthenticated(t *testing.T) {
+	assert := assert.New(t)
+
+	client := dial(
+		t,
+		NewGRPCServer(&config.GRPC{Token: "secret", BufferSize: 10}, NewHub()),
+		WithToken("wrong"))
+
+	stream, err := client.StreamAlerts(
+		context.Background(),
+		&kwatchv1.StreamAlertsRequest{})
+	assert.Nil(err)
+
+	_, err = stream.Recv()
+	assert.Equal(codes.Unauthenticated, status.Code(err))
+}
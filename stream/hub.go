@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+)
+
+// Alert is a sent alert streamed to subscribers
+type Alert struct {
+	Time  time.Time
+	Event *event.Event
+}
+
+// Subscriber receives alerts of its namespaces
+type Subscriber struct {
+	namespaces []string
+	alerts     chan *Alert
+}
+
+// Alerts returns channel alerts of subscriber are received from
+func (s *Subscriber) Alerts() <-chan *Alert {
+	return s.alerts
+}
+
+// matches returns true if alert of namespace is sent to subscriber
+func (s *Subscriber) matches(namespace string) bool {
+	if len(s.namespaces) == 0 {
+		return true
+	}
+
+	for _, ns := range s.namespaces {
+		if strings.EqualFold(ns, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hub fans out sent alerts to subscribers of live streams. It's added as a
+// provider, so only alerts sent by leader are streamed and routing rules
+// apply to streamed alerts as well
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+}
+
+// NewHub returns new instance of hub without subscribers
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscriber]struct{}),
+	}
+}
+
+// Name returns name of provider
+func (h *Hub) Name() string {
+	return "stream"
+}
+
+// SendEvent sends event to matching subscribers, it never blocks as alerts
+// are dropped for subscribers falling behind
+func (h *Hub) SendEvent(ev *event.Event) error {
+	e := *ev
+	alert := &Alert{Time: time.Now(), Event: &e}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		if !sub.matches(ev.Namespace) {
+			continue
+		}
+
+		select {
+		case sub.alerts <- alert:
+		default:
+			logrus.WithFields(logrus.Fields{
+				"namespace": ev.Namespace,
+				"pod":       ev.PodName,
+			}).Warn("dropping streamed alert as subscriber is falling behind")
+		}
+	}
+	return nil
+}
+
+// SendMessage does nothing as only alerts are streamed
+func (h *Hub) SendMessage(msg string) error {
+	return nil
+}
+
+// Subscribe returns new subscriber receiving alerts of namespaces, or all
+// namespaces if it's empty, up to bufferSize alerts are buffered
+func (h *Hub) Subscribe(namespaces []string, bufferSize int) *Subscriber {
+	sub := &Subscriber{
+		namespaces: namespaces,
+		alerts:     make(chan *Alert, bufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe stops sending alerts to subscriber
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, sub)
+}
+
+// Subscribers returns number of subscribers
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
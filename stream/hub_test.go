@@ -0,0 +1,44 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewHub()
+	all := h.Subscribe(nil, 10)
+	kube := h.Subscribe([]string{"kube-system"}, 10)
+	assert.Equal(2, h.Subscribers())
+
+	assert.Nil(h.SendEvent(&event.Event{Namespace: "default", PodName: "a"}))
+	assert.Nil(h.SendEvent(&event.Event{Namespace: "Kube-System", PodName: "b"}))
+	assert.Nil(h.SendMessage("welcome"))
+
+	assert.Len(all.alerts, 2)
+	assert.Len(kube.alerts, 1)
+	assert.Equal("b", (<-kube.Alerts()).Event.PodName)
+
+	h.Unsubscribe(kube)
+	assert.Equal(1, h.Subscribers())
+	assert.Nil(h.SendEvent(&event.Event{Namespace: "kube-system"}))
+	assert.Len(kube.alerts, 0)
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewHub()
+	sub := h.Subscribe(nil, 1)
+
+	// alerts over buffer size are dropped instead of blocking
+	assert.Nil(h.SendEvent(&event.Event{PodName: "a"}))
+	assert.Nil(h.SendEvent(&event.Event{PodName: "b"}))
+
+	assert.Len(sub.alerts, 1)
+	assert.Equal("a", (<-sub.Alerts()).Event.PodName)
+}
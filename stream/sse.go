@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// keepAliveInterval is interval of comments sent to idle connections, so
+// proxies don't close them
+const keepAliveInterval = 30 * time.Second
+
+type SSEHandler struct {
+	config *config.SSE
+	hub    *Hub
+}
+
+// NewSSEHandler returns new instance of Server-Sent Events handler streaming
+// alerts of hub
+func NewSSEHandler(config *config.SSE, hub *Hub) *SSEHandler {
+	return &SSEHandler{
+		config: config,
+		hub:    hub,
+	}
+}
+
+// Enabled returns true if live alert stream is enabled
+func (s *SSEHandler) Enabled() bool {
+	return s.config.Enabled
+}
+
+// RegisterHandlers registers live alert stream endpoint using given handle
+// function
+func (s *SSEHandler) RegisterHandlers(
+	handle func(string, func(http.ResponseWriter, *http.Request))) {
+	handle("/api/v1/alerts/stream", s.handleStream)
+}
+
+// handleStream streams alerts of namespace query params as json encoded
+// events until client disconnects
+func (s *SSEHandler) handleStream(w http.ResponseWriter, r *http.Request) {
+	if !s.isAuthorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	namespaces := parseNamespaces(r.URL.Query()["namespace"])
+	sub := s.hub.Subscribe(namespaces, s.config.BufferSize)
+	defer s.hub.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	logrus.WithField("namespaces", namespaces).
+		Debug("client subscribed to live alert stream")
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case alert := <-sub.Alerts():
+			data, err := protojson.Marshal(NewProtoAlert(alert))
+			if err != nil {
+				logrus.WithError(err).Error("failed to encode streamed alert")
+				continue
+			}
+			fmt.Fprintf(w, "event: alert\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
+
+// isAuthorized returns true if request has configured token either as
+// bearer token or token query param, as browsers' EventSource can't send
+// headers
+func (s *SSEHandler) isAuthorized(r *http.Request) bool {
+	if len(s.config.Token) == 0 {
+		return true
+	}
+
+	given := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); len(auth) > 0 {
+		given = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(s.config.Token)) == 1
+}
+
+// parseNamespaces returns namespaces of repeated or comma separated params
+func parseNamespaces(params []string) []string {
+	namespaces := make([]string, 0)
+	for _, param := range params {
+		for _, ns := range strings.Split(param, ",") {
+			if ns = strings.TrimSpace(ns); len(ns) > 0 {
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSEStream(t *testing.T) {
+	assert := assert.New(t)
+
+	hub := NewHub()
+	h := NewSSEHandler(
+		&config.SSE{Enabled: true, Token: "secret", BufferSize: 10},
+		hub)
+	srv := httptest.NewServer(http.HandlerFunc(h.handleStream))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		srv.URL+"?namespace=default,payments&token=secret",
+		nil)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(err)
+	defer resp.Body.Close()
+
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	hub.SendEvent(&event.Event{Namespace: "kube-system", PodName: "dns"})
+	hub.SendEvent(&event.Event{
+		Namespace: "payments",
+		PodName:   "api-0",
+		Reason:    "OOMKilled",
+	})
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.Nil(err)
+	assert.Equal("event: alert\n", line)
+
+	line, err = reader.ReadString('\n')
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(line, "data: "))
+
+	alert := map[string]interface{}{}
+	assert.Nil(json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &alert))
+	assert.Equal("payments", alert["namespace"])
+	assert.Equal("api-0", alert["pod"])
+	assert.Equal("OOMKilled", alert["reason"])
+}
+
+func TestSSEUnauthorized(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewSSEHandler(
+		&config.SSE{Enabled: true, Token: "secret", BufferSize: 10},
+		NewHub())
+
+	rr := httptest.NewRecorder()
+	h.handleStream(rr, httptest.NewRequest(http.MethodGet, "/?token=x", nil))
+	assert.Equal(http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	h.handleStream(rr, req)
+	assert.Equal(http.StatusUnauthorized, rr.Code)
+}
+
+func TestParseNamespaces(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		[]string{"default", "payments", "kube"},
+		parseNamespaces([]string{"default, payments", "kube", ""}))
+	assert.Len(parseNamespaces(nil), 0)
+}
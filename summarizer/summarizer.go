@@ -0,0 +1,151 @@
+package summarizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/sirupsen/logrus"
+)
+
+const systemPrompt = "You are a Kubernetes expert helping on-call engineers. " +
+	"Given the failure context of a pod, reply with a 2-3 sentence " +
+	"plain-language summary of what happened and its likely cause. " +
+	"Do not use markdown."
+
+type Summarizer struct {
+	config *config.Summarizer
+	client *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model     string        `json:"model"`
+	Messages  []chatMessage `json:"messages"`
+	MaxTokens int           `json:"max_tokens,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// NewSummarizer returns new instance of summarizer
+func NewSummarizer(config *config.Summarizer) *Summarizer {
+	// default transport is used, so configured proxy is respected
+	return &Summarizer{
+		config: config,
+		client: &http.Client{
+			Timeout: time.Duration(config.Timeout) * time.Second,
+		},
+	}
+}
+
+// Enabled returns true if summarizer is enabled and endpoint is set
+func (s *Summarizer) Enabled() bool {
+	return s != nil && s.config.Enabled && len(s.config.Endpoint) > 0
+}
+
+// Summarize returns generated summary of the event if summarizer is enabled,
+// failures are logged and an empty summary is returned so messages are still
+// sent
+func (s *Summarizer) Summarize(ev *event.Event) string {
+	if !s.Enabled() {
+		return ""
+	}
+
+	summary, err := s.summarize(ev)
+	if err != nil {
+		logrus.Warnf("failed to summarize event: %s", err.Error())
+		return ""
+	}
+
+	return summary
+}
+
+func (s *Summarizer) summarize(ev *event.Event) (string, error) {
+	reqBody, err := json.Marshal(chatRequest{
+		Model: s.config.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: buildPrompt(ev)},
+		},
+		MaxTokens: s.config.MaxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	url := strings.TrimSuffix(s.config.Endpoint, "/") + "/chat/completions"
+	request, err := http.NewRequest(
+		http.MethodPost,
+		url,
+		bytes.NewBuffer(reqBody))
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if len(s.config.APIKey) > 0 {
+		request.Header.Set("Authorization", "Bearer "+s.config.APIKey)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(
+			"call to summarizer returned status code %d: %s",
+			response.StatusCode,
+			string(body))
+	}
+
+	var result chatResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("summarizer returned no choices")
+	}
+
+	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+}
+
+// buildPrompt returns failure context of the event sent to the model
+func buildPrompt(ev *event.Event) string {
+	return fmt.Sprintf(
+		"Pod: %s\n"+
+			"Container: %s\n"+
+			"Namespace: %s\n"+
+			"Reason: %s\n"+
+			"%s\n"+
+			"Events:\n%s\n\n"+
+			"Logs:\n%s\n",
+		ev.PodName,
+		ev.ContainerName,
+		ev.Namespace,
+		ev.Reason,
+		ev.FormatExtraFieldsText(),
+		ev.Events,
+		ev.Logs)
+}
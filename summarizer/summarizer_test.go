@@ -0,0 +1,92 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSummarizer(&config.Summarizer{
+		Enabled:  false,
+		Endpoint: "http://localhost",
+	})
+	assert.False(s.Enabled())
+	assert.Empty(s.Summarize(&event.Event{}))
+
+	s = NewSummarizer(&config.Summarizer{Enabled: true})
+	assert.False(s.Enabled())
+	assert.Empty(s.Summarize(&event.Event{}))
+
+	var nilSummarizer *Summarizer
+	assert.False(nilSummarizer.Enabled())
+}
+
+func TestSummarize(t *testing.T) {
+	assert := assert.New(t)
+
+	s := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal("/v1/chat/completions", r.URL.Path)
+			assert.Equal("Bearer test", r.Header.Get("Authorization"))
+
+			var req chatRequest
+			assert.Nil(json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal("test-model", req.Model)
+			assert.Len(req.Messages, 2)
+			assert.Contains(req.Messages[1].Content, "OOMKilled")
+
+			w.Write([]byte(`{"choices": [{"message": {
+				"role": "assistant",
+				"content": " Container ran out of memory. "
+			}}]}`))
+		}))
+	defer s.Close()
+
+	summarizer := NewSummarizer(&config.Summarizer{
+		Enabled:  true,
+		Endpoint: s.URL + "/v1/",
+		APIKey:   "test",
+		Model:    "test-model",
+		Timeout:  5,
+	})
+
+	summary := summarizer.Summarize(&event.Event{
+		PodName: "test-pod",
+		Reason:  "OOMKilled",
+	})
+	assert.Equal("Container ran out of memory.", summary)
+}
+
+func TestSummarizeError(t *testing.T) {
+	assert := assert.New(t)
+
+	s := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+	defer s.Close()
+
+	summarizer := NewSummarizer(&config.Summarizer{
+		Enabled:  true,
+		Endpoint: s.URL,
+		Timeout:  5,
+	})
+	assert.Empty(summarizer.Summarize(&event.Event{}))
+
+	invalid := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"choices": []}`))
+		}))
+	defer invalid.Close()
+
+	summarizer.config.Endpoint = invalid.URL
+	assert.Empty(summarizer.Summarize(&event.Event{}))
+}
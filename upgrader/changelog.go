@@ -0,0 +1,93 @@
+package upgrader
+
+import (
+	"strings"
+)
+
+const (
+	// maxBreakingChanges is max number of breaking changes highlighted
+	maxBreakingChanges = 5
+
+	// defaultChangelogMaxLength is max length of changelog excerpt if it's
+	// not configured
+	defaultChangelogMaxLength = 500
+)
+
+// changelogMessage returns breaking changes and truncated changelog of
+// release notes appended to update notifications
+func (u *Upgrader) changelogMessage(notes string) string {
+	notes = strings.TrimSpace(strings.ReplaceAll(notes, "\r\n", "\n"))
+	if u.config.DisableChangelog || len(notes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if breaking := getBreakingChanges(notes); len(breaking) > 0 {
+		b.WriteString("\n\n:warning: Breaking changes:")
+		for _, change := range breaking {
+			b.WriteString("\n• " + change)
+		}
+	}
+
+	maxLength := u.config.ChangelogMaxLength
+	if maxLength <= 0 {
+		maxLength = defaultChangelogMaxLength
+	}
+	b.WriteString("\n\nChangelog:\n")
+	b.WriteString(truncateChangelog(notes, maxLength))
+
+	return b.String()
+}
+
+// getBreakingChanges returns items of breaking changes sections of release
+// notes in markdown and lines mentioning breaking changes
+func getBreakingChanges(notes string) []string {
+	changes := make([]string, 0)
+	inSection := false
+	for _, line := range strings.Split(notes, "\n") {
+		line = strings.TrimSpace(line)
+		isBreaking := strings.Contains(strings.ToLower(line), "breaking")
+
+		if strings.HasPrefix(line, "#") {
+			inSection = isBreaking
+			continue
+		}
+
+		item, isItem := trimListMarker(line)
+		if len(item) == 0 || !(isBreaking || (inSection && isItem)) {
+			continue
+		}
+
+		changes = append(changes, item)
+		if len(changes) == maxBreakingChanges {
+			break
+		}
+	}
+	return changes
+}
+
+// trimListMarker returns line without markdown list marker, and whether
+// line is a list item
+func trimListMarker(line string) (string, bool) {
+	for _, marker := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(line[len(marker):]), true
+		}
+	}
+	return line, false
+}
+
+// truncateChangelog returns notes cut to maxLength characters, at end of a
+// line if possible
+func truncateChangelog(notes string, maxLength int) string {
+	runes := []rune(notes)
+	if len(runes) <= maxLength {
+		return notes
+	}
+
+	excerpt := string(runes[:maxLength])
+	if i := strings.LastIndex(excerpt, "\n"); i > len(excerpt)/2 {
+		excerpt = excerpt[:i]
+	}
+	return strings.TrimSpace(excerpt) + "\n…"
+}
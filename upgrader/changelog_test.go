@@ -0,0 +1,57 @@
+package upgrader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const testNotes = "## What's Changed\r\n" +
+	"- Add digest by @dev\r\n" +
+	"- BREAKING: rename app.logFormatter to logging.format\r\n" +
+	"\r\n" +
+	"## Breaking Changes\r\n" +
+	"* drop support for Kubernetes 1.25\r\n" +
+	"\r\n" +
+	"## Fixes\r\n" +
+	"- fix crash on empty logs\r\n"
+
+func TestGetBreakingChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		[]string{
+			"BREAKING: rename app.logFormatter to logging.format",
+			"drop support for Kubernetes 1.25",
+		},
+		getBreakingChanges(strings.ReplaceAll(testNotes, "\r\n", "\n")))
+	assert.Empty(getBreakingChanges("- fix crash"))
+}
+
+func TestChangelogMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := &config.Upgrader{ChangelogMaxLength: 40}
+	u := NewUpgrader(cfg, nil, nil)
+
+	msg := u.changelogMessage(testNotes)
+	assert.Contains(msg, "Breaking changes:\n• BREAKING: rename")
+	assert.Contains(msg, "• drop support for Kubernetes 1.25")
+	assert.Contains(msg, "Changelog:\n## What's Changed\n- Add digest by @dev\n…")
+	assert.NotContains(msg, "fix crash")
+	assert.NotContains(msg, "\r")
+
+	assert.Empty(u.changelogMessage(""))
+
+	cfg.DisableChangelog = true
+	assert.Empty(u.changelogMessage(testNotes))
+}
+
+func TestTruncateChangelog(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("short", truncateChangelog("short", 10))
+	assert.Equal("ééé\n…", truncateChangelog("éééééé", 3))
+}
@@ -0,0 +1,134 @@
+package upgrader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// helmManagedBy is value of managed-by label of resources installed by
+	// Helm
+	helmManagedBy = "Helm"
+
+	// helmChartRepo is chart reference of kwatch in its Helm repository
+	helmChartRepo = "kwatch/kwatch"
+)
+
+// HelmRelease is Helm release kwatch was installed with
+type HelmRelease struct {
+	Name      string
+	Namespace string
+
+	// ChartVersion is version of installed chart
+	ChartVersion string
+}
+
+// UpgradeCommand returns command upgrading release to chart of tag
+func (r *HelmRelease) UpgradeCommand(tag string) string {
+	return fmt.Sprintf(
+		"helm upgrade %s %s --version %s --namespace %s --reuse-values",
+		r.Name,
+		helmChartRepo,
+		ChartVersion(tag),
+		r.Namespace)
+}
+
+// ChartVersion returns version of chart releasing kwatch tag, charts are
+// versioned as kwatch without v prefix
+func ChartVersion(tag string) string {
+	return strings.TrimPrefix(tag, "v")
+}
+
+// DetectHelmRelease returns Helm release of running kwatch using labels and
+// annotations of its pod or pod owners, or nil if it wasn't installed by
+// Helm
+func DetectHelmRelease(client kubernetes.Interface) *HelmRelease {
+	name := os.Getenv("POD_NAME")
+	namespace := os.Getenv("POD_NAMESPACE")
+	if len(name) == 0 || len(namespace) == 0 {
+		return nil
+	}
+
+	pod, err := client.CoreV1().
+		Pods(namespace).
+		Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		logrus.WithError(err).Debug("failed to get kwatch pod")
+		return nil
+	}
+
+	meta := pod.ObjectMeta
+	for {
+		if release := helmReleaseOf(meta); release != nil {
+			return release
+		}
+
+		owner := metav1.GetControllerOf(&meta)
+		if owner == nil {
+			return nil
+		}
+
+		switch owner.Kind {
+		case "ReplicaSet":
+			rs, err := client.AppsV1().
+				ReplicaSets(namespace).
+				Get(context.TODO(), owner.Name, metav1.GetOptions{})
+			if err != nil {
+				logrus.WithError(err).Debug("failed to get kwatch replicaset")
+				return nil
+			}
+			meta = rs.ObjectMeta
+		case "Deployment":
+			d, err := client.AppsV1().
+				Deployments(namespace).
+				Get(context.TODO(), owner.Name, metav1.GetOptions{})
+			if err != nil {
+				logrus.WithError(err).Debug("failed to get kwatch deployment")
+				return nil
+			}
+			meta = d.ObjectMeta
+		default:
+			return nil
+		}
+	}
+}
+
+// helmReleaseOf returns Helm release of object managed by Helm, or nil
+func helmReleaseOf(meta metav1.ObjectMeta) *HelmRelease {
+	if meta.Labels["app.kubernetes.io/managed-by"] != helmManagedBy {
+		return nil
+	}
+
+	release := &HelmRelease{
+		Name:         meta.Annotations["meta.helm.sh/release-name"],
+		Namespace:    meta.Annotations["meta.helm.sh/release-namespace"],
+		ChartVersion: chartVersionOf(meta.Labels["helm.sh/chart"]),
+	}
+	if len(release.Name) == 0 {
+		release.Name = meta.Labels["app.kubernetes.io/instance"]
+	}
+	if len(release.Namespace) == 0 {
+		release.Namespace = meta.Namespace
+	}
+	if len(release.Name) == 0 {
+		return nil
+	}
+
+	return release
+}
+
+// chartVersionOf returns version of chart label e.g. kwatch-0.9.3
+func chartVersionOf(label string) string {
+	for i := 0; i < len(label)-1; i++ {
+		if label[i] == '-' && label[i+1] >= '0' && label[i+1] <= '9' {
+			return label[i+1:]
+		}
+	}
+	return ""
+}
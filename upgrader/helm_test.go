@@ -0,0 +1,94 @@
+package upgrader
+
+import (
+	"os"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDetectHelmRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	isController := true
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kwatch",
+			Namespace: "monitoring",
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "Helm",
+				"app.kubernetes.io/instance":   "kwatch",
+				"helm.sh/chart":                "kwatch-0.9.3",
+			},
+			Annotations: map[string]string{
+				"meta.helm.sh/release-name":      "watcher",
+				"meta.helm.sh/release-namespace": "monitoring",
+			},
+		},
+	}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kwatch-5d8f7",
+			Namespace: "monitoring",
+			OwnerReferences: []metav1.OwnerReference{{
+				Kind:       "Deployment",
+				Name:       "kwatch",
+				Controller: &isController,
+			}},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kwatch-5d8f7-abcde",
+			Namespace: "monitoring",
+			OwnerReferences: []metav1.OwnerReference{{
+				Kind:       "ReplicaSet",
+				Name:       "kwatch-5d8f7",
+				Controller: &isController,
+			}},
+		},
+	}
+	client := fake.NewSimpleClientset(deployment, rs, pod)
+
+	// not running in a pod
+	assert.Nil(DetectHelmRelease(client))
+
+	os.Setenv("POD_NAME", "kwatch-5d8f7-abcde")
+	os.Setenv("POD_NAMESPACE", "monitoring")
+	defer os.Unsetenv("POD_NAME")
+	defer os.Unsetenv("POD_NAMESPACE")
+
+	release := DetectHelmRelease(client)
+	assert.NotNil(release)
+	assert.Equal(&HelmRelease{
+		Name:         "watcher",
+		Namespace:    "monitoring",
+		ChartVersion: "0.9.3",
+	}, release)
+
+	u := NewUpgrader(&config.Upgrader{}, nil, nil)
+	u.helmRelease = release
+	assert.Contains(u.updateMessage("v0.10.0"), "chart 0.9.3")
+	assert.Contains(
+		u.updateMessage("v0.10.0"),
+		"helm upgrade watcher kwatch/kwatch --version 0.10.0 "+
+			"--namespace monitoring --reuse-values")
+
+	// not installed by Helm
+	deployment.Labels = nil
+	client = fake.NewSimpleClientset(deployment, rs, pod)
+	assert.Nil(DetectHelmRelease(client))
+}
+
+func TestChartVersionOf(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0.9.3", chartVersionOf("kwatch-0.9.3"))
+	assert.Equal("0.10.0-rc1", chartVersionOf("my-kwatch-0.10.0-rc1"))
+	assert.Equal("", chartVersionOf("kwatch"))
+}
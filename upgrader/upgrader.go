@@ -2,7 +2,13 @@ package upgrader
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/abahmed/kwatch/alertmanager"
@@ -11,33 +17,124 @@ import (
 	"github.com/abahmed/kwatch/version"
 	"github.com/google/go-github/v41/github"
 	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// requestTimeout is timeout of release requests
+	requestTimeout = 30 * time.Second
+
+	// maxResponseSize is max size of custom release endpoints responses
+	maxResponseSize = 1 << 20
 )
 
 type Upgrader struct {
 	config       *config.Upgrader
 	alertManager *alertmanager.AlertManager
+	client       *http.Client
+	kubeClient   kubernetes.Interface
+	helmRelease  *HelmRelease
+
+	// offline is true if kwatch runs in air-gapped cluster
+	offline bool
+
+	// latestRelease is tag of newer release found by last check
+	latestRelease   string
+	latestReleaseMu sync.RWMutex
+}
+
+// ChannelPrerelease is release channel including release candidates
+const ChannelPrerelease = "prerelease"
+
+// release is a release returned by custom release endpoints, GitHub
+// releases API returns tag_name and body while internal endpoints may return
+// version and notes
+type release struct {
+	TagName    string `json:"tag_name"`
+	Version    string `json:"version"`
+	Prerelease bool   `json:"prerelease"`
+	Draft      bool   `json:"draft"`
+	Body       string `json:"body"`
+	Notes      string `json:"notes"`
+}
+
+// tag returns tag of release
+func (r *release) tag() string {
+	if len(r.TagName) > 0 {
+		return r.TagName
+	}
+	return r.Version
+}
+
+// notes returns release notes in markdown
+func (r *release) notes() string {
+	if len(r.Body) > 0 {
+		return r.Body
+	}
+	return r.Notes
 }
 
 // NewUpgrader returns new instance of upgrader
 func NewUpgrader(config *config.Upgrader,
+	appCfg *config.App,
 	alertManager *alertmanager.AlertManager) *Upgrader {
+	proxy := http.ProxyFromEnvironment
+	if appCfg != nil && len(appCfg.ProxyURL) > 0 {
+		if proxyURL, err := url.Parse(appCfg.ProxyURL); err == nil {
+			proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
 	return &Upgrader{
 		config:       config,
 		alertManager: alertManager,
+		offline:      appCfg != nil && appCfg.Offline,
+		client: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{Proxy: proxy},
+		},
 	}
 }
 
-// CheckUpdates checks every 24 hours if a newer version of Kwatch is available
+// SetKubeClient sets client used to detect Helm release kwatch was
+// installed with, so update notifications include its upgrade command
+func (u *Upgrader) SetKubeClient(client kubernetes.Interface) {
+	u.kubeClient = client
+}
+
+// CheckUpdates checks on configured interval if a newer version of Kwatch is
+// available
 func (u *Upgrader) CheckUpdates() {
 	if u.config.DisableUpdateCheck ||
 		version.Short() == "dev" {
 		return
 	}
 
+	if u.offline {
+		logrus.Info("update checks are disabled in offline mode")
+		return
+	}
+
+	if u.kubeClient != nil {
+		u.helmRelease = DetectHelmRelease(u.kubeClient)
+		if u.helmRelease != nil {
+			logrus.WithFields(logrus.Fields{
+				"release":   u.helmRelease.Name,
+				"namespace": u.helmRelease.Namespace,
+				"chart":     u.helmRelease.ChartVersion,
+			}).Debug("kwatch was installed with Helm")
+		}
+	}
+
 	// check at startup
 	u.checkRelease()
 
-	ticker := time.NewTicker(24 * time.Hour)
+	interval := u.config.Interval
+	if interval <= 0 {
+		interval = 24
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Hour)
 	defer ticker.Stop()
 
 	for range ticker.C {
@@ -46,25 +143,148 @@ func (u *Upgrader) CheckUpdates() {
 }
 
 func (u *Upgrader) checkRelease() {
-	client := github.NewClient(nil)
-
-	r, _, err := client.Repositories.GetLatestRelease(
-		context.TODO(),
-		"abahmed",
-		"kwatch")
+	r, err := u.getLatestRelease()
 	if err != nil {
 		logrus.Warnf("failed to get latest release: %s", err.Error())
 		return
 	}
 
-	if r.TagName == nil {
-		logrus.Warnf("failed to get release tag: %+v", r)
-		return
+	tag := r.tag()
+	latest := tag
+	if version.Short() == tag {
+		latest = ""
 	}
 
-	if version.Short() == *r.TagName {
+	u.latestReleaseMu.Lock()
+	u.latestRelease = latest
+	u.latestReleaseMu.Unlock()
+
+	if len(latest) == 0 {
 		return
 	}
 
-	u.alertManager.Notify(fmt.Sprintf(constant.KwatchUpdateMsg, *r.TagName))
+	u.alertManager.NotifyKind(
+		alertmanager.MessageUpdate,
+		u.updateMessage(tag)+u.changelogMessage(r.notes()))
+}
+
+// LatestRelease returns tag of newer release found by last check, or empty
+// string if running version is the latest one
+func (u *Upgrader) LatestRelease() string {
+	u.latestReleaseMu.RLock()
+	defer u.latestReleaseMu.RUnlock()
+	return u.latestRelease
+}
+
+// updateMessage returns update notification of tag, including chart
+// version and upgrade command if kwatch was installed with Helm
+func (u *Upgrader) updateMessage(tag string) string {
+	msg := fmt.Sprintf(constant.KwatchUpdateMsg, tag)
+	if u.helmRelease == nil {
+		return msg
+	}
+
+	return msg + fmt.Sprintf(
+		constant.KwatchHelmUpdateMsg,
+		u.helmRelease.Name,
+		u.helmRelease.ChartVersion,
+		ChartVersion(tag),
+		u.helmRelease.UpgradeCommand(tag))
+}
+
+// getLatestRelease returns latest release of configured channel using
+// configured release endpoint, or github.com if it's not set
+func (u *Upgrader) getLatestRelease() (*release, error) {
+	if len(u.config.ReleaseURL) > 0 {
+		return u.getCustomRelease()
+	}
+
+	client := github.NewClient(u.client)
+
+	// latest release API skips pre-releases
+	if u.config.Channel != ChannelPrerelease {
+		r, _, err := client.Repositories.GetLatestRelease(
+			context.TODO(),
+			"abahmed",
+			"kwatch")
+		if err != nil {
+			return nil, err
+		}
+
+		if r.TagName == nil {
+			return nil, fmt.Errorf("failed to get release tag: %+v", r)
+		}
+
+		return &release{TagName: r.GetTagName(), Body: r.GetBody()}, nil
+	}
+
+	releases, _, err := client.Repositories.ListReleases(
+		context.TODO(),
+		"abahmed",
+		"kwatch",
+		&github.ListOptions{PerPage: 10})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range releases {
+		if !r.GetDraft() && len(r.GetTagName()) > 0 {
+			return &release{TagName: r.GetTagName(), Body: r.GetBody()}, nil
+		}
+	}
+	return nil, errors.New("no release found")
+}
+
+// getCustomRelease returns latest release returned by configured release
+// endpoint
+func (u *Upgrader) getCustomRelease() (*release, error) {
+	req, err := http.NewRequest(http.MethodGet, u.config.ReleaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(u.config.ReleaseToken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+u.config.ReleaseToken)
+	}
+
+	response, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"call to release endpoint returned status code %d",
+			response.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// endpoint returns either latest release or list of releases newest
+	// first e.g. releases API of GitHub Enterprise
+	releases := make([]release, 0)
+	if err := json.Unmarshal(body, &releases); err != nil {
+		r := release{}
+		if err := json.Unmarshal(body, &r); err != nil {
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft ||
+			(r.Prerelease && u.config.Channel != ChannelPrerelease) {
+			continue
+		}
+
+		if len(r.tag()) > 0 {
+			return r, nil
+		}
+	}
+	return nil, errors.New("no release with tag_name or version found")
 }
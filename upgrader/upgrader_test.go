@@ -0,0 +1,84 @@
+package upgrader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCustomRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	var auth string
+	body := `{"tag_name": "v0.10.0"}`
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+			w.Write([]byte(body))
+		}))
+	defer srv.Close()
+
+	u := NewUpgrader(
+		&config.Upgrader{ReleaseURL: srv.URL, ReleaseToken: "secret"},
+		nil,
+		nil)
+
+	r, err := u.getLatestRelease()
+	assert.Nil(err)
+	assert.Equal("v0.10.0", r.tag())
+	assert.Equal("Bearer secret", auth)
+
+	body = `{"version": "v0.11.0", "notes": "- fixed crash"}`
+	r, err = u.getLatestRelease()
+	assert.Nil(err)
+	assert.Equal("v0.11.0", r.tag())
+	assert.Equal("- fixed crash", r.notes())
+
+	body = `{"name": "latest"}`
+	_, err = u.getLatestRelease()
+	assert.NotNil(err)
+}
+
+func TestGetCustomReleaseChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[
+				{"tag_name": "v0.11.0", "draft": true},
+				{"tag_name": "v0.11.0-rc1", "prerelease": true},
+				{"tag_name": "v0.10.0"}
+			]`))
+		}))
+	defer srv.Close()
+
+	cfg := &config.Upgrader{ReleaseURL: srv.URL, Channel: "stable"}
+	u := NewUpgrader(cfg, &config.App{}, nil)
+
+	r, err := u.getLatestRelease()
+	assert.Nil(err)
+	assert.Equal("v0.10.0", r.tag())
+
+	cfg.Channel = ChannelPrerelease
+	r, err = u.getLatestRelease()
+	assert.Nil(err)
+	assert.Equal("v0.11.0-rc1", r.tag())
+}
+
+func TestGetCustomReleaseFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	defer srv.Close()
+
+	u := NewUpgrader(&config.Upgrader{ReleaseURL: srv.URL}, nil, nil)
+
+	_, err := u.getLatestRelease()
+	assert.NotNil(err)
+}
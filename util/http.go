@@ -0,0 +1,171 @@
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/abahmed/kwatch/offline"
+	"golang.org/x/oauth2"
+)
+
+// ProxyDirect is proxy option of providers bypassing global proxy
+const ProxyDirect = "direct"
+
+// DefaultProviderTimeout is timeout of provider requests if it isn't set,
+// so hanging endpoints don't stall sending of notifications
+const DefaultProviderTimeout = 30 * time.Second
+
+// baseTransport is default transport before it's wrapped e.g. by offline
+// mode, provider transports are cloned from it
+var baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// NewHTTPClient returns http client of provider using its proxyURL option:
+// a proxy URL, direct to bypass global proxy, or global proxy if it's
+// empty, its tls option, its oauth2 option and its timeout option
+func NewHTTPClient(options map[string]interface{}) (*http.Client, error) {
+	timeout, err := GetProviderTimeout(options)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyURL, _ := options["proxyURL"].(string)
+	tlsConfig, err := NewTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	oauth2Config, err := NewOAuth2Config(options)
+	if err != nil {
+		return nil, err
+	}
+	if len(proxyURL) == 0 && tlsConfig == nil && oauth2Config == nil {
+		return &http.Client{Timeout: timeout}, nil
+	}
+
+	transport := baseTransport.Clone()
+	if proxyURL == ProxyDirect {
+		transport.Proxy = nil
+	} else if len(proxyURL) > 0 {
+		u, err := url.Parse(proxyURL)
+		if err != nil || len(u.Host) == 0 {
+			return nil, fmt.Errorf("invalid proxy url %s", proxyURL)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	client := &http.Client{
+		Transport: offline.Wrap(transport),
+		Timeout:   timeout,
+	}
+	if oauth2Config == nil {
+		return client, nil
+	}
+
+	// tokens are fetched through the same transport, cached and refreshed
+	// before they expire
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+	return &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2Config.TokenSource(ctx),
+			Base:   client.Transport,
+		},
+		Timeout: timeout,
+	}, nil
+}
+
+// GetProviderTimeout returns timeout of provider requests of its timeout
+// option in seconds, or DefaultProviderTimeout if it isn't set
+func GetProviderTimeout(
+	options map[string]interface{}) (time.Duration, error) {
+	var timeout time.Duration
+	switch seconds := options["timeout"].(type) {
+	case nil:
+		return DefaultProviderTimeout, nil
+	case int:
+		timeout = time.Duration(seconds) * time.Second
+	case float64:
+		timeout = time.Duration(seconds * float64(time.Second))
+	default:
+		return 0, fmt.Errorf("timeout must be number of seconds")
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+	return timeout, nil
+}
+
+// NewTLSConfig returns tls config of provider's tls option with CA bundle
+// and client certificate as files or inline PEM, or nil if it isn't set
+// e.g. {caFile: /etc/ssl/corp.pem, certFile: ..., keyFile: ...}
+func NewTLSConfig(options map[string]interface{}) (*tls.Config, error) {
+	opts, ok := options["tls"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	tlsConfig.InsecureSkipVerify, _ = opts["insecureSkipVerify"].(bool)
+
+	ca, err := getPEM(opts, "ca", "caFile")
+	if err != nil {
+		return nil, err
+	}
+	if len(ca) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	cert, err := getPEM(opts, "cert", "certFile")
+	if err != nil {
+		return nil, err
+	}
+	key, err := getPEM(opts, "key", "keyFile")
+	if err != nil {
+		return nil, err
+	}
+	if len(cert) > 0 || len(key) > 0 {
+		certificate, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// getPEM returns inline PEM of option, or content of file of fileOption
+func getPEM(
+	opts map[string]interface{},
+	option string,
+	fileOption string) ([]byte, error) {
+	if pem, _ := opts[option].(string); len(pem) > 0 {
+		return []byte(pem), nil
+	}
+
+	path, _ := opts[fileOption].(string)
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fileOption, err)
+	}
+	return pem, nil
+}
@@ -0,0 +1,214 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	assert := assert.New(t)
+
+	client, err := NewHTTPClient(map[string]interface{}{})
+	assert.Nil(err)
+	assert.Nil(client.Transport)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+
+	client, err = NewHTTPClient(map[string]interface{}{
+		"proxyURL": "http://proxy.corp:3128",
+	})
+	assert.Nil(err)
+	proxy, _ := client.Transport.(*http.Transport).Proxy(req)
+	assert.Equal("proxy.corp:3128", proxy.Host)
+
+	client, err = NewHTTPClient(map[string]interface{}{
+		"proxyURL": ProxyDirect,
+	})
+	assert.Nil(err)
+	assert.Nil(client.Transport.(*http.Transport).Proxy)
+
+	_, err = NewHTTPClient(map[string]interface{}{"proxyURL": "proxy"})
+	assert.NotNil(err)
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	tlsConfig, err := NewTLSConfig(map[string]interface{}{})
+	assert.Nil(err)
+	assert.Nil(tlsConfig)
+
+	certPEM, keyPEM := newTestCertificate(t)
+
+	tlsConfig, err = NewTLSConfig(map[string]interface{}{
+		"tls": map[string]interface{}{
+			"ca":                 string(certPEM),
+			"cert":               string(certPEM),
+			"key":                string(keyPEM),
+			"insecureSkipVerify": true,
+		},
+	})
+	assert.Nil(err)
+	assert.NotNil(tlsConfig.RootCAs)
+	assert.Len(tlsConfig.Certificates, 1)
+	assert.True(tlsConfig.InsecureSkipVerify)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	assert.Nil(os.WriteFile(caFile, certPEM, 0600))
+
+	client, err := NewHTTPClient(map[string]interface{}{
+		"tls": map[string]interface{}{"caFile": caFile},
+	})
+	assert.Nil(err)
+	transport := client.Transport.(*http.Transport)
+	assert.NotNil(transport.TLSClientConfig.RootCAs)
+	assert.Nil(transport.TLSClientConfig.Certificates)
+
+	_, err = NewTLSConfig(map[string]interface{}{
+		"tls": map[string]interface{}{"ca": "invalid"},
+	})
+	assert.NotNil(err)
+
+	_, err = NewTLSConfig(map[string]interface{}{
+		"tls": map[string]interface{}{
+			"caFile": filepath.Join(dir, "missing.pem"),
+		},
+	})
+	assert.NotNil(err)
+
+	_, err = NewTLSConfig(map[string]interface{}{
+		"tls": map[string]interface{}{"cert": string(certPEM)},
+	})
+	assert.NotNil(err)
+}
+
+// newTestCertificate returns PEM of self-signed certificate and its key
+func newTestCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kwatch-test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&key.PublicKey,
+		key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestNewHTTPClientOAuth2(t *testing.T) {
+	assert := assert.New(t)
+
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenRequests++
+			r.ParseForm()
+			assert.Equal("client_credentials", r.Form.Get("grant_type"))
+			assert.Equal("alerts.write", r.Form.Get("scope"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"t0ken","token_type":"Bearer",` +
+				`"expires_in":3600}`))
+		}))
+	defer tokenServer.Close()
+
+	var authorization string
+	s := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+		}))
+	defer s.Close()
+
+	client, err := NewHTTPClient(map[string]interface{}{
+		"oauth2": map[string]interface{}{
+			"tokenURL":     tokenServer.URL,
+			"clientID":     "kwatch",
+			"clientSecret": "s3cr3t",
+			"scopes":       []interface{}{"alerts.write"},
+		},
+	})
+	assert.Nil(err)
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Get(s.URL)
+		assert.Nil(err)
+		response.Body.Close()
+	}
+	assert.Equal("Bearer t0ken", authorization)
+	assert.Equal(1, tokenRequests)
+
+	_, err = NewHTTPClient(map[string]interface{}{
+		"oauth2": map[string]interface{}{"tokenURL": tokenServer.URL},
+	})
+	assert.NotNil(err)
+}
+
+func TestGetProviderTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	timeout, err := GetProviderTimeout(map[string]interface{}{})
+	assert.Nil(err)
+	assert.Equal(DefaultProviderTimeout, timeout)
+
+	timeout, err = GetProviderTimeout(map[string]interface{}{"timeout": 5})
+	assert.Nil(err)
+	assert.Equal(5*time.Second, timeout)
+
+	timeout, err = GetProviderTimeout(map[string]interface{}{"timeout": 0.5})
+	assert.Nil(err)
+	assert.Equal(500*time.Millisecond, timeout)
+
+	_, err = GetProviderTimeout(map[string]interface{}{"timeout": "5s"})
+	assert.NotNil(err)
+
+	_, err = GetProviderTimeout(map[string]interface{}{"timeout": -1})
+	assert.NotNil(err)
+
+	client, err := NewHTTPClient(map[string]interface{}{})
+	assert.Nil(err)
+	assert.Equal(DefaultProviderTimeout, client.Timeout)
+
+	client, err = NewHTTPClient(map[string]interface{}{
+		"proxyURL": ProxyDirect,
+		"timeout":  10,
+	})
+	assert.Nil(err)
+	assert.Equal(10*time.Second, client.Timeout)
+
+	_, err = NewHTTPClient(map[string]interface{}{"timeout": 0})
+	assert.NotNil(err)
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"fmt"
+
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// NewOAuth2Config returns client credentials config of provider's oauth2
+// option, or nil if it isn't set e.g.
+// {tokenURL: https://auth.corp/token, clientID: kwatch, clientSecret: ...}
+func NewOAuth2Config(
+	options map[string]interface{}) (*clientcredentials.Config, error) {
+	opts, ok := options["oauth2"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	tokenURL, _ := opts["tokenURL"].(string)
+	clientID, _ := opts["clientID"].(string)
+	clientSecret, _ := opts["clientSecret"].(string)
+	if len(tokenURL) == 0 || len(clientID) == 0 || len(clientSecret) == 0 {
+		return nil, fmt.Errorf(
+			"oauth2 requires tokenURL, clientID and clientSecret")
+	}
+
+	scopes := make([]string, 0)
+	if rawScopes, ok := opts["scopes"].([]interface{}); ok {
+		for _, scope := range rawScopes {
+			if s, ok := scope.(string); ok && len(s) > 0 {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+
+	return &clientcredentials.Config{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+	}, nil
+}
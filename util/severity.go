@@ -0,0 +1,53 @@
+package util
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abahmed/kwatch/config"
+)
+
+// SeverityTargets are targets of provider e.g. webhooks or channels by
+// lowercase severity, so critical alerts can land in a separate channel
+type SeverityTargets map[string]string
+
+// NewSeverityTargets returns severity targets of provider option of given
+// name e.g. {critical: <webhook>, warning: <webhook>}, or nil if it isn't
+// set
+func NewSeverityTargets(
+	options map[string]interface{},
+	name string) (SeverityTargets, error) {
+	opt, ok := options[name]
+	if !ok {
+		return nil, nil
+	}
+
+	m, ok := opt.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object", name)
+	}
+
+	targets := make(SeverityTargets, len(m))
+	for severity, value := range m {
+		severity = strings.ToLower(severity)
+		if !slices.Contains(config.SeverityLevels, severity) {
+			return nil, fmt.Errorf("unknown severity %s of %s", severity, name)
+		}
+
+		target, _ := value.(string)
+		if len(target) == 0 {
+			return nil, fmt.Errorf("empty %s of severity %s", name, severity)
+		}
+		targets[severity] = target
+	}
+	return targets, nil
+}
+
+// Get returns target of severity, or defaultTarget if there's none
+func (t SeverityTargets) Get(severity string, defaultTarget string) string {
+	if target, ok := t[strings.ToLower(severity)]; ok {
+		return target
+	}
+	return defaultTarget
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSeverityTargets(t *testing.T) {
+	assert := assert.New(t)
+
+	targets, err := NewSeverityTargets(map[string]interface{}{}, "channels")
+	assert.Nil(err)
+	assert.Nil(targets)
+	assert.Equal("default", targets.Get("critical", "default"))
+
+	targets, err = NewSeverityTargets(map[string]interface{}{
+		"channels": map[string]interface{}{
+			"Critical": "#incidents",
+			"warning":  "#platform-noise",
+		},
+	}, "channels")
+	assert.Nil(err)
+	assert.Equal("#incidents", targets.Get("critical", "#alerts"))
+	assert.Equal("#platform-noise", targets.Get("WARNING", "#alerts"))
+	assert.Equal("#alerts", targets.Get("info", "#alerts"))
+	assert.Equal("#alerts", targets.Get("", "#alerts"))
+
+	_, err = NewSeverityTargets(map[string]interface{}{
+		"channels": "#incidents",
+	}, "channels")
+	assert.NotNil(err)
+
+	_, err = NewSeverityTargets(map[string]interface{}{
+		"channels": map[string]interface{}{"fatal": "#incidents"},
+	}, "channels")
+	assert.NotNil(err)
+
+	_, err = NewSeverityTargets(map[string]interface{}{
+		"channels": map[string]interface{}{"critical": ""},
+	}, "channels")
+	assert.NotNil(err)
+}
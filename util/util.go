@@ -5,17 +5,83 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"regexp"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
+const (
+	// redactedText replaces secrets masked in logs
+	redactedText = "[REDACTED]"
+
+	// truncatedTextFormat replaces the middle of truncated logs
+	truncatedTextFormat = "\n... [%d bytes truncated] ...\n"
+)
+
+// exitCodeDescriptions maps well-known container exit codes to human-readable
+// explanations
+var exitCodeDescriptions = map[int32]string{
+	1:   "general application error",
+	2:   "misuse of shell builtin",
+	126: "command cannot be executed",
+	127: "command not found",
+	128: "invalid exit argument",
+	130: "SIGINT: interrupted",
+	134: "SIGABRT: aborted, e.g. failed assertion",
+	137: "SIGKILL: killed, often out of memory or failed liveness probe",
+	139: "SIGSEGV: segmentation fault",
+	141: "SIGPIPE: wrote to a closed pipe",
+	143: "SIGTERM: terminated gracefully",
+	255: "exit status out of range",
+}
+
+// signalNames maps signal numbers to their names
+var signalNames = map[int32]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	4:  "SIGILL",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	8:  "SIGFPE",
+	9:  "SIGKILL",
+	11: "SIGSEGV",
+	13: "SIGPIPE",
+	14: "SIGALRM",
+	15: "SIGTERM",
+}
+
+// DescribeExitCode returns exit code with a human-readable explanation
+// e.g. 137 (SIGKILL: killed, often out of memory or failed liveness probe)
+func DescribeExitCode(code int32) string {
+	if description, ok := exitCodeDescriptions[code]; ok {
+		return fmt.Sprintf("%d (%s)", code, description)
+	}
+
+	// exit codes above 128 mean that process was killed by signal code-128
+	if code > 128 && code < 160 {
+		signal, ok := signalNames[code-128]
+		if !ok {
+			signal = fmt.Sprintf("signal %d", code-128)
+		}
+		return fmt.Sprintf("%d (%s: killed by signal)", code, signal)
+	}
+
+	return fmt.Sprintf("%d", code)
+}
+
 // GetPodEventsStr returns formatted events as a string for specified pod
 func GetPodEventsStr(events *[]v1.Event) string {
 	if events == nil {
@@ -36,6 +102,67 @@ func GetPodEventsStr(events *[]v1.Event) string {
 	return strings.TrimSpace(eventsString)
 }
 
+// GetRecentPodEventsTable returns most recent maxEvents events (all events if
+// maxEvents is 0) formatted as a compact table sorted from oldest to newest
+func GetRecentPodEventsTable(events *[]v1.Event, maxEvents int) string {
+	if events == nil || len(*events) == 0 {
+		return ""
+	}
+
+	sorted := make([]v1.Event, len(*events))
+	copy(sorted, *events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return getEventTime(&sorted[i]).Before(getEventTime(&sorted[j]))
+	})
+
+	if maxEvents > 0 && len(sorted) > maxEvents {
+		sorted = sorted[len(sorted)-maxEvents:]
+	}
+
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "AGE\tTYPE\tREASON\tCOUNT\tMESSAGE")
+	for i := range sorted {
+		ev := &sorted[i]
+
+		age := "<unknown>"
+		if eventTime := getEventTime(ev); !eventTime.IsZero() {
+			age = duration.HumanDuration(time.Since(eventTime))
+		}
+
+		count := ev.Count
+		if count == 0 {
+			count = 1
+		}
+
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%d\t%s\n",
+			age,
+			ev.Type,
+			ev.Reason,
+			count,
+			strings.Join(strings.Fields(ev.Message), " "))
+	}
+	w.Flush()
+
+	return strings.TrimSpace(table.String())
+}
+
+// getEventTime returns last time event was observed
+func getEventTime(ev *v1.Event) time.Time {
+	if !ev.LastTimestamp.IsZero() {
+		return ev.LastTimestamp.Time
+	}
+	if !ev.EventTime.IsZero() {
+		return ev.EventTime.Time
+	}
+	if !ev.FirstTimestamp.IsZero() {
+		return ev.FirstTimestamp.Time
+	}
+	return ev.CreationTimestamp.Time
+}
+
 // ContainsKillingStoppingContainerEvents checks if the events contain an event
 // with "Killing Stopping container" which indicates that a container could not
 // be gracefully shutdown
@@ -103,6 +230,156 @@ func GetPodContainerLogs(
 	return string(logs)
 }
 
+// FilterLogLines keeps log lines matching at least one of include patterns
+// (if any is provided) and drops lines matching any of exclude patterns
+func FilterLogLines(
+	logs string,
+	include []*regexp.Regexp,
+	exclude []*regexp.Regexp) string {
+	if len(include) == 0 && len(exclude) == 0 {
+		return logs
+	}
+
+	lines := strings.Split(logs, "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if len(include) > 0 && !matchesAny(line, include) {
+			continue
+		}
+
+		if matchesAny(line, exclude) {
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// TailLogLines returns last n lines of logs, if n is 0 it returns all lines
+func TailLogLines(logs string, n int64) string {
+	if n <= 0 {
+		return logs
+	}
+
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	if int64(len(lines)) <= n {
+		return logs
+	}
+
+	return strings.Join(lines[int64(len(lines))-n:], "\n")
+}
+
+// RedactLogs masks values matching patterns in logs, if a pattern has a
+// capture group named secret, only this group is masked
+func RedactLogs(logs string, patterns []*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		secretIdx := pattern.SubexpIndex("secret")
+		if secretIdx < 0 {
+			logs = pattern.ReplaceAllString(logs, redactedText)
+			continue
+		}
+
+		matches := pattern.FindAllStringSubmatchIndex(logs, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		var sb strings.Builder
+		last := 0
+		for _, match := range matches {
+			start, end := match[2*secretIdx], match[2*secretIdx+1]
+			if start < 0 {
+				continue
+			}
+			sb.WriteString(logs[last:start])
+			sb.WriteString(redactedText)
+			last = end
+		}
+		sb.WriteString(logs[last:])
+		logs = sb.String()
+	}
+
+	return logs
+}
+
+// TruncateLogs truncates logs exceeding maxBytes keeping the beginning and
+// the end of them, if maxBytes is 0 it returns logs as is
+func TruncateLogs(logs string, maxBytes int) string {
+	if maxBytes <= 0 || len(logs) <= maxBytes {
+		return logs
+	}
+
+	marker := fmt.Sprintf(truncatedTextFormat, len(logs)-maxBytes)
+	if len(marker) >= maxBytes {
+		return validUTF8Prefix(logs, maxBytes)
+	}
+
+	// keep more of the end as it usually has the error
+	available := maxBytes - len(marker)
+	headSize := available / 3
+	tailSize := available - headSize
+
+	return validUTF8Prefix(logs, headSize) +
+		marker +
+		validUTF8Suffix(logs, tailSize)
+}
+
+// TruncateHead truncates text exceeding maxBytes keeping its beginning, if
+// maxBytes is 0 it returns text as is
+func TruncateHead(text string, maxBytes int) string {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text
+	}
+
+	marker := fmt.Sprintf(truncatedTextFormat, len(text)-maxBytes)
+	if len(marker) >= maxBytes {
+		return validUTF8Prefix(text, maxBytes)
+	}
+	return validUTF8Prefix(text, maxBytes-len(marker)) + marker
+}
+
+// TruncateTail truncates text exceeding maxBytes keeping its end, if
+// maxBytes is 0 it returns text as is
+func TruncateTail(text string, maxBytes int) string {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text
+	}
+
+	marker := fmt.Sprintf(truncatedTextFormat, len(text)-maxBytes)
+	if len(marker) >= maxBytes {
+		return validUTF8Suffix(text, maxBytes)
+	}
+	return marker + validUTF8Suffix(text, maxBytes-len(marker))
+}
+
+// validUTF8Prefix returns prefix of s up to n bytes without cutting a rune
+func validUTF8Prefix(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// validUTF8Suffix returns suffix of s up to n bytes without cutting a rune
+func validUTF8Suffix(s string, n int) string {
+	start := len(s) - n
+	for start < len(s) && !utf8.RuneStart(s[start]) {
+		start++
+	}
+	return s[start:]
+}
+
+func matchesAny(s string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
 func getContainerLogs(
 	c kubernetes.Interface,
 	name string,
@@ -125,6 +402,101 @@ func GetPodEvents(
 		})
 }
 
+// GetPodFailureReason returns reason pod or one of its containers is
+// failing for, it returns empty string if pod is healthy
+func GetPodFailureReason(pod *v1.Pod) string {
+	if pod.Status.Phase == v1.PodFailed {
+		if len(pod.Status.Reason) > 0 {
+			return pod.Status.Reason
+		}
+		return string(v1.PodFailed)
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodScheduled &&
+			cond.Status == v1.ConditionFalse &&
+			cond.Reason == v1.PodReasonUnschedulable {
+			return cond.Reason
+		}
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil &&
+			waiting.Reason != "ContainerCreating" &&
+			waiting.Reason != "PodInitializing" {
+			return waiting.Reason
+		}
+
+		if terminated := status.State.Terminated; terminated != nil &&
+			terminated.ExitCode != 0 &&
+			terminated.ExitCode != 143 {
+			return terminated.Reason
+		}
+	}
+
+	return ""
+}
+
+// GetWorkloadReplicas returns number of ready and desired replicas of
+// workload with given kind and name
+func GetWorkloadReplicas(
+	c kubernetes.Interface,
+	namespace, kind, name string) (ready int32, desired int32, err error) {
+	switch kind {
+	case "Deployment":
+		d, err := c.AppsV1().
+			Deployments(namespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return d.Status.ReadyReplicas, replicasOrDefault(d.Spec.Replicas), nil
+	case "StatefulSet":
+		s, err := c.AppsV1().
+			StatefulSets(namespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return s.Status.ReadyReplicas, replicasOrDefault(s.Spec.Replicas), nil
+	case "ReplicaSet":
+		r, err := c.AppsV1().
+			ReplicaSets(namespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return r.Status.ReadyReplicas, replicasOrDefault(r.Spec.Replicas), nil
+	case "DaemonSet":
+		d, err := c.AppsV1().
+			DaemonSets(namespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return d.Status.NumberReady, d.Status.DesiredNumberScheduled, nil
+	case "Job":
+		j, err := c.BatchV1().
+			Jobs(namespace).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, 0, err
+		}
+		return j.Status.Succeeded, replicasOrDefault(j.Spec.Completions), nil
+	}
+
+	return 0, 0, fmt.Errorf("unsupported workload kind %s", kind)
+}
+
+// replicasOrDefault returns replicas value, or 1 if it's not set as
+// Kubernetes defaults it to 1
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
 // GetNodes gets a list of nodes
 func GetNodes(c kubernetes.Interface) (*v1.NodeList, error) {
 	return c.CoreV1().
@@ -132,7 +504,35 @@ func GetNodes(c kubernetes.Interface) (*v1.NodeList, error) {
 		List(context.TODO(), metav1.ListOptions{})
 }
 
-// // GetNodeSummary gets a list of nodes
+// GetNodeZone returns zone of the node from its topology labels
+func GetNodeZone(node *v1.Node) string {
+	if zone, ok := node.Labels[v1.LabelTopologyZone]; ok {
+		return zone
+	}
+	return node.Labels[v1.LabelFailureDomainBetaZone]
+}
+
+// GetNodeRegion returns region of the node from its topology labels
+func GetNodeRegion(node *v1.Node) string {
+	if region, ok := node.Labels[v1.LabelTopologyRegion]; ok {
+		return region
+	}
+	return node.Labels[v1.LabelFailureDomainBetaRegion]
+}
+
+// FormatNodeConditions returns node conditions in compact form
+// e.g. Ready=True, MemoryPressure=False
+func FormatNodeConditions(conditions []v1.NodeCondition) string {
+	result := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		result = append(
+			result,
+			string(condition.Type)+"="+string(condition.Status))
+	}
+	return strings.Join(result, ", ")
+}
+
+// GetNodeSummary gets a list of nodes
 func GetNodeSummary(c kubernetes.Interface, name string) ([]byte, error) {
 	return c.CoreV1().
 		RESTClient().
@@ -144,6 +544,68 @@ func GetNodeSummary(c kubernetes.Interface, name string) ([]byte, error) {
 		DoRaw(context.TODO())
 }
 
+// PodMetrics is the subset of metrics.k8s.io PodMetrics used by kwatch
+type PodMetrics struct {
+	Containers []ContainerMetrics `json:"containers"`
+}
+
+// ContainerMetrics holds current resource usage of a container
+type ContainerMetrics struct {
+	Name  string          `json:"name"`
+	Usage v1.ResourceList `json:"usage"`
+}
+
+// GetPodMetrics returns current resource usage of pod containers from
+// metrics API
+func GetPodMetrics(
+	c kubernetes.Interface,
+	name,
+	namespace string) (*PodMetrics, error) {
+	// fake clients used by tests have no rest client
+	restClient := c.CoreV1().RESTClient()
+	if rc, ok := restClient.(*rest.RESTClient); ok && rc == nil {
+		return nil, fmt.Errorf("metrics API is not available")
+	}
+
+	raw, err := restClient.
+		Get().
+		AbsPath(
+			"/apis/metrics.k8s.io/v1beta1/namespaces",
+			namespace,
+			"pods",
+			name).
+		DoRaw(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePodMetrics(raw)
+}
+
+// ParsePodMetrics parses PodMetrics response of metrics API
+func ParsePodMetrics(raw []byte) (*PodMetrics, error) {
+	var metrics PodMetrics
+	if err := json.Unmarshal(raw, &metrics); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
+
+// FormatResources returns cpu (in millicores) and memory (in MiB) of
+// resources in compact form e.g. cpu: 250m, memory: 128Mi
+func FormatResources(resources v1.ResourceList) string {
+	result := make([]string, 0, 2)
+	if cpu, ok := resources[v1.ResourceCPU]; ok {
+		result = append(result, fmt.Sprintf("cpu: %dm", cpu.MilliValue()))
+	}
+	if memory, ok := resources[v1.ResourceMemory]; ok {
+		result = append(
+			result,
+			fmt.Sprintf("memory: %dMi", memory.Value()/(1024*1024)))
+	}
+	return strings.Join(result, ", ")
+}
+
 // GetPVNameFromPVC returns the name of persistent volume given a namespace and
 // persistent volume claim name
 func GetPVNameFromPVC(
@@ -181,3 +643,13 @@ func RandomString(n int) string {
 
 	return string(b)
 }
+
+// ContainsFold returns true if items contain value ignoring case
+func ContainsFold(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
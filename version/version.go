@@ -2,6 +2,7 @@ package version
 
 import (
 	"encoding/json"
+	"net/http"
 )
 
 // Version is the current versions of kwatch
@@ -14,15 +15,26 @@ const gitCommitID = "none"
 const buildDate = "unknown"
 
 type Info struct {
-	Version   string
-	GitCommit string
-	BuildDate string
+	Version    string
+	GitCommit  string
+	BuildDate  string
+	ConfigHash string `json:",omitempty"`
 }
 
 func Short() string {
 	return version
 }
 
+// Commit returns git commit id of the release
+func Commit() string {
+	return gitCommitID
+}
+
+// BuildDate returns date of the release
+func BuildDate() string {
+	return buildDate
+}
+
 func Version() string {
 	ver, _ := json.Marshal(Info{
 		Version:   version,
@@ -32,3 +44,17 @@ func Version() string {
 
 	return string(ver)
 }
+
+// Handler returns HTTP handler that responds with build info and hash of
+// loaded config as json
+func Handler(configHash string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Info{
+			Version:    version,
+			GitCommit:  gitCommitID,
+			BuildDate:  buildDate,
+			ConfigHash: configHash,
+		})
+	}
+}
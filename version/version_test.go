@@ -0,0 +1,24 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	rr := httptest.NewRecorder()
+	Handler("abc123")(rr, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	var info Info
+	assert.Nil(json.Unmarshal(rr.Body.Bytes(), &info))
+	assert.Equal(Short(), info.Version)
+	assert.Equal(Commit(), info.GitCommit)
+	assert.Equal(BuildDate(), info.BuildDate)
+	assert.Equal("abc123", info.ConfigHash)
+}
@@ -1,51 +1,57 @@
 package watcher
 
 import (
-	"context"
-
-	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
-	toolsWatch "k8s.io/client-go/tools/watch"
 	"k8s.io/client-go/util/workqueue"
 )
 
-// Start creates an instance of watcher after initialization and runs it
-func Start(
-	client kubernetes.Interface,
-	config *config.Config,
-	handleFunc func(string, *corev1.Pod)) {
-	namespace := metav1.NamespaceAll
-	if len(config.AllowedNamespaces) == 1 {
-		namespace = config.AllowedNamespaces[0]
+// NewWatcher returns new instance of watcher of pods of informer, it has to
+// be created before informer is started
+func NewWatcher(inf *informer.Informer) *Watcher {
+	w := &Watcher{
+		queue: workqueue.New(),
+		pods:  inf.Pods(),
+		inf:   inf,
 	}
 
-	watchFunc :=
-		func(options metav1.ListOptions) (watch.Interface, error) {
-			return client.CoreV1().Pods(namespace).Watch(
-				context.Background(),
-				metav1.ListOptions{},
-			)
-		}
-
-	watcher, _ :=
-		toolsWatch.NewRetryWatcher(
-			"1",
-			&cache.ListWatch{WatchFunc: watchFunc},
-		)
+	err := w.pods.SetWatchErrorHandler(w.handleWatchError)
+	if err != nil {
+		logrus.WithError(err).Error("failed to set pod watch error handler")
+	}
 
-	w := &Watcher{
-		watcher:     watcher,
-		queue:       workqueue.New(),
-		handlerFunc: handleFunc,
+	_, err = w.pods.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.enqueue(watch.Added, obj)
+		},
+		UpdateFunc: w.onUpdate,
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			w.enqueue(watch.Deleted, obj)
+		},
+	})
+	if err != nil {
+		logrus.WithError(err).Error("failed to add pod event handler")
 	}
 
-	stopCh := make(chan struct{})
-	defer close(stopCh)
+	return w
+}
+
+// Start starts informer and passes pod events to handleFunc until stopCh is
+// closed
+func (w *Watcher) Start(
+	handleFunc func(string, *corev1.Pod),
+	stopCh <-chan struct{}) {
+	w.handlerFunc = handleFunc
+
+	if !w.inf.Start(stopCh) {
+		logrus.Error("failed to sync informer caches")
+	}
 
 	w.run(stopCh)
 }
@@ -1,13 +1,17 @@
 package watcher
 
 import (
+	"sync"
 	"time"
 
+	"github.com/abahmed/kwatch/informer"
+	"github.com/abahmed/kwatch/metrics"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	toolsWatch "k8s.io/client-go/tools/watch"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
@@ -17,41 +21,92 @@ type watcherEvent struct {
 }
 
 type Watcher struct {
-	watcher     *toolsWatch.RetryWatcher
 	queue       *workqueue.Type
 	handlerFunc func(string, *corev1.Pod)
+	pods        cache.SharedIndexInformer
+	inf         *informer.Informer
+
+	// failed is true once pod watch failed until it's re-established,
+	// failedVersion is resource version pods were synced at when it failed
+	mu            sync.Mutex
+	failed        bool
+	failedVersion string
 }
 
-// run starts the watcher
-func (w *Watcher) run(stopCh chan struct{}) {
+// Established returns true if pod watch is established with kubernetes, it
+// is once pods are synced and after a watch failure once informer syncs a
+// newer resource version e.g. on relist or bookmark
+func (w *Watcher) Established() bool {
+	if !w.pods.HasSynced() {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed && w.pods.LastSyncResourceVersion() == w.failedVersion {
+		return false
+	}
+	w.failed = false
+	return true
+}
+
+// run starts the watcher, once stopCh is closed it stops processing queued
+// events and waits for the in-flight one
+func (w *Watcher) run(stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
-	defer w.queue.ShutDown()
 
 	logrus.Info("starting pod watcher")
 
-	go wait.Until(w.processEvents, time.Second, stopCh)
-	go wait.Until(w.runWorker, time.Second, stopCh)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait.Until(w.runWorker, time.Second, stopCh)
+	}()
 
 	<-stopCh
+	logrus.Info("stopping pod watcher")
+	w.queue.ShutDown()
+	<-done
 }
 
-func (w *Watcher) processEvents() {
-	if w.watcher == nil {
+// enqueue adds pod event received from informer to queue
+func (w *Watcher) enqueue(eventType watch.EventType, obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		logrus.Warnf("failed to cast event to pod object: %v", obj)
 		return
 	}
 
-	for event := range w.watcher.ResultChan() {
-		pod, ok := event.Object.(*corev1.Pod)
-		if !ok {
-			logrus.Warnf("failed to cast event to pod object: %v", event.Object)
-			continue
-		}
-
-		w.queue.Add(watcherEvent{
-			eventType: string(event.Type),
-			pod:       pod.DeepCopy(),
-		})
+	w.queue.Add(watcherEvent{
+		eventType: string(eventType),
+		pod:       pod.DeepCopy(),
+	})
+}
+
+// onUpdate enqueues modified pod, pods that didn't change since they were
+// last seen are replayed by informer relists and are skipped, so old pod
+// states aren't reported as fresh alerts
+func (w *Watcher) onUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*corev1.Pod)
+	newPod, ok2 := newObj.(*corev1.Pod)
+	if ok && ok2 && oldPod.ResourceVersion == newPod.ResourceVersion {
+		return
 	}
+
+	w.enqueue(watch.Modified, newObj)
+}
+
+// handleWatchError records pod watch failures, informer restarts the watch
+// by itself
+func (w *Watcher) handleWatchError(r *cache.Reflector, err error) {
+	logrus.WithError(err).Warn("pod watch failed, restarting")
+	metrics.WatchRestarts.Inc()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.failed = true
+	w.failedVersion = w.pods.LastSyncResourceVersion()
 }
 
 func (w *Watcher) runWorker() {
@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestEstablished(t *testing.T) {
+	assert := assert.New(t)
+
+	inf := informer.NewInformer(fake.NewSimpleClientset(), &config.Config{})
+	w := NewWatcher(inf)
+	assert.False(w.Established())
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	assert.True(inf.Start(stopCh))
+	assert.True(w.Established())
+
+	// watch isn't established until informer syncs newer resource version
+	w.handleWatchError(nil, errors.New("watch failed"))
+	assert.False(w.Established())
+
+	w.failedVersion = "stale"
+	assert.True(w.Established())
+	assert.False(w.failed)
+}
+
+func TestOnUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	w := &Watcher{queue: workqueue.New()}
+	defer w.queue.ShutDown()
+
+	oldPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", ResourceVersion: "1"},
+	}
+
+	// replayed pod is skipped
+	w.onUpdate(oldPod, oldPod.DeepCopy())
+	assert.Equal(0, w.queue.Len())
+
+	newPod := oldPod.DeepCopy()
+	newPod.ResourceVersion = "2"
+	w.onUpdate(oldPod, newPod)
+	assert.Equal(1, w.queue.Len())
+
+	item, _ := w.queue.Get()
+	ev := item.(watcherEvent)
+	assert.Equal("MODIFIED", ev.eventType)
+	assert.Equal("2", ev.pod.ResourceVersion)
+}
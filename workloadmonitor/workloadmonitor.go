@@ -0,0 +1,197 @@
+package workloadmonitor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/constant"
+	"github.com/abahmed/kwatch/informer"
+	"github.com/abahmed/kwatch/util"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+type WorkloadMonitor struct {
+	client       dynamic.Interface
+	config       *config.Config
+	alertManager *alertmanager.AlertManager
+
+	mu sync.Mutex
+
+	// failures are failing phases or conditions of last notification by
+	// resource and object key, so a failure is notified once until it
+	// changes or recovers. Messages aren't compared, as they often carry
+	// counters or timestamps
+	failures map[schema.GroupVersionResource]map[string]string
+}
+
+// NewWorkloadMonitor returns new instance of workload resource monitor of
+// cluster
+func NewWorkloadMonitor(
+	client dynamic.Interface,
+	config *config.Config,
+	alertManager *alertmanager.AlertManager) *WorkloadMonitor {
+	return &WorkloadMonitor{
+		client:       client,
+		config:       config,
+		alertManager: alertManager,
+		failures: make(
+			map[schema.GroupVersionResource]map[string]string),
+	}
+}
+
+// Start watches configured workload resources in background until stopCh
+// is closed, resources whose CRD isn't installed are retried by informers
+func (m *WorkloadMonitor) Start(stopCh <-chan struct{}) {
+	for i := range m.config.WorkloadResources {
+		resource := &m.config.WorkloadResources[i]
+		go func() {
+			synced := informer.WatchResourceChanges(
+				m.client,
+				getGroupVersionResource(resource),
+				"",
+				stopCh,
+				func(key string, obj *unstructured.Unstructured) {
+					m.Check(resource, key, obj)
+				},
+				func(key string) {
+					m.Forget(resource, key)
+				})
+			if !synced {
+				logrus.WithField("kind", resource.Kind).
+					Error("failed to sync workload resources")
+			}
+		}()
+	}
+}
+
+// Check notifies added or changed object of workload resource with key if
+// it's failing, objects which recovered are forgotten
+func (m *WorkloadMonitor) Check(
+	resource *config.WorkloadResource,
+	key string,
+	obj *unstructured.Unstructured) {
+	gvr := getGroupVersionResource(resource)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	failures, ok := m.failures[gvr]
+	if !ok {
+		failures = make(map[string]string)
+		m.failures[gvr] = failures
+	}
+
+	failure, msg := "", ""
+	if m.config.IsNamespaceWatched(obj.GetNamespace()) {
+		failure, msg = getFailure(resource, obj)
+	}
+	if len(failure) == 0 {
+		delete(failures, key)
+		return
+	}
+
+	if failures[key] == failure {
+		return
+	}
+	failures[key] = failure
+
+	if len(msg) > 0 {
+		failure += ": " + msg
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"kind":      resource.Kind,
+		"namespace": obj.GetNamespace(),
+		"name":      obj.GetName(),
+		"failure":   failure,
+	}).Warn("workload resource is failing")
+
+	m.alertManager.Notify(fmt.Sprintf(
+		constant.WorkloadResourceFailureMsg,
+		resource.Kind,
+		m.describeObject(obj),
+		obj.GetNamespace(),
+		failure))
+}
+
+// Forget forgets deleted object of workload resource with key
+func (m *WorkloadMonitor) Forget(
+	resource *config.WorkloadResource,
+	key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.failures[getGroupVersionResource(resource)], key)
+}
+
+// describeObject returns name of object and cluster if it's known
+func (m *WorkloadMonitor) describeObject(
+	obj *unstructured.Unstructured) string {
+	if len(m.config.App.ClusterName) == 0 {
+		return obj.GetName()
+	}
+	return obj.GetName() + " of cluster " + m.config.App.ClusterName
+}
+
+// getFailure returns failing phase or condition of object with its
+// message, or empty strings if it isn't failing
+func getFailure(
+	resource *config.WorkloadResource,
+	obj *unstructured.Unstructured) (string, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	if len(phase) > 0 && util.ContainsFold(resource.Phases, phase) {
+		msg, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+		return "phase is " + phase, msg
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(
+		obj.Object,
+		"status",
+		"conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		for _, failing := range resource.Conditions {
+			if !strings.EqualFold(failing.Type, conditionType) ||
+				!strings.EqualFold(failing.Status, status) {
+				continue
+			}
+			if len(failing.Reasons) > 0 &&
+				!util.ContainsFold(failing.Reasons, reason) {
+				continue
+			}
+
+			failure := conditionType + " is " + status
+			if len(reason) > 0 {
+				failure += " (" + reason + ")"
+			}
+			msg, _, _ := unstructured.NestedString(condition, "message")
+			return failure, msg
+		}
+	}
+
+	return "", ""
+}
+
+// getGroupVersionResource returns group, version and resource of workload
+// resource
+func getGroupVersionResource(
+	resource *config.WorkloadResource) schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    resource.Group,
+		Version:  resource.Version,
+		Resource: resource.Resource,
+	}
+}
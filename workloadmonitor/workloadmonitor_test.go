@@ -0,0 +1,140 @@
+package workloadmonitor
+
+import (
+	"testing"
+
+	"github.com/abahmed/kwatch/alertmanager"
+	"github.com/abahmed/kwatch/config"
+	"github.com/abahmed/kwatch/event"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type recordingProvider struct {
+	messages []string
+}
+
+func (p *recordingProvider) Name() string {
+	return "recording"
+}
+func (p *recordingProvider) SendEvent(ev *event.Event) error {
+	return nil
+}
+func (p *recordingProvider) SendMessage(msg string) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func newObject(
+	namespace string,
+	phase string,
+	conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "api",
+			"namespace": namespace,
+		},
+		"status": map[string]interface{}{
+			"phase":      phase,
+			"message":    "ProgressDeadlineExceeded",
+			"conditions": conditions,
+		},
+	}}
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	prv := &recordingProvider{}
+	alertManager := &alertmanager.AlertManager{}
+	alertManager.Init(nil, nil)
+	alertManager.AddProvider(prv)
+
+	cfg := &config.Config{
+		ForbiddenNamespaces: []string{"dev"},
+	}
+	cfg.App.ClusterName = "prod"
+	resource := &config.WorkloadResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Kind:     "Rollout",
+		Resource: "rollouts",
+		Phases:   []string{"Degraded"},
+	}
+
+	m := NewWorkloadMonitor(nil, cfg, alertManager)
+	m.Check(resource, "default/api", newObject("default", "Healthy"))
+	m.Check(resource, "dev/api", newObject("dev", "Degraded"))
+	assert.Len(prv.messages, 0)
+
+	// failure is notified once
+	degraded := newObject("default", "Degraded")
+	m.Check(resource, "default/api", degraded)
+	m.Check(resource, "default/api", degraded)
+	assert.Len(prv.messages, 1)
+	assert.Contains(prv.messages[0], "Rollout api of cluster prod")
+	assert.Contains(prv.messages[0], "namespace default")
+	assert.Contains(prv.messages[0],
+		"phase is Degraded: ProgressDeadlineExceeded")
+
+	// changed message of same failure isn't notified again
+	changed := newObject("default", "Degraded")
+	changed.Object["status"].(map[string]interface{})["message"] =
+		"ProgressDeadlineExceeded after 2 attempts"
+	m.Check(resource, "default/api", changed)
+	assert.Len(prv.messages, 1)
+
+	// recovered resource is notified again if it fails
+	m.Check(resource, "default/api", newObject("default", "Healthy"))
+	m.Check(resource, "default/api", degraded)
+	assert.Len(prv.messages, 2)
+
+	// deleted resources are forgotten
+	m.Forget(resource, "default/api")
+	assert.Empty(m.failures[getGroupVersionResource(resource)])
+}
+
+func TestGetFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	resource := &config.WorkloadResource{
+		Conditions: []config.ResourceCondition{
+			{Type: "Ready", Status: "False"},
+			{
+				Type:    "Progressing",
+				Status:  "False",
+				Reasons: []string{"ProgressDeadlineExceeded"},
+			},
+		},
+	}
+
+	failure, _ := getFailure(resource, newObject("default", ""))
+	assert.Empty(failure)
+	failure, _ = getFailure(resource, newObject("default", "",
+		map[string]interface{}{"type": "Ready", "status": "True"},
+		map[string]interface{}{
+			"type":   "Progressing",
+			"status": "False",
+			"reason": "NewReplicaSetAvailable",
+		}))
+	assert.Empty(failure)
+
+	failure, msg := getFailure(resource, newObject("default", "",
+		map[string]interface{}{
+			"type":    "Ready",
+			"status":  "False",
+			"reason":  "BuildFailed",
+			"message": "kustomize build failed",
+		}))
+	assert.Equal("Ready is False (BuildFailed)", failure)
+	assert.Equal("kustomize build failed", msg)
+
+	failure, msg = getFailure(resource, newObject("default", "",
+		map[string]interface{}{
+			"type":   "Progressing",
+			"status": "False",
+			"reason": "ProgressDeadlineExceeded",
+		}))
+	assert.Equal("Progressing is False (ProgressDeadlineExceeded)", failure)
+	assert.Empty(msg)
+}